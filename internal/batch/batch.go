@@ -0,0 +1,438 @@
+// Package batch provides the queueing and batching engine shared by the
+// clients that ingest telemetry data into New Relic in batch mode.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/logging"
+)
+
+const (
+	DefaultWorkers     = 1
+	DefaultSize        = 900
+	DefaultTimeout     = 60 * time.Second
+	DefaultMaxInFlight = 4
+	DefaultRetries     = 0
+	DefaultRetryWait   = 1 * time.Second
+)
+
+var (
+	// ErrShutdown is returned when items are queued after Shutdown has been called.
+	ErrShutdown = errors.New("batch: the batcher has been shut down")
+)
+
+// SendFunc sends a single batch of items.
+type SendFunc func(ctx context.Context, items []interface{}) error
+
+// PartialError is returned by a SendFunc when only some of the items of a
+// batch could not be sent.  Failed items are retried, while rejected items
+// are dropped straight away since sending them again cannot succeed.
+type PartialError struct {
+	// Failed holds the items that may succeed if sent again.
+	Failed []interface{}
+
+	// Err is the error returned when sending the failed items.
+	Err error
+
+	// Rejected holds the items that can never be sent.
+	Rejected []Rejection
+}
+
+// Rejection is an item that can never be sent, along with the reason.
+type Rejection struct {
+	Item interface{}
+	Err  error
+}
+
+func (e *PartialError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("batch: %d items rejected", len(e.Rejected))
+	}
+
+	return fmt.Sprintf("batch: %d items failed, %d items rejected: %s", len(e.Failed), len(e.Rejected), e.Err)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// FailureHandler is called with the items of a batch that could not be sent,
+// along with the error returned by the final attempt.
+type FailureHandler func(items []interface{}, err error)
+
+// Config holds the settings of a Batcher.
+type Config struct {
+	// Workers is the number of background workers reading from the queue.
+	Workers int
+
+	// Size is the number of items that triggers sending a batch.
+	// It is also the capacity of the queue.
+	Size int
+
+	// Timeout is the maximum amount of time items are queued before
+	// the queue is flushed.
+	Timeout time.Duration
+
+	// MaxInFlight bounds the number of batches being sent at once.
+	MaxInFlight int
+
+	// Retries is the number of times a failed batch is sent again
+	// before it is dropped.
+	Retries int
+
+	// RetryWait is the wait before the first retry, doubling on each
+	// subsequent attempt.
+	RetryWait time.Duration
+
+	// OnFailure is called for each batch that is dropped.
+	OnFailure FailureHandler
+}
+
+// Stats holds the counters of a Batcher.
+type Stats struct {
+	// Queued is the number of items accepted into the queue.
+	Queued int64
+
+	// Sent is the number of items successfully sent.
+	Sent int64
+
+	// Dropped is the number of items that could not be sent.
+	Dropped int64
+
+	// Retried is the number of items sent again after a failed attempt.
+	Retried int64
+}
+
+// Batcher accepts items, groups them into batches and sends them in the
+// background, bounding the number of batches in flight.
+type Batcher struct {
+	config Config
+	logger logging.Logger
+	send   SendFunc
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	queue    chan interface{}
+	flushers []chan bool
+	timer    *time.Timer
+
+	// stopping unblocks pending Enqueue calls, drain tells the workers
+	// to send what is left in the queue and exit.
+	mu       sync.RWMutex
+	closed   bool
+	stopping chan struct{}
+	stopOnce sync.Once
+	drain    chan struct{}
+
+	inFlight chan struct{}
+	workers  sync.WaitGroup
+	sends    sync.WaitGroup
+
+	queued  int64
+	sent    int64
+	dropped int64
+	retried int64
+}
+
+// New creates a Batcher that uses send to deliver batches.  Zero values in
+// the configuration are replaced with their defaults.
+func New(send SendFunc, cfg Config, logger logging.Logger) *Batcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultSize
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = DefaultMaxInFlight
+	}
+
+	if cfg.Retries < 0 {
+		cfg.Retries = DefaultRetries
+	}
+
+	if cfg.RetryWait <= 0 {
+		cfg.RetryWait = DefaultRetryWait
+	}
+
+	return &Batcher{
+		config:   cfg,
+		logger:   logger,
+		send:     send,
+		queue:    make(chan interface{}, cfg.Size),
+		flushers: make([]chan bool, cfg.Workers),
+		stopping: make(chan struct{}),
+		drain:    make(chan struct{}),
+		inFlight: make(chan struct{}, cfg.MaxInFlight),
+	}
+}
+
+// Start spins up the workers and the flush timer.  Canceling ctx stops
+// the Batcher immediately, dropping anything that has not been sent;
+// use Shutdown to stop gracefully.
+func (b *Batcher) Start(ctx context.Context) {
+	b.ctx, b.cancel = context.WithCancel(ctx)
+	b.timer = time.NewTimer(b.config.Timeout)
+
+	// Handle timer based flushing
+	go b.watchdog()
+
+	// Spin up some workers
+	for x := range b.flushers {
+		b.flushers[x] = make(chan bool, 1)
+		b.workers.Add(1)
+
+		go func(id int) {
+			defer b.workers.Done()
+
+			b.worker(id)
+		}(x)
+	}
+}
+
+// Enqueue adds an item to the queue.  It blocks until the item is queued,
+// ctx is done, or the Batcher is shut down.
+func (b *Batcher) Enqueue(ctx context.Context, item interface{}) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrShutdown
+	}
+
+	select {
+	case b.queue <- item:
+		atomic.AddInt64(&b.queued, 1)
+		return nil
+	case <-b.stopping:
+		return ErrShutdown
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush asks every worker to send the items it is holding.
+func (b *Batcher) Flush() {
+	b.logger.Debug("flushing batch queues")
+
+	for x := range b.flushers {
+		select {
+		case b.flushers[x] <- true:
+		default:
+			// A flush is already pending for this worker
+		}
+	}
+}
+
+// Shutdown stops accepting items, sends everything that is queued and waits
+// for all batches in flight.  If ctx is done first, sends still in flight
+// are canceled and the context's error is returned.
+func (b *Batcher) Shutdown(ctx context.Context) error {
+	// Pending Enqueue calls hold the read lock until they return, so they
+	// are unblocked before the write lock is taken.
+	b.stopOnce.Do(func() { close(b.stopping) })
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrShutdown
+	}
+
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.drain)
+
+	done := make(chan struct{})
+	go func() {
+		b.workers.Wait()
+		b.sends.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.logger.Warn("batch shutdown did not complete, canceling sends in flight")
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the Batcher's counters.
+func (b *Batcher) Stats() Stats {
+	return Stats{
+		Queued:  atomic.LoadInt64(&b.queued),
+		Sent:    atomic.LoadInt64(&b.sent),
+		Dropped: atomic.LoadInt64(&b.dropped),
+		Retried: atomic.LoadInt64(&b.retried),
+	}
+}
+
+//
+// worker reads items from the queue until a threshold is passed,
+// then hands the items it has read off to be sent.
+//
+func (b *Batcher) worker(id int) {
+	buf := make([]interface{}, 0, b.config.Size)
+
+	for {
+		select {
+		case item := <-b.queue:
+			buf = append(buf, item)
+			if len(buf) >= b.config.Size {
+				buf = b.dispatch(buf)
+			}
+		case <-b.flushers[id]:
+			if len(buf) > 0 {
+				buf = b.dispatch(buf)
+			}
+		case <-b.drain:
+			b.drainQueue(buf)
+
+			b.logger.Trace(fmt.Sprintf("batch worker[%d]: exiting after draining the queue", id))
+			return
+		case <-b.ctx.Done():
+			atomic.AddInt64(&b.dropped, int64(len(buf)))
+
+			b.logger.Trace(fmt.Sprintf("batch worker[%d]: exiting per context Done", id))
+			return
+		}
+	}
+}
+
+// drainQueue sends the buffered items along with everything left in the queue.
+func (b *Batcher) drainQueue(buf []interface{}) {
+	for {
+		select {
+		case item := <-b.queue:
+			buf = append(buf, item)
+			if len(buf) >= b.config.Size {
+				buf = b.dispatch(buf)
+			}
+		default:
+			if len(buf) > 0 {
+				b.dispatch(buf)
+			}
+
+			return
+		}
+	}
+}
+
+//
+// watchdog has a Timer that will flush the queues once
+// it has expired.
+//
+func (b *Batcher) watchdog() {
+	for {
+		select {
+		case <-b.timer.C:
+			b.logger.Debug("Timeout expired, flushing queued items")
+			b.Flush()
+			b.timer.Reset(b.config.Timeout)
+		case <-b.ctx.Done():
+			b.timer.Stop()
+			b.logger.Trace("watchdog exiting: context finished")
+			return
+		}
+	}
+}
+
+// dispatch sends a copy of the buffer in the background once a send slot
+// is available, and returns the emptied buffer for reuse.
+func (b *Batcher) dispatch(buf []interface{}) []interface{} {
+	items := make([]interface{}, len(buf))
+	copy(items, buf)
+
+	select {
+	case b.inFlight <- struct{}{}:
+	case <-b.ctx.Done():
+		b.fail(items, b.ctx.Err())
+		return buf[:0]
+	}
+
+	b.sends.Add(1)
+
+	go func() {
+		defer func() {
+			<-b.inFlight
+			b.sends.Done()
+		}()
+
+		b.sendWithRetries(items)
+	}()
+
+	return buf[:0]
+}
+
+func (b *Batcher) sendWithRetries(items []interface{}) {
+	wait := b.config.RetryWait
+
+	for attempt := 0; ; attempt++ {
+		err := b.send(b.ctx, items)
+		if err == nil {
+			atomic.AddInt64(&b.sent, int64(len(items)))
+			return
+		}
+
+		if partial, ok := err.(*PartialError); ok {
+			atomic.AddInt64(&b.sent, int64(len(items)-len(partial.Failed)-len(partial.Rejected)))
+
+			for _, r := range partial.Rejected {
+				b.fail([]interface{}{r.Item}, r.Err)
+			}
+
+			if len(partial.Failed) == 0 {
+				return
+			}
+
+			items, err = partial.Failed, partial.Err
+		}
+
+		if attempt >= b.config.Retries || b.ctx.Err() != nil {
+			b.fail(items, err)
+			return
+		}
+
+		b.logger.Debug(fmt.Sprintf("failed to send batch, retrying (attempt %d)", attempt+1), "error", err.Error())
+		atomic.AddInt64(&b.retried, int64(len(items)))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-b.ctx.Done():
+			timer.Stop()
+			b.fail(items, b.ctx.Err())
+			return
+		}
+
+		wait *= 2
+	}
+}
+
+func (b *Batcher) fail(items []interface{}, err error) {
+	atomic.AddInt64(&b.dropped, int64(len(items)))
+
+	if b.config.OnFailure != nil {
+		b.config.OnFailure(items, err)
+		return
+	}
+
+	b.logger.Error(fmt.Sprintf("failed to send batch of %d items", len(items)), "error", err.Error())
+}
@@ -0,0 +1,307 @@
+// +build unit
+
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/internal/logging"
+)
+
+// recorder collects the batches handed to a SendFunc.
+type recorder struct {
+	sync.Mutex
+	batches [][]interface{}
+}
+
+func (r *recorder) send(ctx context.Context, items []interface{}) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.batches = append(r.batches, items)
+
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.Lock()
+	defer r.Unlock()
+
+	n := 0
+	for _, b := range r.batches {
+		n += len(b)
+	}
+
+	return n
+}
+
+func newTestBatcher(t *testing.T, send SendFunc, cfg Config) *Batcher {
+	b := New(send, cfg, logging.NewStructuredLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	b.Start(ctx)
+
+	return b
+}
+
+func TestBatcher_sizeTrigger(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	b := newTestBatcher(t, r.send, Config{Size: 2})
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, b.Enqueue(context.Background(), i))
+	}
+
+	assert.Eventually(t, func() bool { return r.count() == 4 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, Stats{Queued: 4, Sent: 4}, b.Stats())
+}
+
+func TestBatcher_flush(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	b := newTestBatcher(t, r.send, Config{Size: 100})
+
+	require.NoError(t, b.Enqueue(context.Background(), "one"))
+
+	// Give the worker a chance to pick up the item before flushing
+	assert.Eventually(t, func() bool { return len(b.queue) == 0 }, 5*time.Second, 10*time.Millisecond)
+	b.Flush()
+
+	assert.Eventually(t, func() bool { return r.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBatcher_timeout(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	b := newTestBatcher(t, r.send, Config{Size: 100, Timeout: 50 * time.Millisecond})
+
+	require.NoError(t, b.Enqueue(context.Background(), "one"))
+
+	assert.Eventually(t, func() bool { return r.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBatcher_shutdownDrainsQueue(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	b := newTestBatcher(t, r.send, Config{Size: 10, Workers: 2})
+
+	for i := 0; i < 25; i++ {
+		require.NoError(t, b.Enqueue(context.Background(), i))
+	}
+
+	require.NoError(t, b.Shutdown(context.Background()))
+	assert.Equal(t, 25, r.count())
+	assert.Equal(t, Stats{Queued: 25, Sent: 25}, b.Stats())
+
+	// No more items are accepted
+	assert.Equal(t, ErrShutdown, b.Enqueue(context.Background(), "late"))
+	assert.Equal(t, ErrShutdown, b.Shutdown(context.Background()))
+}
+
+func TestBatcher_shutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	send := func(ctx context.Context, items []interface{}) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	b := newTestBatcher(t, send, Config{Size: 1})
+	require.NoError(t, b.Enqueue(context.Background(), "stuck"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := b.Shutdown(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// The send in flight is canceled and the item counted as dropped
+	assert.Eventually(t, func() bool { return b.Stats().Dropped == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBatcher_shutdownWhileEnqueueBlocked(t *testing.T) {
+	t.Parallel()
+
+	send := func(ctx context.Context, items []interface{}) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	// The first item is stuck in flight, the second waits for a send slot
+	// and the third fills the queue.
+	b := newTestBatcher(t, send, Config{Size: 1, MaxInFlight: 1})
+	for _, item := range []string{"one", "two", "three"} {
+		require.NoError(t, b.Enqueue(context.Background(), item))
+	}
+
+	enqueued := make(chan error, 1)
+	go func() {
+		enqueued <- b.Enqueue(context.Background(), "blocked")
+	}()
+
+	select {
+	case err := <-enqueued:
+		t.Fatalf("enqueue returned before shutdown: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- b.Shutdown(ctx)
+	}()
+
+	select {
+	case err := <-shutdown:
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not honor its context")
+	}
+
+	assert.Equal(t, ErrShutdown, <-enqueued)
+}
+
+func TestBatcher_failureHandler(t *testing.T) {
+	t.Parallel()
+
+	sendErr := errors.New("send failed")
+	attempts := int64(0)
+	send := func(ctx context.Context, items []interface{}) error {
+		atomic.AddInt64(&attempts, 1)
+		return sendErr
+	}
+
+	failed := make(chan []interface{}, 1)
+	b := newTestBatcher(t, send, Config{
+		Size:      2,
+		Retries:   2,
+		RetryWait: time.Millisecond,
+		OnFailure: func(items []interface{}, err error) {
+			assert.Equal(t, sendErr, err)
+			failed <- items
+		},
+	})
+
+	require.NoError(t, b.Enqueue(context.Background(), "one"))
+	require.NoError(t, b.Enqueue(context.Background(), "two"))
+
+	select {
+	case items := <-failed:
+		assert.Equal(t, []interface{}{"one", "two"}, items)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the failed batch")
+	}
+
+	assert.Equal(t, int64(3), atomic.LoadInt64(&attempts))
+	assert.Equal(t, Stats{Queued: 2, Dropped: 2, Retried: 4}, b.Stats())
+}
+
+func TestBatcher_partialError(t *testing.T) {
+	t.Parallel()
+
+	sendErr := errors.New("send failed")
+	tooLarge := errors.New("too large")
+	attempts := [][]interface{}{}
+	send := func(ctx context.Context, items []interface{}) error {
+		attempts = append(attempts, items)
+
+		if len(attempts) == 1 {
+			return &PartialError{
+				Failed:   []interface{}{"two"},
+				Err:      sendErr,
+				Rejected: []Rejection{{Item: "three", Err: tooLarge}},
+			}
+		}
+
+		return nil
+	}
+
+	rejected := make(chan error, 1)
+	b := newTestBatcher(t, send, Config{
+		Size:      3,
+		Retries:   1,
+		RetryWait: time.Millisecond,
+		OnFailure: func(items []interface{}, err error) {
+			assert.Equal(t, []interface{}{"three"}, items)
+			rejected <- err
+		},
+	})
+
+	require.NoError(t, b.Enqueue(context.Background(), "one"))
+	require.NoError(t, b.Enqueue(context.Background(), "two"))
+	require.NoError(t, b.Enqueue(context.Background(), "three"))
+	require.NoError(t, b.Shutdown(context.Background()))
+
+	assert.Equal(t, tooLarge, <-rejected)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, []interface{}{"two"}, attempts[1])
+	assert.Equal(t, Stats{Queued: 3, Sent: 2, Dropped: 1, Retried: 1}, b.Stats())
+}
+
+func TestBatcher_maxInFlight(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	current := int64(0)
+	peak := int64(0)
+
+	send := func(ctx context.Context, items []interface{}) error {
+		n := atomic.AddInt64(&current, 1)
+		defer atomic.AddInt64(&current, -1)
+
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+
+		<-release
+		return nil
+	}
+
+	b := newTestBatcher(t, send, Config{Size: 1, MaxInFlight: 2})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Enqueue(context.Background(), i))
+	}
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt64(&current) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	close(release)
+	require.NoError(t, b.Shutdown(context.Background()))
+
+	assert.Equal(t, int64(2), atomic.LoadInt64(&peak))
+	assert.Equal(t, int64(3), b.Stats().Sent)
+}
+
+func TestBatcher_enqueueContext(t *testing.T) {
+	t.Parallel()
+
+	// A queue of one item that is never consumed
+	b := New(func(ctx context.Context, items []interface{}) error { return nil }, Config{Size: 1}, logging.NewStructuredLogger())
+	require.NoError(t, b.Enqueue(context.Background(), "one"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := b.Enqueue(ctx, "two")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
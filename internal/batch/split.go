@@ -0,0 +1,220 @@
+package batch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+// SizeLimits holds the payload size limits of an ingest API.
+type SizeLimits struct {
+	// MaxPayloadSize is the maximum size in bytes of an uncompressed payload.
+	MaxPayloadSize int
+
+	// MaxCompressedPayloadSize is the maximum size in bytes of a gzipped
+	// payload.  Zero disables the check.
+	MaxCompressedPayloadSize int
+}
+
+// PostFunc posts a payload holding a JSON array of encoded items.
+type PostFunc func(ctx context.Context, payload []byte) error
+
+// SendSized posts items to an ingest API in as many requests as needed to stay
+// within its size limits.  Each item is posted as its JSON encoding, found at
+// the same index in encoded.  A payload rejected by the API as too large is
+// split in half and posted again.
+//
+// It returns nil when every item was sent, or a *PartialError holding the
+// items that failed and those too large to ever be sent.
+func SendSized(ctx context.Context, items []interface{}, encoded [][]byte, limits SizeLimits, post PostFunc) error {
+	s := sizedSender{
+		items:   items,
+		encoded: encoded,
+		limits:  limits,
+		post:    post,
+	}
+
+	chunk := []int{}
+	size := 2 // Brackets of the JSON array
+
+	for i, e := range encoded {
+		if len(e)+2 > limits.MaxPayloadSize {
+			s.reject(i, nrErrors.NewPayloadTooLarge(len(e), limits.MaxPayloadSize))
+			continue
+		}
+
+		if len(chunk) > 0 && size+1+len(e) > limits.MaxPayloadSize {
+			s.send(ctx, chunk)
+			chunk = []int{}
+			size = 2
+		}
+
+		if len(chunk) > 0 {
+			size++ // Separating comma
+		}
+
+		chunk = append(chunk, i)
+		size += len(e)
+	}
+
+	if len(chunk) > 0 {
+		s.send(ctx, chunk)
+	}
+
+	if len(s.partial.Failed) == 0 && len(s.partial.Rejected) == 0 {
+		return nil
+	}
+
+	return &s.partial
+}
+
+// Part is the result of sending a part of a batch: its items and the error
+// returned, if any.
+type Part struct {
+	Items []interface{}
+	Err   error
+}
+
+// MergePartialErrors combines the results of sending parts of a batch into
+// a single *PartialError, or nil when every part was sent.  Errors other than
+// *PartialError fail every item of their part.
+func MergePartialErrors(parts ...Part) error {
+	merged := &PartialError{}
+
+	for _, part := range parts {
+		if part.Err == nil {
+			continue
+		}
+
+		partial, ok := part.Err.(*PartialError)
+		if !ok {
+			partial = &PartialError{Failed: part.Items, Err: part.Err}
+		}
+
+		merged.Failed = append(merged.Failed, partial.Failed...)
+		merged.Rejected = append(merged.Rejected, partial.Rejected...)
+
+		if partial.Err != nil {
+			merged.Err = partial.Err
+		}
+	}
+
+	if len(merged.Failed) == 0 && len(merged.Rejected) == 0 {
+		return nil
+	}
+
+	return merged
+}
+
+type sizedSender struct {
+	items   []interface{}
+	encoded [][]byte
+	limits  SizeLimits
+	post    PostFunc
+	partial PartialError
+}
+
+// send posts the items at the given indexes as one payload, halving it as
+// long as it is too large.
+func (s *sizedSender) send(ctx context.Context, chunk []int) {
+	payload := s.payload(chunk)
+
+	if s.limits.MaxCompressedPayloadSize > 0 {
+		compressed, err := compressedSize(payload)
+		if err != nil {
+			s.failed(chunk, err)
+			return
+		}
+
+		if compressed > s.limits.MaxCompressedPayloadSize {
+			if len(chunk) == 1 {
+				s.reject(chunk[0], nrErrors.NewPayloadTooLarge(compressed, s.limits.MaxCompressedPayloadSize))
+				return
+			}
+
+			s.halve(ctx, chunk)
+			return
+		}
+	}
+
+	err := s.post(ctx, payload)
+	if err == nil {
+		return
+	}
+
+	if isTooLarge(err) {
+		if len(chunk) == 1 {
+			s.reject(chunk[0], nrErrors.NewPayloadTooLarge(len(payload), s.limits.MaxPayloadSize))
+			return
+		}
+
+		s.halve(ctx, chunk)
+		return
+	}
+
+	s.failed(chunk, err)
+}
+
+func (s *sizedSender) halve(ctx context.Context, chunk []int) {
+	half := len(chunk) / 2
+
+	s.send(ctx, chunk[:half])
+	s.send(ctx, chunk[half:])
+}
+
+func (s *sizedSender) payload(chunk []int) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("[")
+	for n, i := range chunk {
+		if n > 0 {
+			buf.WriteString(",")
+		}
+		buf.Write(s.encoded[i])
+	}
+	buf.WriteString("]")
+
+	return buf.Bytes()
+}
+
+func (s *sizedSender) failed(chunk []int, err error) {
+	for _, i := range chunk {
+		s.partial.Failed = append(s.partial.Failed, s.items[i])
+	}
+
+	s.partial.Err = err
+}
+
+func (s *sizedSender) reject(i int, err error) {
+	s.partial.Rejected = append(s.partial.Rejected, Rejection{
+		Item: s.items[i],
+		Err:  err,
+	})
+}
+
+func compressedSize(payload []byte) (int, error) {
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+
+	if _, err := writer.Write(payload); err != nil {
+		return 0, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+
+	return buf.Len(), nil
+}
+
+// isTooLarge reports whether the API rejected a payload for its size.
+func isTooLarge(err error) bool {
+	var statusErr *nrErrors.UnexpectedStatusCode
+
+	return errors.As(err, &statusErr) && statusErr.StatusCode() == http.StatusRequestEntityTooLarge
+}
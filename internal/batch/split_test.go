@@ -0,0 +1,201 @@
+// +build unit
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+// postRecorder collects the payloads handed to a PostFunc.
+type postRecorder struct {
+	payloads [][]interface{}
+	err      func(payload []byte) error
+}
+
+func (p *postRecorder) post(ctx context.Context, payload []byte) error {
+	if p.err != nil {
+		if err := p.err(payload); err != nil {
+			return err
+		}
+	}
+
+	items := []interface{}{}
+	if err := json.Unmarshal(payload, &items); err != nil {
+		return err
+	}
+
+	p.payloads = append(p.payloads, items)
+
+	return nil
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func testItems(sizes ...int) ([]interface{}, [][]byte) {
+	items := make([]interface{}, len(sizes))
+	encoded := make([][]byte, len(sizes))
+
+	for i, size := range sizes {
+		// A JSON string of the given size: quotes, index and random
+		// padding that does not compress well
+		rnd := rand.New(rand.NewSource(int64(i)))
+		padding := make([]byte, size-3)
+		for j := range padding {
+			padding[j] = letters[rnd.Intn(len(letters))]
+		}
+
+		s := fmt.Sprintf("%d%s", i, padding)
+		items[i] = s
+		encoded[i] = []byte(fmt.Sprintf("%q", s))
+	}
+
+	return items, encoded
+}
+
+func TestSendSized_splitsOnSize(t *testing.T) {
+	t.Parallel()
+
+	p := &postRecorder{}
+	items, encoded := testItems(10, 10, 10, 10, 10)
+
+	// Two items per payload: 2 + 10 + 1 + 10 = 23
+	err := SendSized(context.Background(), items, encoded, SizeLimits{MaxPayloadSize: 25}, p.post)
+	require.NoError(t, err)
+
+	require.Len(t, p.payloads, 3)
+	assert.Len(t, p.payloads[0], 2)
+	assert.Len(t, p.payloads[1], 2)
+	assert.Len(t, p.payloads[2], 1)
+}
+
+func TestSendSized_rejectsOversizeItem(t *testing.T) {
+	t.Parallel()
+
+	p := &postRecorder{}
+	items, encoded := testItems(10, 50, 10)
+
+	err := SendSized(context.Background(), items, encoded, SizeLimits{MaxPayloadSize: 25}, p.post)
+
+	var partial *PartialError
+	require.True(t, errors.As(err, &partial))
+	assert.Empty(t, partial.Failed)
+	require.Len(t, partial.Rejected, 1)
+	assert.Equal(t, items[1], partial.Rejected[0].Item)
+
+	var tooLarge *nrErrors.PayloadTooLarge
+	require.True(t, errors.As(partial.Rejected[0].Err, &tooLarge))
+	assert.Equal(t, 50, tooLarge.Size())
+	assert.Equal(t, 25, tooLarge.Limit())
+
+	require.Len(t, p.payloads, 1)
+	assert.Equal(t, []interface{}{items[0], items[2]}, p.payloads[0])
+}
+
+func TestSendSized_splitsOnCompressedSize(t *testing.T) {
+	t.Parallel()
+
+	p := &postRecorder{}
+	items, encoded := testItems(100, 100, 100, 100)
+
+	limits := SizeLimits{
+		MaxPayloadSize:           1000,
+		MaxCompressedPayloadSize: 60,
+	}
+
+	err := SendSized(context.Background(), items, encoded, limits, p.post)
+
+	// Even alone, each item compresses to more than the limit
+	var partial *PartialError
+	require.True(t, errors.As(err, &partial))
+	assert.Len(t, partial.Rejected, 4)
+	assert.Empty(t, p.payloads)
+
+	// Each item fits alone, but not two together
+	limits.MaxCompressedPayloadSize = 150
+	p = &postRecorder{}
+
+	err = SendSized(context.Background(), items, encoded, limits, p.post)
+	require.NoError(t, err)
+	assert.Len(t, p.payloads, 4)
+}
+
+func TestSendSized_halvesOnTooLarge(t *testing.T) {
+	t.Parallel()
+
+	p := &postRecorder{
+		err: func(payload []byte) error {
+			if len(payload) > 30 {
+				return nrErrors.NewUnexpectedStatusCode(http.StatusRequestEntityTooLarge, "")
+			}
+
+			return nil
+		},
+	}
+	items, encoded := testItems(10, 10, 10, 10)
+
+	err := SendSized(context.Background(), items, encoded, SizeLimits{MaxPayloadSize: 100}, p.post)
+	require.NoError(t, err)
+
+	require.Len(t, p.payloads, 2)
+	assert.Len(t, p.payloads[0], 2)
+	assert.Len(t, p.payloads[1], 2)
+}
+
+func TestSendSized_failedItems(t *testing.T) {
+	t.Parallel()
+
+	sendErr := errors.New("send failed")
+	calls := 0
+	p := &postRecorder{
+		err: func(payload []byte) error {
+			calls++
+			if calls == 2 {
+				return sendErr
+			}
+
+			return nil
+		},
+	}
+	items, encoded := testItems(10, 10, 10)
+
+	err := SendSized(context.Background(), items, encoded, SizeLimits{MaxPayloadSize: 15}, p.post)
+
+	var partial *PartialError
+	require.True(t, errors.As(err, &partial))
+	assert.Equal(t, []interface{}{items[1]}, partial.Failed)
+	assert.Equal(t, sendErr, partial.Err)
+	assert.Empty(t, partial.Rejected)
+	assert.Len(t, p.payloads, 2)
+}
+
+func TestMergePartialErrors(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, MergePartialErrors(Part{Items: []interface{}{"a"}}))
+
+	rejection := Rejection{Item: "b", Err: errors.New("too large")}
+	failure := errors.New("unavailable")
+
+	err := MergePartialErrors(
+		Part{Items: []interface{}{"a", "b"}, Err: &PartialError{Rejected: []Rejection{rejection}}},
+		Part{Items: []interface{}{"c", "d"}, Err: failure},
+		Part{Items: []interface{}{"e"}},
+	)
+
+	var partial *PartialError
+	require.True(t, errors.As(err, &partial))
+	assert.Equal(t, []interface{}{"c", "d"}, partial.Failed)
+	assert.Equal(t, []Rejection{rejection}, partial.Rejected)
+	assert.Equal(t, failure, partial.Err)
+}
@@ -50,6 +50,8 @@ func (a *LicenseKeyAuthorizer) AuthorizeRequest(r *Request, c *config.Config) {
 	r.SetHeader("X-License-Key", c.LicenseKey)
 }
 
+// LogsInsertKeyAuthorizer authorizes sending data to the ingest APIs taking
+// an insert key as API key, which are the Log, Metric and Trace APIs.
 type LogsInsertKeyAuthorizer struct{}
 
 func (a *LogsInsertKeyAuthorizer) AuthorizeRequest(r *Request, c *config.Config) {
@@ -25,6 +25,8 @@ const (
 	defaultServiceName                     = "newrelic-client-go"
 	defaultTimeout                         = time.Second * 30
 	defaultRetryMax                        = 3
+	defaultRetryWaitMin                    = time.Second * 1
+	defaultRetryWaitMax                    = time.Second * 30
 )
 
 var (
@@ -61,9 +63,7 @@ func NewClient(cfg config.Config) Client {
 	}
 
 	if cfg.HTTPTransport != nil {
-		if transport, ok := (cfg.HTTPTransport).(*http.Transport); ok {
-			c.Transport = transport
-		}
+		c.Transport = cfg.HTTPTransport
 	} else {
 		c.Transport = http.DefaultTransport
 	}
@@ -82,7 +82,22 @@ func NewClient(cfg config.Config) Client {
 	r := retryablehttp.NewClient()
 	r.HTTPClient = &c
 	r.RetryMax = defaultRetryMax
-	r.CheckRetry = RetryPolicy
+	r.RetryWaitMin = defaultRetryWaitMin
+	r.RetryWaitMax = defaultRetryWaitMax
+	r.CheckRetry = newRetryPolicy(cfg)
+	r.Backoff = newBackoff(cfg.RetryJitter)
+
+	if cfg.RetryMax != nil {
+		r.RetryMax = *cfg.RetryMax
+	}
+
+	if cfg.RetryWaitMin != nil {
+		r.RetryWaitMin = *cfg.RetryWaitMin
+	}
+
+	if cfg.RetryWaitMax != nil {
+		r.RetryWaitMax = *cfg.RetryWaitMax
+	}
 
 	// Disable logging in go-retryablehttp since we are logging requests directly here
 	r.Logger = nil
@@ -281,53 +296,62 @@ func logNice(body string) string {
 	return newBody
 }
 
-// obfuscate receives a string, and replaces everything after the first 8
-// characters with an asterisk before returning the result.
-func obfuscate(input string) string {
-	result := make([]string, len(input))
-	parts := strings.Split(input, "")
-
-	for i, x := range parts {
-		if i < 8 {
-			result[i] = x
-		} else {
-			result[i] = "*"
-		}
+func logCleanHeaderMarshalJSON(header http.Header) ([]byte, error) {
+	return json.Marshal(logging.CleanHeader(header))
+}
+
+// Do initiates an HTTP request as configured by the passed Request struct.
+func (c *Client) Do(req *Request) (*http.Response, error) {
+	r, err := req.makeRequest()
+	if err != nil {
+		return nil, err
 	}
 
-	return strings.Join(result, "")
-}
+	for _, m := range c.config.Middleware {
+		if err = m.BeforeRequest(r.Request); err != nil {
+			c.onError(r.Request, err)
+			return nil, err
+		}
+	}
 
-func logCleanHeaderMarshalJSON(header http.Header) ([]byte, error) {
-	h := http.Header{}
+	start := time.Now()
+	resp, body, errorValue, err := c.do(req)
 
-	for k, values := range header {
-		if _, ok := h[k]; ok {
-			h[k] = make([]string, len(values))
+	if resp != nil && len(c.config.Middleware) > 0 {
+		response := &config.Response{
+			Request:  r.Request,
+			Response: resp,
+			Body:     body,
+			Duration: time.Since(start),
 		}
 
-		switch k {
-		case "Api-Key", "X-Api-Key", "X-Insert-Key":
-			newValues := []string{}
-			for _, v := range values {
-				newValues = append(newValues, obfuscate(v))
-			}
+		if v, ok := errorValue.(interface{ graphQLErrors() []GraphQLError }); ok {
+			response.GraphQLErrors = v.graphQLErrors()
+		}
 
-			if len(newValues) > 0 {
-				h[k] = newValues
-			} else {
-				h[k] = values
-			}
-		default:
-			h[k] = values
+		for i := len(c.config.Middleware) - 1; i >= 0; i-- {
+			c.config.Middleware[i].AfterResponse(response)
 		}
 	}
 
-	return json.Marshal(h)
+	if err != nil {
+		c.onError(r.Request, err)
+		return nil, err
+	}
+
+	return resp, nil
 }
 
-// Do initiates an HTTP request as configured by the passed Request struct.
-func (c *Client) Do(req *Request) (*http.Response, error) {
+// onError hands the error of a failed request to the middleware.
+func (c *Client) onError(req *http.Request, err error) {
+	for i := len(c.config.Middleware) - 1; i >= 0; i-- {
+		c.config.Middleware[i].OnError(req, err)
+	}
+}
+
+// do sends the request, retrying as required, and returns the last response
+// received along with its body and error value, even when it failed.
+func (c *Client) do(req *Request) (*http.Response, []byte, ErrorResponse, error) {
 	var resp *http.Response
 	var errorValue ErrorResponse
 	var body []byte
@@ -341,7 +365,7 @@ func (c *Client) Do(req *Request) (*http.Response, error) {
 		resp, body, shouldRetry, err = c.innerDo(req, errorValue, i)
 
 		if serr, ok := err.(*nrErrors.MaxRetriesReached); ok {
-			return nil, serr
+			return resp, body, errorValue, serr
 		}
 
 		if shouldRetry {
@@ -349,7 +373,7 @@ func (c *Client) Do(req *Request) (*http.Response, error) {
 		}
 
 		if err != nil {
-			return nil, err
+			return resp, body, errorValue, err
 		}
 
 		break
@@ -357,30 +381,51 @@ func (c *Client) Do(req *Request) (*http.Response, error) {
 
 	if !isResponseSuccess(resp) {
 		if errorValue.IsUnauthorized(resp) {
-			return nil, nrErrors.NewUnauthorizedError()
+			return resp, body, errorValue, nrErrors.NewUnauthorizedError()
 		}
 
-		return nil, nrErrors.NewUnexpectedStatusCode(resp.StatusCode, errorValue.Error())
+		return resp, body, errorValue, nrErrors.NewUnexpectedStatusCode(resp.StatusCode, errorValue.Error())
+	}
+
+	gqlErrors := []GraphQLError{}
+	if v, ok := errorValue.(interface{ graphQLErrors() []GraphQLError }); ok {
+		gqlErrors = v.graphQLErrors()
 	}
 
 	if errorValue.IsNotFound() {
-		return nil, nrErrors.NewNotFound("resource not found")
+		if len(gqlErrors) > 0 {
+			return resp, body, errorValue, nrErrors.NewGraphQLNotFound(gqlErrors)
+		}
+
+		return resp, body, errorValue, nrErrors.NewNotFound("resource not found")
+	}
+
+	if len(gqlErrors) > 0 {
+		if req.value != nil && req.AllowsPartialData() && hasGraphQLData(body) {
+			if jsonErr := json.Unmarshal(body, req.value); jsonErr != nil {
+				return resp, body, errorValue, jsonErr
+			}
+
+			return resp, body, errorValue, nrErrors.NewPartialResult(gqlErrors)
+		}
+
+		return resp, body, errorValue, nrErrors.NewGraphQLErrors(gqlErrors)
 	}
 
 	if errorValue.Error() != "" {
-		return nil, errors.New(errorValue.Error())
+		return resp, body, errorValue, errors.New(errorValue.Error())
 	}
 
 	if req.value == nil {
-		return resp, nil
+		return resp, body, errorValue, nil
 	}
 
 	jsonErr := json.Unmarshal(body, req.value)
 	if jsonErr != nil {
-		return nil, jsonErr
+		return resp, body, errorValue, jsonErr
 	}
 
-	return resp, nil
+	return resp, body, errorValue, nil
 }
 
 func (c *Client) innerDo(req *Request, errorValue ErrorResponse, i int) (*http.Response, []byte, bool, error) {
@@ -422,8 +467,19 @@ func (c *Client) innerDo(req *Request, errorValue ErrorResponse, i int) (*http.R
 		c.logger.Debug(fmt.Sprintf("retrying request (attempt %d)", i), "method", req.method, "url", r.URL)
 	}
 
+	// Make the idempotency of the request available to the retry policy
+	if idempotent, ok := r.Context().Value(idempotentKey{}).(bool); !ok || idempotent != req.IsIdempotent() {
+		r.WithContext(context.WithValue(r.Context(), idempotentKey{}, req.IsIdempotent()))
+	}
+
 	resp, retryErr := c.client.Do(r)
 	if retryErr != nil {
+		// Surface cancellation and deadline errors as-is so callers can
+		// compare them against context.Canceled or context.DeadlineExceeded.
+		if ctxErr := r.Context().Err(); ctxErr != nil {
+			return resp, nil, false, ctxErr
+		}
+
 		return resp, nil, false, retryErr
 	}
 
@@ -448,7 +504,7 @@ func (c *Client) innerDo(req *Request, errorValue ErrorResponse, i int) (*http.R
 
 	_ = json.Unmarshal(body, &errorValue)
 
-	if errorValue.IsRetryableError() {
+	if errorValue.IsRetryableError() && (req.IsIdempotent() || c.config.RetryNonIdempotent) {
 		shouldRetry = true
 	}
 
@@ -464,7 +520,17 @@ func (c *Client) innerDo(req *Request, errorValue ErrorResponse, i int) (*http.R
 
 	wait := c.client.Backoff(c.client.RetryWaitMin, c.client.RetryWaitMax, i, resp)
 
-	time.Sleep(wait)
+	// Wait for the backoff to elapse, but bail out early if the caller's
+	// context is canceled or its deadline is exceeded in the meantime.
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-r.Context().Done():
+		c.logger.Debug("context finished while waiting to retry", "method", req.method, "url", r.URL)
+		return resp, body, false, r.Context().Err()
+	case <-timer.C:
+	}
 
 	return resp, body, true, nil
 }
@@ -518,5 +584,8 @@ func (c *Client) NewNerdGraphRequest(query string, vars map[string]interface{},
 	req.SetAuthStrategy(&NerdGraphAuthorizer{})
 	req.SetErrorValue(&GraphQLErrorResponse{})
 
+	// Queries are sent with POST, but only mutations change anything
+	req.SetIdempotent(!isMutation(query))
+
 	return req, nil
 }
@@ -3,7 +3,9 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	goerrors "errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -317,6 +319,102 @@ func TestErrNotFound(t *testing.T) {
 	assert.IsType(t, &errors.NotFound{}, err)
 }
 
+func TestNerdGraphErrors(t *testing.T) {
+	t.Parallel()
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors":[{"message":"invalid","path":["actor",0],"extensions":{"errorClass":"VALIDATION","error_code":"INVALID"}}]}`))
+	}))
+
+	err := c.NerdGraphQuery("query { actor { user { id } } }", nil, nil)
+
+	var gqlErr *errors.GraphQLError
+	require.True(t, goerrors.As(err, &gqlErr))
+	assert.Equal(t, "invalid", gqlErr.Message)
+	assert.Equal(t, []interface{}{"actor", float64(0)}, gqlErr.Path)
+	assert.Equal(t, "VALIDATION", gqlErr.Extensions.ErrorClass)
+	assert.Equal(t, "INVALID", gqlErr.Extensions.ErrorCode)
+}
+
+func TestNerdGraphErrors_notFound(t *testing.T) {
+	t.Parallel()
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors":[{"message":"no such entity","extensions":{"errorClass":"NOT_FOUND"}}]}`))
+	}))
+
+	err := c.NerdGraphQuery("query { actor { entity(guid: \"guid\") { name } } }", nil, nil)
+
+	assert.IsType(t, &errors.NotFound{}, err)
+	assert.Equal(t, "no such entity", err.Error())
+
+	var gqlErr *errors.GraphQLError
+	assert.True(t, goerrors.As(err, &gqlErr))
+}
+
+func TestNerdGraphPartialData(t *testing.T) {
+	t.Parallel()
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"first":{"id":1},"second":null},"errors":[{"message":"no access","path":["second"],"extensions":{"errorClass":"FORBIDDEN"}}]}`))
+	}))
+
+	type account struct {
+		ID int `json:"id"`
+	}
+
+	type response struct {
+		First  *account `json:"first"`
+		Second *account `json:"second"`
+	}
+
+	// Disabled by default
+	resp := response{}
+	err := c.NerdGraphQuery("query { first: account(id: 1) { id } second: account(id: 2) { id } }", nil, &resp)
+	assert.IsType(t, &errors.UnauthorizedError{}, err)
+	assert.Nil(t, resp.First)
+
+	c.config.NerdGraphPartialData = true
+
+	resp = response{}
+	err = c.NerdGraphQuery("query { first: account(id: 1) { id } second: account(id: 2) { id } }", nil, &resp)
+
+	var partial *errors.PartialResult
+	require.True(t, goerrors.As(err, &partial))
+	require.Len(t, partial.Errors(), 1)
+	assert.Equal(t, "partial result: no access", err.Error())
+	require.NotNil(t, resp.First)
+	assert.Equal(t, 1, resp.First.ID)
+	assert.Nil(t, resp.Second)
+
+	var gqlErr *errors.GraphQLError
+	require.True(t, goerrors.As(err, &gqlErr))
+	assert.Equal(t, "FORBIDDEN", gqlErr.Extensions.ErrorClass)
+
+	// Overridden per request
+	req, err := c.NewNerdGraphRequest("query { first: account(id: 1) { id } }", nil, &response{})
+	require.NoError(t, err)
+	req.SetPartialData(false)
+
+	_, err = c.Do(req)
+	assert.IsType(t, &errors.UnauthorizedError{}, err)
+}
+
+func TestNerdGraphPartialData_noData(t *testing.T) {
+	t.Parallel()
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":null,"errors":[{"message":"invalid query"}]}`))
+	}))
+
+	c.config.NerdGraphPartialData = true
+
+	err := c.NerdGraphQuery("query { invalid }", nil, &struct{}{})
+
+	assert.IsType(t, &errors.GraphQLErrors{}, err)
+	assert.Equal(t, "invalid query", err.Error())
+}
+
 func TestRetryOnNerdGraphTimeout(t *testing.T) {
 	t.Parallel()
 	attempts := 0
@@ -414,3 +512,195 @@ func TestDelete(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestContextCanceledAbortsInFlightRequest(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	defer close(release)
+
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetWithContext(ctx, c.config.Region().RestURL("path"), nil, nil)
+
+	require.Error(t, err)
+	assert.True(t, goerrors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestContextCanceledBeforeRequest(t *testing.T) {
+	t.Parallel()
+	attempts := 0
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.PostWithContext(ctx, c.config.Region().RestURL("path"), nil, &struct{}{}, nil)
+
+	require.Error(t, err)
+	assert.True(t, goerrors.Is(err, context.Canceled))
+	assert.Equal(t, 0, attempts)
+}
+
+func TestContextCanceledAbortsRetryWait(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	attempts := 0
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		cancel()
+	}))
+
+	c.client.RetryWaitMin = time.Hour
+	c.client.RetryWaitMax = time.Hour
+
+	start := time.Now()
+	_, err := c.GetWithContext(ctx, c.config.Region().RestURL("path"), nil, nil)
+
+	require.Error(t, err)
+	assert.True(t, goerrors.Is(err, context.Canceled))
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestContextDeadlineAbortsNerdGraphRetryWait(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors":[{"message": "some error", "extensions":{"errorClass":"TIMEOUT"}}]}`))
+	}))
+
+	c.client.RetryWaitMin = time.Hour
+	c.client.RetryWaitMax = time.Hour
+
+	start := time.Now()
+	err := c.NerdGraphQueryWithContext(ctx, "query { actor { user { id } } }", nil, nil)
+
+	require.Error(t, err)
+	assert.True(t, goerrors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+// recordingMiddleware records the calls made to it.
+type recordingMiddleware struct {
+	name  string
+	calls *[]string
+	resp  *config.Response
+	err   error
+}
+
+func (m *recordingMiddleware) BeforeRequest(req *http.Request) error {
+	*m.calls = append(*m.calls, m.name+".before")
+	req.Header.Set("X-Trace-"+m.name, "traced")
+
+	return nil
+}
+
+func (m *recordingMiddleware) AfterResponse(resp *config.Response) {
+	*m.calls = append(*m.calls, m.name+".after")
+	m.resp = resp
+}
+
+func (m *recordingMiddleware) OnError(req *http.Request, err error) {
+	*m.calls = append(*m.calls, m.name+".error")
+	m.err = err
+}
+
+func newTestMiddlewareClient(t *testing.T, handler http.Handler, middleware ...config.Middleware) Client {
+	ts := httptest.NewServer(handler)
+	tc := mock.NewTestConfig(t, ts)
+	tc.Middleware = middleware
+
+	return NewClient(tc)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	calls := []string{}
+	first := &recordingMiddleware{name: "first", calls: &calls}
+	second := &recordingMiddleware{name: "second", calls: &calls}
+
+	c := newTestMiddlewareClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "traced", r.Header.Get("X-Trace-first"))
+		assert.Equal(t, "traced", r.Header.Get("X-Trace-second"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"foo":"bar"}`))
+	}), first, second)
+
+	_, err := c.Get(c.config.Region().RestURL("path"), nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first.before", "second.before", "second.after", "first.after"}, calls)
+	require.NotNil(t, first.resp)
+	assert.Equal(t, http.StatusOK, first.resp.Response.StatusCode)
+	assert.Equal(t, `{"foo":"bar"}`, string(first.resp.Body))
+	assert.Nil(t, first.err)
+}
+
+func TestMiddleware_graphQLErrors(t *testing.T) {
+	t.Parallel()
+
+	calls := []string{}
+	m := &recordingMiddleware{name: "m", calls: &calls}
+
+	c := newTestMiddlewareClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors":[{"message":"not allowed","path":["actor"],"extensions":{"errorClass":"FORBIDDEN"}}]}`))
+	}), m)
+
+	err := c.NerdGraphQuery("query { actor { user { id } } }", nil, nil)
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"m.before", "m.after", "m.error"}, calls)
+	require.NotNil(t, m.resp)
+	require.Len(t, m.resp.GraphQLErrors, 1)
+	assert.Equal(t, "FORBIDDEN", m.resp.GraphQLErrors[0].Extensions.ErrorClass)
+	assert.Equal(t, []interface{}{"actor"}, m.resp.GraphQLErrors[0].Path)
+	assert.Equal(t, err, m.err)
+}
+
+func TestMiddleware_beforeRequestError(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	abort := goerrors.New("aborted")
+	calls := []string{}
+	m := &recordingMiddleware{name: "m", calls: &calls}
+
+	c := newTestMiddlewareClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+	}), config.MiddlewareFuncs{
+		Before: func(req *http.Request) error {
+			return abort
+		},
+	}, m)
+
+	_, err := c.Get(c.config.Region().RestURL("path"), nil, nil)
+
+	assert.Equal(t, abort, err)
+	assert.Equal(t, 0, attempts)
+	assert.Equal(t, []string{"m.error"}, calls)
+}
@@ -3,7 +3,8 @@ package http
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
 type graphQLRequest struct {
@@ -15,28 +16,24 @@ type graphQLResponse struct {
 	Data interface{} `json:"data"`
 }
 
-// GraphQLError represents a single error.
-type GraphQLError struct {
-	Message    string   `json:"message,omitempty"`
-	Path       []string `json:"path,omitempty"`
-	Extensions struct {
-		ErrorClass string `json:"errorClass,omitempty"`
-		ErrorCode  string `json:"error_code,omitempty"`
-	} `json:"extensions,omitempty"`
-	DownstreamResponse []GraphQLDownstreamResponse `json:"downstreamResponse,omitempty"`
+// hasGraphQLData reports whether a NerdGraph response body holds data.
+func hasGraphQLData(body []byte) bool {
+	resp := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return false
+	}
+
+	return len(resp.Data) > 0 && string(resp.Data) != "null"
 }
 
+// GraphQLError represents a single error.
+type GraphQLError = nrErrors.GraphQLError
+
 // GraphQLDownstreamResponse represents an error's downstream response.
-type GraphQLDownstreamResponse struct {
-	Extensions struct {
-		Code             string `json:"code,omitempty"`
-		ValidationErrors []struct {
-			Name   string `json:"name,omitempty"`
-			Reason string `json:"reason,omitempty"`
-		} `json:"validationErrors,omitempty"`
-	} `json:"extensions,omitempty"`
-	Message string `json:"message,omitempty"`
-}
+type GraphQLDownstreamResponse = nrErrors.GraphQLDownstreamResponse
 
 // GraphQLErrorResponse represents a default error response body.
 type GraphQLErrorResponse struct {
@@ -45,24 +42,16 @@ type GraphQLErrorResponse struct {
 
 func (r *GraphQLErrorResponse) Error() string {
 	if len(r.Errors) > 0 {
-		messages := []string{}
-		for _, e := range r.Errors {
-
-			if e.Message != "" {
-				messages = append(messages, e.Message)
-			}
-
-			if e.DownstreamResponse != nil {
-				f, _ := json.Marshal(e.DownstreamResponse)
-				messages = append(messages, string(f))
-			}
-		}
-		return strings.Join(messages, ", ")
+		return nrErrors.NewGraphQLErrors(r.Errors).Error()
 	}
 
 	return ""
 }
 
+func (r *GraphQLErrorResponse) graphQLErrors() []GraphQLError {
+	return r.Errors
+}
+
 // IsNotFound determines if the error is due to a missing resource.
 func (r *GraphQLErrorResponse) IsNotFound() bool {
 	return false
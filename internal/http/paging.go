@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"net/http"
+	"reflect"
 
 	"github.com/tomnomnom/linkheader"
 )
@@ -34,3 +36,198 @@ func (l *LinkHeaderPager) Parse(resp *http.Response) Paging {
 
 	return paging
 }
+
+// PageFetcher fetches the page of items following the given cursor, empty
+// for the first page.  It returns the items of the page along with the cursor
+// of the next page, empty after the last page.  The page size requested is
+// zero when the API is to decide.
+type PageFetcher func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error)
+
+// Iterator streams the items of a paginated API, only fetching a page once
+// the items of the previous one have been consumed.
+type Iterator struct {
+	ctx      context.Context
+	fetch    PageFetcher
+	pageSize int
+
+	page    []interface{}
+	pos     int
+	cursor  string
+	fetched bool
+	value   interface{}
+	err     error
+}
+
+// IteratorOption configures an Iterator.
+type IteratorOption func(*Iterator)
+
+// IteratorPageSize requests pages of the given size, for the APIs that
+// allow it.
+func IteratorPageSize(size int) IteratorOption {
+	return func(it *Iterator) {
+		if size > 0 {
+			it.pageSize = size
+		}
+	}
+}
+
+// NewIterator creates an Iterator fetching its pages with fetch.
+func NewIterator(ctx context.Context, fetch PageFetcher, opts ...IteratorOption) *Iterator {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	it := &Iterator{
+		ctx:   ctx,
+		fetch: fetch,
+	}
+
+	for _, fn := range opts {
+		if fn != nil {
+			fn(it)
+		}
+	}
+
+	return it
+}
+
+// Next advances to the next item, fetching the next page when required.
+// It returns false once all items have been read or when fetching a page
+// failed, which Err then reports.
+func (it *Iterator) Next() bool {
+	for it.pos >= len(it.page) {
+		if !it.fetchPage() {
+			return false
+		}
+	}
+
+	it.value = it.page[it.pos]
+	it.pos++
+
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator) Value() interface{} {
+	return it.value
+}
+
+// NextPage advances to the next page of items, skipping the items of the
+// current page not read with Next.  It returns false once all pages have
+// been read or when fetching a page failed, which Err then reports.
+func (it *Iterator) NextPage() bool {
+	if !it.fetchPage() {
+		return false
+	}
+
+	it.pos = len(it.page)
+
+	return true
+}
+
+// Page returns the items of the current page.
+func (it *Iterator) Page() []interface{} {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+func (it *Iterator) fetchPage() bool {
+	if it.err != nil || (it.fetched && it.cursor == "") {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	items, next, err := it.fetch(it.ctx, it.cursor, it.pageSize)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	// Guard against APIs returning the same cursor again
+	if it.fetched && next == it.cursor {
+		next = ""
+	}
+
+	it.page = items
+	it.pos = 0
+	it.cursor = next
+	it.fetched = true
+
+	return true
+}
+
+// LinkHeaderFetcher returns a PageFetcher for a REST API paginated with Link
+// headers, starting at url.  Each page is unmarshaled into the value returned
+// by newPage, then items returns the items of that page.
+func (c *Client) LinkHeaderFetcher(pager Pager, url string, params interface{}, newPage func() interface{}, items func(page interface{}) []interface{}) PageFetcher {
+	return func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error) {
+		nextURL := cursor
+		if nextURL == "" {
+			nextURL = url
+		}
+
+		page := newPage()
+
+		resp, err := c.GetWithContext(ctx, nextURL, params, page)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return items(page), pager.Parse(resp).Next, nil
+	}
+}
+
+// NerdGraphCursorFetcher returns a PageFetcher for a NerdGraph query paginated
+// with a nextCursor, which is sent as the "cursor" variable.  Each response is
+// unmarshaled into the value returned by newResponse, then page returns the
+// items of that response along with the next cursor.
+func (c *Client) NerdGraphCursorFetcher(query string, vars map[string]interface{}, newResponse func() interface{}, page func(resp interface{}) ([]interface{}, *string)) PageFetcher {
+	return func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error) {
+		pageVars := make(map[string]interface{}, len(vars)+1)
+		for k, v := range vars {
+			pageVars[k] = v
+		}
+
+		pageVars["cursor"] = nil
+		if cursor != "" {
+			pageVars["cursor"] = cursor
+		}
+
+		resp := newResponse()
+
+		if err := c.NerdGraphQueryWithContext(ctx, query, pageVars, resp); err != nil {
+			return nil, "", err
+		}
+
+		items, next := page(resp)
+		if next == nil {
+			return items, "", nil
+		}
+
+		return items, *next, nil
+	}
+}
+
+// Items converts a slice of any type to a slice of items, as returned by a
+// PageFetcher.
+func Items(slice interface{}) []interface{} {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+
+	return items
+}
@@ -0,0 +1,228 @@
+// +build unit
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPages returns a PageFetcher serving the given pages, with cursors
+// being the index of the page, and counts the pages fetched.
+func testPages(pages [][]interface{}, fetched *int, sizes *[]int) PageFetcher {
+	return func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error) {
+		i := 0
+		if cursor != "" {
+			fmt.Sscan(cursor, &i)
+		}
+
+		*fetched++
+		if sizes != nil {
+			*sizes = append(*sizes, pageSize)
+		}
+
+		if i+1 < len(pages) {
+			return pages[i], fmt.Sprint(i + 1), nil
+		}
+
+		return pages[i], "", nil
+	}
+}
+
+func TestIterator_next(t *testing.T) {
+	t.Parallel()
+
+	fetched := 0
+	sizes := []int{}
+	it := NewIterator(context.Background(), testPages([][]interface{}{{1, 2}, {}, {3}}, &fetched, &sizes), IteratorPageSize(2))
+
+	// Pages are only fetched when required
+	assert.Equal(t, 0, fetched)
+	require.True(t, it.Next())
+	assert.Equal(t, 1, it.Value())
+	assert.Equal(t, 1, fetched)
+
+	values := []interface{}{it.Value()}
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []interface{}{1, 2, 3}, values)
+	assert.Equal(t, 3, fetched)
+	assert.Equal(t, []int{2, 2, 2}, sizes)
+
+	// An exhausted iterator stays exhausted
+	assert.False(t, it.Next())
+	assert.Equal(t, 3, fetched)
+}
+
+func TestIterator_nextPage(t *testing.T) {
+	t.Parallel()
+
+	fetched := 0
+	it := NewIterator(context.Background(), testPages([][]interface{}{{1, 2}, {3}}, &fetched, nil))
+
+	require.True(t, it.NextPage())
+	assert.Equal(t, []interface{}{1, 2}, it.Page())
+	require.True(t, it.NextPage())
+	assert.Equal(t, []interface{}{3}, it.Page())
+	assert.False(t, it.NextPage())
+	assert.NoError(t, it.Err())
+}
+
+func TestIterator_error(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("test error")
+	calls := 0
+
+	it := NewIterator(context.Background(), func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error) {
+		calls++
+		if cursor == "" {
+			return []interface{}{1}, "next", nil
+		}
+
+		return nil, "", testErr
+	})
+
+	require.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Equal(t, testErr, it.Err())
+
+	// The failed page is not fetched again
+	assert.False(t, it.Next())
+	assert.Equal(t, 2, calls)
+}
+
+func TestIterator_repeatedCursor(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	it := NewIterator(context.Background(), func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error) {
+		calls++
+		return []interface{}{calls}, "same", nil
+	})
+
+	for it.Next() {
+		require.LessOrEqual(t, calls, 2)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 2, calls)
+}
+
+func TestIterator_contextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fetched := 0
+	it := NewIterator(ctx, testPages([][]interface{}{{1}, {2}}, &fetched, nil))
+
+	require.True(t, it.Next())
+	cancel()
+
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+	assert.Equal(t, 1, fetched)
+}
+
+func TestLinkHeaderFetcher(t *testing.T) {
+	t.Parallel()
+
+	var c Client
+	c = NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("page") == "" {
+			assert.Equal(t, "test", r.URL.Query().Get("filter[name]"))
+
+			next := c.config.Region().RestURL("items.json") + "?filter%5Bname%5D=test&page=2"
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+			_, _ = w.Write([]byte(`{"items":[1,2]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"items":[3]}`))
+	}))
+
+	type itemsResponse struct {
+		Items []int `json:"items"`
+	}
+
+	params := struct {
+		Name string `url:"filter[name]"`
+	}{
+		Name: "test",
+	}
+
+	fetch := c.LinkHeaderFetcher(&LinkHeaderPager{}, c.config.Region().RestURL("items.json"), &params,
+		func() interface{} { return &itemsResponse{} },
+		func(page interface{}) []interface{} { return Items(page.(*itemsResponse).Items) },
+	)
+
+	values := []interface{}{}
+	it := NewIterator(context.Background(), fetch)
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []interface{}{1, 2, 3}, values)
+}
+
+func TestNerdGraphCursorFetcher(t *testing.T) {
+	t.Parallel()
+
+	c := NewTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := graphQLRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, float64(1), req.Variables["accountId"])
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if req.Variables["cursor"] == nil {
+			_, _ = w.Write([]byte(`{"data":{"search":{"items":["a","b"],"nextCursor":"c1"}}}`))
+			return
+		}
+
+		assert.Equal(t, "c1", req.Variables["cursor"])
+		_, _ = w.Write([]byte(`{"data":{"search":{"items":["c"],"nextCursor":null}}}`))
+	}))
+
+	type searchResponse struct {
+		Search struct {
+			Items      []string `json:"items"`
+			NextCursor *string  `json:"nextCursor"`
+		} `json:"search"`
+	}
+
+	vars := map[string]interface{}{"accountId": 1}
+
+	fetch := c.NerdGraphCursorFetcher("query { search { items nextCursor } }", vars,
+		func() interface{} { return &searchResponse{} },
+		func(resp interface{}) ([]interface{}, *string) {
+			search := resp.(*searchResponse).Search
+			return Items(search.Items), search.NextCursor
+		},
+	)
+
+	values := []interface{}{}
+	it := NewIterator(context.Background(), fetch)
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []interface{}{"a", "b", "c"}, values)
+
+	// The variables given are left untouched
+	assert.Equal(t, map[string]interface{}{"accountId": 1}, vars)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/google/go-querystring/query"
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
@@ -22,6 +23,8 @@ type Request struct {
 	config       config.Config
 	authStrategy RequestAuthorizer
 	errorValue   ErrorResponse
+	idempotent   *bool
+	partialData  *bool
 	request      *retryablehttp.Request
 }
 
@@ -87,8 +90,18 @@ func (c *Client) NewRequest(method string, url string, params interface{}, reqBo
 }
 
 // WithContext sets the context of the underlying request.
+// A nil context is ignored.
 func (r *Request) WithContext(ctx context.Context) {
-	r.request.WithContext(ctx)
+	if ctx == nil {
+		return
+	}
+
+	r.request = r.request.WithContext(ctx)
+}
+
+// Context returns the context of the underlying request.
+func (r *Request) Context() context.Context {
+	return r.request.Context()
 }
 
 // SetHeader sets a header on the underlying request.
@@ -118,6 +131,42 @@ func (r *Request) SetErrorValue(e ErrorResponse) {
 	r.errorValue = e
 }
 
+// SetIdempotent overrides whether the request can safely be sent more than
+// once.  By default, only requests using idempotent HTTP methods are.
+func (r *Request) SetIdempotent(idempotent bool) {
+	r.idempotent = &idempotent
+}
+
+// IsIdempotent reports whether the request can safely be sent more than once.
+func (r *Request) IsIdempotent() bool {
+	if r.idempotent != nil {
+		return *r.idempotent
+	}
+
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// SetPartialData overrides whether the data of a NerdGraph response holding
+// errors is kept, as configured with NerdGraphPartialData by default.
+func (r *Request) SetPartialData(partialData bool) {
+	r.partialData = &partialData
+}
+
+// AllowsPartialData reports whether the data of a NerdGraph response holding
+// errors is kept.
+func (r *Request) AllowsPartialData() bool {
+	if r.partialData != nil {
+		return *r.partialData
+	}
+
+	return r.config.NerdGraphPartialData
+}
+
 // SetServiceName sets the service name for the request.
 func (r *Request) SetServiceName(serviceName string) {
 	serviceName = fmt.Sprintf("%s|%s", serviceName, defaultServiceName)
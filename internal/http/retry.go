@@ -3,9 +3,19 @@ package http
 import (
 	"context"
 	"crypto/x509"
+	"errors"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
 )
 
 var (
@@ -67,3 +77,118 @@ func RetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, err
 
 	return false, nil
 }
+
+// unixTimestampThreshold separates Unix timestamps from durations in seconds
+// in rate limit headers.
+const unixTimestampThreshold = 1000000000
+
+// idempotentKey is the context key holding whether the request being sent
+// is idempotent.
+type idempotentKey struct{}
+
+// newRetryPolicy returns the CheckRetry callback of a client.  It applies
+// RetryPolicy, then prevents retrying requests that are not idempotent unless
+// the server did not process them, then defers to the policy configured, if any.
+func newRetryPolicy(cfg config.Config) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		retry, checkErr := RetryPolicy(ctx, resp, err)
+		if checkErr != nil {
+			return retry, checkErr
+		}
+
+		if retry && !cfg.RetryNonIdempotent {
+			if idempotent, ok := ctx.Value(idempotentKey{}).(bool); ok && !idempotent && !notProcessed(resp, err) {
+				retry = false
+			}
+		}
+
+		if cfg.RetryPolicy != nil {
+			return cfg.RetryPolicy(ctx, resp, err, retry)
+		}
+
+		return retry, nil
+	}
+}
+
+// notProcessed reports whether a failed request is known not to have been
+// processed by the server, so sending it again cannot create duplicates.
+func notProcessed(resp *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests
+}
+
+// newBackoff returns the Backoff callback of a client.  Rate limited requests
+// wait as long as the response asks for, up to the maximum wait, otherwise
+// the wait doubles on each attempt and is randomly reduced by up to the
+// jitter fraction given.
+func newBackoff(jitter float64) retryablehttp.Backoff {
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		if wait, ok := rateLimitWait(resp, time.Now()); ok {
+			if wait > max {
+				return max
+			}
+
+			return wait
+		}
+
+		wait := retryablehttp.DefaultBackoff(min, max, attemptNum, nil)
+
+		if jitter > 0 {
+			wait -= time.Duration(rand.Float64() * jitter * float64(wait))
+		}
+
+		return wait
+	}
+}
+
+// rateLimitWait returns the wait requested by a rate limited response, through
+// either the Retry-After header or the X-RateLimit-Reset header used by NerdGraph.
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	if value := strings.TrimSpace(resp.Header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+
+		if at, err := http.ParseTime(value); err == nil {
+			return nonNegative(at.Sub(now)), true
+		}
+	}
+
+	if value := strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")); value != "" {
+		reset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || reset < 0 {
+			return 0, false
+		}
+
+		// Either a Unix timestamp or a number of seconds
+		if reset > unixTimestampThreshold {
+			return nonNegative(time.Unix(reset, 0).Sub(now)), true
+		}
+
+		return time.Duration(reset) * time.Second, true
+	}
+
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+
+	return d
+}
+
+// isMutation reports whether a GraphQL request is a mutation.
+func isMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
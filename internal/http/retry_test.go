@@ -0,0 +1,226 @@
+// +build unit
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	mock "github.com/newrelic/newrelic-client-go/pkg/testhelpers"
+)
+
+func newTestRetryClient(t *testing.T, handler http.Handler, opts func(*config.Config)) Client {
+	ts := httptest.NewServer(handler)
+	tc := mock.NewTestConfig(t, ts)
+
+	wait := time.Millisecond
+	tc.RetryWaitMin = &wait
+	tc.RetryWaitMax = &wait
+
+	if opts != nil {
+		opts(&tc)
+	}
+
+	return NewClient(tc)
+}
+
+// statusHandler responds with the given status code and counts the attempts.
+func statusHandler(attempts *int64, statusCode int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(attempts, 1)
+		w.WriteHeader(statusCode)
+	})
+}
+
+func TestRetry_idempotentMethods(t *testing.T) {
+	t.Parallel()
+
+	var attempts int64
+	c := newTestRetryClient(t, statusHandler(&attempts, http.StatusServiceUnavailable), nil)
+
+	_, err := c.Get(c.config.Region().RestURL("path"), nil, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(defaultRetryMax+1), atomic.LoadInt64(&attempts))
+}
+
+func TestRetry_nonIdempotentMethods(t *testing.T) {
+	t.Parallel()
+
+	var attempts int64
+	c := newTestRetryClient(t, statusHandler(&attempts, http.StatusServiceUnavailable), nil)
+
+	_, err := c.Post(c.config.Region().RestURL("path"), nil, &struct{}{}, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&attempts))
+
+	// Rate limited requests were not processed, so they are safe to retry
+	atomic.StoreInt64(&attempts, 0)
+	c = newTestRetryClient(t, statusHandler(&attempts, http.StatusTooManyRequests), nil)
+
+	_, err = c.Post(c.config.Region().RestURL("path"), nil, &struct{}{}, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(defaultRetryMax+1), atomic.LoadInt64(&attempts))
+
+	// Unless configured otherwise
+	atomic.StoreInt64(&attempts, 0)
+	c = newTestRetryClient(t, statusHandler(&attempts, http.StatusServiceUnavailable), func(cfg *config.Config) {
+		cfg.RetryNonIdempotent = true
+	})
+
+	_, err = c.Post(c.config.Region().RestURL("path"), nil, &struct{}{}, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(defaultRetryMax+1), atomic.LoadInt64(&attempts))
+}
+
+func TestRetry_nerdGraphMutation(t *testing.T) {
+	t.Parallel()
+
+	var attempts int64
+	c := newTestRetryClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&attempts, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors":[{"message": "some error", "extensions":{"errorClass":"TIMEOUT"}}]}`))
+	}), nil)
+
+	err := c.NerdGraphQuery("query { actor { user { id } } }", nil, nil)
+	assert.Error(t, err)
+	assert.Equal(t, int64(defaultRetryMax+1), atomic.LoadInt64(&attempts))
+
+	atomic.StoreInt64(&attempts, 0)
+
+	err = c.NerdGraphQuery("mutation { doSomething { id } }", nil, nil)
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&attempts))
+}
+
+func TestRetry_configRetryMax(t *testing.T) {
+	t.Parallel()
+
+	var attempts int64
+	c := newTestRetryClient(t, statusHandler(&attempts, http.StatusServiceUnavailable), func(cfg *config.Config) {
+		retryMax := 1
+		cfg.RetryMax = &retryMax
+	})
+
+	_, err := c.Get(c.config.Region().RestURL("path"), nil, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&attempts))
+}
+
+func TestRetry_configRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	var attempts int64
+	c := newTestRetryClient(t, statusHandler(&attempts, http.StatusConflict), func(cfg *config.Config) {
+		cfg.RetryPolicy = func(ctx context.Context, resp *http.Response, err error, retry bool) (bool, error) {
+			assert.False(t, retry)
+			return resp != nil && resp.StatusCode == http.StatusConflict, nil
+		}
+	})
+
+	_, err := c.Get(c.config.Region().RestURL("path"), nil, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(defaultRetryMax+1), atomic.LoadInt64(&attempts))
+}
+
+func TestRateLimitWait(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1600000000, 0)
+
+	cases := map[string]struct {
+		statusCode int
+		headers    map[string]string
+		wait       time.Duration
+		ok         bool
+	}{
+		"retry after seconds": {
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"Retry-After": "7"},
+			wait:       7 * time.Second,
+			ok:         true,
+		},
+		"retry after date": {
+			statusCode: http.StatusServiceUnavailable,
+			headers:    map[string]string{"Retry-After": now.Add(time.Minute).UTC().Format(http.TimeFormat)},
+			wait:       time.Minute,
+			ok:         true,
+		},
+		"rate limit reset seconds": {
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"X-RateLimit-Reset": "3"},
+			wait:       3 * time.Second,
+			ok:         true,
+		},
+		"rate limit reset timestamp": {
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"X-RateLimit-Reset": "1600000010"},
+			wait:       10 * time.Second,
+			ok:         true,
+		},
+		"no header": {
+			statusCode: http.StatusTooManyRequests,
+		},
+		"not rate limited": {
+			statusCode: http.StatusInternalServerError,
+			headers:    map[string]string{"Retry-After": "7"},
+		},
+	}
+
+	for name, c := range cases {
+		resp := &http.Response{
+			StatusCode: c.statusCode,
+			Header:     http.Header{},
+		}
+
+		for k, v := range c.headers {
+			resp.Header.Set(k, v)
+		}
+
+		wait, ok := rateLimitWait(resp, now)
+		assert.Equal(t, c.ok, ok, name)
+		assert.Equal(t, c.wait, wait, name)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	t.Parallel()
+
+	backoff := newBackoff(0.5)
+
+	for i := 0; i < 100; i++ {
+		wait := backoff(time.Second, time.Minute, 2, nil)
+
+		require.LessOrEqual(t, int64(wait), int64(4*time.Second))
+		require.GreaterOrEqual(t, int64(wait), int64(2*time.Second))
+	}
+}
+
+func TestBackoffRateLimitCapped(t *testing.T) {
+	t.Parallel()
+
+	backoff := newBackoff(0)
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"3600"}},
+	}
+
+	assert.Equal(t, 30*time.Second, backoff(time.Second, 30*time.Second, 1, resp))
+
+	resp.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, backoff(time.Second, 30*time.Second, 1, resp))
+}
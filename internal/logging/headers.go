@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CleanHeader returns a copy of the given HTTP headers with the API keys
+// obfuscated, suitable for logging or otherwise persisting.
+func CleanHeader(header http.Header) http.Header {
+	h := http.Header{}
+
+	for k, values := range header {
+		switch k {
+		case "Api-Key", "X-Api-Key", "X-Insert-Key", "X-License-Key":
+			newValues := []string{}
+			for _, v := range values {
+				newValues = append(newValues, obfuscate(v))
+			}
+
+			if len(newValues) > 0 {
+				h[k] = newValues
+			} else {
+				h[k] = values
+			}
+		default:
+			h[k] = values
+		}
+	}
+
+	return h
+}
+
+// obfuscate receives a string, and replaces everything after the first 8
+// characters with an asterisk before returning the result.
+func obfuscate(input string) string {
+	result := make([]string, len(input))
+	parts := strings.Split(input, "")
+
+	for i, x := range parts {
+		if i < 8 {
+			result[i] = x
+		} else {
+			result[i] = "*"
+		}
+	}
+
+	return strings.Join(result, "")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/newrelic/newrelic-client-go/pkg/events"
 	"github.com/newrelic/newrelic-client-go/pkg/eventstometrics"
 	"github.com/newrelic/newrelic-client-go/pkg/logs"
+	"github.com/newrelic/newrelic-client-go/pkg/metrics"
 	"github.com/newrelic/newrelic-client-go/pkg/nerdgraph"
 	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
 	"github.com/newrelic/newrelic-client-go/pkg/nrdb"
@@ -27,6 +28,7 @@ import (
 	"github.com/newrelic/newrelic-client-go/pkg/plugins"
 	"github.com/newrelic/newrelic-client-go/pkg/region"
 	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/newrelic/newrelic-client-go/pkg/traces"
 	"github.com/newrelic/newrelic-client-go/pkg/workloads"
 )
 
@@ -43,12 +45,14 @@ type NewRelic struct {
 	Events          events.Events
 	EventsToMetrics eventstometrics.EventsToMetrics
 	Logs            logs.Logs
+	Metrics         metrics.Metrics
 	NerdGraph       nerdgraph.NerdGraph
 	NerdStorage     nerdstorage.NerdStorage
 	Nrdb            nrdb.Nrdb
 	Nrqldroprules   nrqldroprules.Nrqldroprules
 	Plugins         plugins.Plugins
 	Synthetics      synthetics.Synthetics
+	Traces          traces.Traces
 	Workloads       workloads.Workloads
 
 	config config.Config
@@ -89,12 +93,14 @@ func New(opts ...ConfigOption) (*NewRelic, error) {
 		Events:          events.New(cfg),
 		EventsToMetrics: eventstometrics.New(cfg),
 		Logs:            logs.New(cfg),
+		Metrics:         metrics.New(cfg),
 		NerdGraph:       nerdgraph.New(cfg),
 		NerdStorage:     nerdstorage.New(cfg),
 		Nrdb:            nrdb.New(cfg),
 		Nrqldroprules:   nrqldroprules.New(cfg),
 		Plugins:         plugins.New(cfg),
 		Synthetics:      synthetics.New(cfg),
+		Traces:          traces.New(cfg),
 		Workloads:       workloads.New(cfg),
 	}
 
@@ -138,6 +144,41 @@ func ConfigAdminAPIKey(adminAPIKey string) ConfigOption {
 	}
 }
 
+// ConfigFromEnv sets the configuration values found in the environment, such
+// as NEW_RELIC_API_KEY, NEW_RELIC_REGION and NEW_RELIC_ACCOUNT_ID, see the
+// config package for the full list.
+//
+// Options are applied in order, each overriding the values set by the
+// previous ones, so that explicit options given after ConfigFromEnv take
+// precedence over the environment.  Setting the region resets the base URLs,
+// which should be overridden after it.
+func ConfigFromEnv() ConfigOption {
+	return func(cfg *config.Config) error {
+		return cfg.LoadEnv()
+	}
+}
+
+// ConfigFromProfile sets the configuration values of the given profile of the
+// New Relic CLI, read from its credentials.json and config.json files in the
+// .newrelic directory of the user.  The default profile is used when the name
+// is empty.  Like ConfigFromEnv, it only overrides the values set by previous
+// options with the ones present in the profile.
+func ConfigFromProfile(name string) ConfigOption {
+	return func(cfg *config.Config) error {
+		dir, err := config.DefaultProfileDir()
+		if err != nil {
+			return err
+		}
+
+		p, err := config.LoadProfile(dir, name)
+		if err != nil {
+			return err
+		}
+
+		return p.Apply(cfg)
+	}
+}
+
 // ConfigRegion sets the New Relic Region this client will use.
 func ConfigRegion(r string) ConfigOption {
 	return func(cfg *config.Config) error {
@@ -162,6 +203,15 @@ func ConfigRegion(r string) ConfigOption {
 	}
 }
 
+// ConfigCustomRegion sets a region of the New Relic platform not known to the
+// region package, such as one created with region.New.  The client keeps its
+// own copy of the region, which can be shared with other clients.
+func ConfigCustomRegion(reg *region.Region) ConfigOption {
+	return func(cfg *config.Config) error {
+		return cfg.SetRegion(reg)
+	}
+}
+
 // ConfigHTTPTimeout sets the timeout for HTTP requests.
 func ConfigHTTPTimeout(t time.Duration) ConfigOption {
 	return func(cfg *config.Config) error {
@@ -171,6 +221,105 @@ func ConfigHTTPTimeout(t time.Duration) ConfigOption {
 	}
 }
 
+// ConfigRetryMax sets the maximum number of times a failed request is retried.
+func ConfigRetryMax(retryMax int) ConfigOption {
+	return func(cfg *config.Config) error {
+		if retryMax < 0 {
+			return errors.New("retry max can not be negative")
+		}
+
+		cfg.RetryMax = &retryMax
+		return nil
+	}
+}
+
+// ConfigRetryWait sets the minimum and maximum wait between retries.
+// The wait doubles on each retry, starting from the minimum.
+func ConfigRetryWait(min time.Duration, max time.Duration) ConfigOption {
+	return func(cfg *config.Config) error {
+		if min < 0 || max < min {
+			return errors.New("retry wait must be positive, with max greater than min")
+		}
+
+		cfg.RetryWaitMin = &min
+		cfg.RetryWaitMax = &max
+		return nil
+	}
+}
+
+// ConfigRetryJitter randomly reduces the wait between retries by up to the
+// given fraction, between 0 and 1, to spread out retries from many clients.
+func ConfigRetryJitter(jitter float64) ConfigOption {
+	return func(cfg *config.Config) error {
+		if jitter < 0 || jitter > 1 {
+			return errors.New("retry jitter must be between 0 and 1")
+		}
+
+		cfg.RetryJitter = jitter
+		return nil
+	}
+}
+
+// ConfigRetryNonIdempotent allows retrying requests that may create resources,
+// such as POST requests and NerdGraph mutations, even when the server may have
+// processed them.  By default, these are only retried when rate limited.
+func ConfigRetryNonIdempotent(retry bool) ConfigOption {
+	return func(cfg *config.Config) error {
+		cfg.RetryNonIdempotent = retry
+		return nil
+	}
+}
+
+// ConfigNerdGraphPartialData keeps the data of NerdGraph responses holding
+// errors, such as a query across several accounts with one of them
+// inaccessible.  The data is unmarshalled into the response and the errors
+// returned as an *errors.PartialResult.
+func ConfigNerdGraphPartialData(enabled bool) ConfigOption {
+	return func(cfg *config.Config) error {
+		cfg.NerdGraphPartialData = enabled
+		return nil
+	}
+}
+
+// ConfigSkipInputValidation sends the inputs of mutations, such as alert
+// conditions, without checking them against the documented rules first.  The
+// APIs then report the invalid inputs, with less detail.
+func ConfigSkipInputValidation(skip bool) ConfigOption {
+	return func(cfg *config.Config) error {
+		cfg.SkipInputValidation = skip
+		return nil
+	}
+}
+
+// ConfigRetryPolicy sets a policy that decides whether a failed request is
+// retried, given the decision of the default policy.
+func ConfigRetryPolicy(policy config.RetryPolicy) ConfigOption {
+	return func(cfg *config.Config) error {
+		if policy != nil {
+			cfg.RetryPolicy = policy
+			return nil
+		}
+
+		return errors.New("retry policy can not be nil")
+	}
+}
+
+// ConfigMiddleware adds middleware that intercepts every request made by the
+// API clients.  It may be used several times, the middleware is called in the
+// order it was added.
+func ConfigMiddleware(middleware ...config.Middleware) ConfigOption {
+	return func(cfg *config.Config) error {
+		for _, m := range middleware {
+			if m == nil {
+				return errors.New("middleware can not be nil")
+			}
+		}
+
+		cfg.Middleware = append(cfg.Middleware, middleware...)
+		return nil
+	}
+}
+
 // ConfigHTTPTransport sets the HTTP Transporter.
 func ConfigHTTPTransport(transport http.RoundTripper) ConfigOption {
 	return func(cfg *config.Config) error {
@@ -210,8 +359,7 @@ func ConfigServiceName(name string) ConfigOption {
 func ConfigBaseURL(url string) ConfigOption {
 	return func(cfg *config.Config) error {
 		if url != "" {
-			cfg.Region().SetRestBaseURL(url)
-			return nil
+			return cfg.SetEndpoints(region.Endpoints{Rest: url})
 		}
 
 		return errors.New("base URL can not be empty")
@@ -222,8 +370,7 @@ func ConfigBaseURL(url string) ConfigOption {
 func ConfigInfrastructureBaseURL(url string) ConfigOption {
 	return func(cfg *config.Config) error {
 		if url != "" {
-			cfg.Region().SetInfrastructureBaseURL(url)
-			return nil
+			return cfg.SetEndpoints(region.Endpoints{Infrastructure: url})
 		}
 
 		return errors.New("infrastructure base URL can not be empty")
@@ -234,8 +381,7 @@ func ConfigInfrastructureBaseURL(url string) ConfigOption {
 func ConfigSyntheticsBaseURL(url string) ConfigOption {
 	return func(cfg *config.Config) error {
 		if url != "" {
-			cfg.Region().SetSyntheticsBaseURL(url)
-			return nil
+			return cfg.SetEndpoints(region.Endpoints{Synthetics: url})
 		}
 
 		return errors.New("synthetics base URL can not be empty")
@@ -246,8 +392,7 @@ func ConfigSyntheticsBaseURL(url string) ConfigOption {
 func ConfigNerdGraphBaseURL(url string) ConfigOption {
 	return func(cfg *config.Config) error {
 		if url != "" {
-			cfg.Region().SetNerdGraphBaseURL(url)
-			return nil
+			return cfg.SetEndpoints(region.Endpoints{NerdGraph: url})
 		}
 
 		return errors.New("nerdgraph base URL can not be empty")
@@ -3,6 +3,7 @@
 package newrelic
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/newrelic/newrelic-client-go/internal/logging"
 	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/region"
 )
 
 var testAPIkey = "asdf1234"
@@ -126,6 +128,69 @@ func TestNew_optionTimeout(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNew_optionRetry(t *testing.T) {
+	t.Parallel()
+
+	nr, err := New(ConfigPersonalAPIKey(testAPIkey),
+		ConfigRetryMax(5),
+		ConfigRetryWait(time.Millisecond, time.Second),
+		ConfigRetryJitter(0.5),
+		ConfigRetryNonIdempotent(true),
+		ConfigRetryPolicy(func(ctx context.Context, resp *http.Response, err error, retry bool) (bool, error) {
+			return retry, nil
+		}),
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, nr)
+	assert.Equal(t, 5, *nr.config.RetryMax)
+	assert.Equal(t, time.Millisecond, *nr.config.RetryWaitMin)
+	assert.Equal(t, time.Second, *nr.config.RetryWaitMax)
+	assert.Equal(t, 0.5, nr.config.RetryJitter)
+	assert.True(t, nr.config.RetryNonIdempotent)
+	assert.NotNil(t, nr.config.RetryPolicy)
+
+	invalid := []ConfigOption{
+		ConfigRetryMax(-1),
+		ConfigRetryWait(time.Second, time.Millisecond),
+		ConfigRetryJitter(2),
+		ConfigRetryPolicy(nil),
+	}
+
+	for _, opt := range invalid {
+		nr, err = New(ConfigPersonalAPIKey(testAPIkey), opt)
+		assert.Nil(t, nr)
+		assert.Error(t, err)
+	}
+}
+
+func TestNew_optionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	first := config.MiddlewareFuncs{}
+	second := config.MiddlewareFuncs{}
+
+	nr, err := New(ConfigPersonalAPIKey(testAPIkey), ConfigMiddleware(first), ConfigMiddleware(second))
+
+	require.NoError(t, err)
+	require.NotNil(t, nr)
+	assert.Equal(t, []config.Middleware{first, second}, nr.config.Middleware)
+
+	nr, err = New(ConfigPersonalAPIKey(testAPIkey), ConfigMiddleware(nil))
+	assert.Nil(t, nr)
+	assert.Error(t, err)
+}
+
+func TestNew_optionNerdGraphPartialData(t *testing.T) {
+	t.Parallel()
+
+	nr, err := New(ConfigPersonalAPIKey(testAPIkey), ConfigNerdGraphPartialData(true))
+
+	require.NoError(t, err)
+	require.NotNil(t, nr)
+	assert.True(t, nr.config.NerdGraphPartialData)
+}
+
 func TestNew_optionTransport(t *testing.T) {
 	t.Parallel()
 
@@ -174,6 +239,29 @@ func TestNew_optionBaseURL(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNew_optionBaseURLIsolation(t *testing.T) {
+	t.Parallel()
+
+	reg, err := region.New("custom", region.Endpoints{Rest: "http://localhost/v2"})
+	require.NoError(t, err)
+
+	first, err := New(ConfigPersonalAPIKey(testAPIkey), ConfigCustomRegion(reg), ConfigBaseURL("http://first/v2"))
+	require.NoError(t, err)
+
+	second, err := New(ConfigPersonalAPIKey(testAPIkey), ConfigCustomRegion(reg))
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://first/v2", first.config.Region().RestURL())
+	assert.Equal(t, "http://localhost/v2", second.config.Region().RestURL())
+	assert.Equal(t, "http://localhost/v2", reg.RestURL())
+
+	_, err = New(ConfigPersonalAPIKey(testAPIkey), ConfigBaseURL("localhost"))
+	assert.Error(t, err)
+
+	_, err = New(ConfigPersonalAPIKey(testAPIkey), ConfigCustomRegion(nil))
+	assert.Error(t, err)
+}
+
 func TestNew_optionInfrastructureBaseURL(t *testing.T) {
 	t.Parallel()
 
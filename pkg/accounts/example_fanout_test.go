@@ -0,0 +1,39 @@
+package accounts
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/nrdb"
+)
+
+func Example_forEachAccount() {
+	// Initialize the client configuration.  A Personal API key is required to
+	// communicate with the backend API.
+	cfg := config.New()
+	cfg.PersonalAPIKey = os.Getenv("NEW_RELIC_API_KEY")
+
+	// Initialize the clients.
+	client := New(cfg)
+	query := nrdb.New(cfg)
+
+	// Count the transactions of the last hour in every account this user is
+	// authorized to view, querying three accounts at once.
+	results, err := client.ForEachAccount(func(ctx context.Context, account AccountOutline) (interface{}, error) {
+		return query.QueryWithContext(ctx, account.ID, "SELECT count(*) FROM Transaction SINCE 1 hour ago")
+	}, FanOutConcurrency(3))
+	if err != nil {
+		log.Fatal("error retrieving accounts:", err)
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			log.Printf("account %d: error running query: %s", r.Account.ID, r.Err)
+			continue
+		}
+
+		log.Printf("account %d: %v", r.Account.ID, r.Value.(*nrdb.NRDBResultContainer).Results)
+	}
+}
@@ -0,0 +1,234 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultFanOutConcurrency is the number of accounts an operation is run for
+// at once by ForEachAccount, unless configured otherwise.
+const DefaultFanOutConcurrency = 5
+
+// AccountFunc is an operation run by ForEachAccount for a single account,
+// such as a NRQL query, returning its result for the account.
+type AccountFunc func(ctx context.Context, account AccountOutline) (interface{}, error)
+
+// AccountResult holds the outcome of an operation for a single account.
+type AccountResult struct {
+	Account AccountOutline
+	Value   interface{}
+	Err     error
+}
+
+// AccountResults holds the outcome of an operation for every account it was
+// run for, in the order of the accounts.
+type AccountResults []AccountResult
+
+// Errors returns the errors of the operation by account ID, if any.
+func (r AccountResults) Errors() map[int]error {
+	errs := map[int]error{}
+	for _, result := range r {
+		if result.Err != nil {
+			errs[result.Account.ID] = result.Err
+		}
+	}
+
+	return errs
+}
+
+// Err returns an error summarizing the accounts the operation failed for, or
+// nil when it succeeded for every account.
+func (r AccountResults) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for _, result := range r {
+		if result.Err != nil {
+			return fmt.Errorf("operation failed for %d of %d accounts, first for account %d: %w", len(errs), len(r), result.Account.ID, result.Err)
+		}
+	}
+
+	return nil
+}
+
+type fanOut struct {
+	params      ListAccountsParams
+	accountIDs  []int
+	selected    bool
+	filter      func(AccountOutline) bool
+	concurrency int
+	interval    time.Duration
+}
+
+// FanOutOption configures the accounts ForEachAccount runs an operation for
+// and how.
+type FanOutOption func(*fanOut) error
+
+// FanOutConcurrency sets the number of accounts the operation is run for at
+// once.
+func FanOutConcurrency(concurrency int) FanOutOption {
+	return func(f *fanOut) error {
+		if concurrency < 1 {
+			return errors.New("concurrency must be at least 1")
+		}
+
+		f.concurrency = concurrency
+		return nil
+	}
+}
+
+// FanOutInterval sets the minimum interval between the start of the
+// operation for two accounts, to stay within the rate limits of the APIs
+// used.  Rate limited requests are retried by the client regardless, waiting
+// as long as asked by the API.
+func FanOutInterval(interval time.Duration) FanOutOption {
+	return func(f *fanOut) error {
+		if interval < 0 {
+			return errors.New("interval can not be negative")
+		}
+
+		f.interval = interval
+		return nil
+	}
+}
+
+// FanOutFilter selects the accounts the operation is run for among the
+// ones listed.
+func FanOutFilter(filter func(AccountOutline) bool) FanOutOption {
+	return func(f *fanOut) error {
+		if filter == nil {
+			return errors.New("filter can not be nil")
+		}
+
+		f.filter = filter
+		return nil
+	}
+}
+
+// FanOutAccountIDs runs the operation for the given accounts only, without
+// listing the accounts of the user.  The accounts given to the operation
+// then only hold their ID.  When none are given, the operation is run for no
+// account.
+func FanOutAccountIDs(accountIDs ...int) FanOutOption {
+	return func(f *fanOut) error {
+		f.accountIDs = accountIDs
+		f.selected = true
+		return nil
+	}
+}
+
+// FanOutListParams sets the parameters used to list the accounts of the
+// user.
+func FanOutListParams(params ListAccountsParams) FanOutOption {
+	return func(f *fanOut) error {
+		f.params = params
+		return nil
+	}
+}
+
+// ForEachAccount runs an operation for every account the user is authorized
+// to view, or the ones selected, with bounded concurrency.  The results and
+// errors of the operation are collected by account, the error returned being
+// reserved to the failure to list the accounts.
+func (e *Accounts) ForEachAccount(fn AccountFunc, opts ...FanOutOption) (AccountResults, error) {
+	return e.ForEachAccountWithContext(context.Background(), fn, opts...)
+}
+
+// ForEachAccountWithContext runs an operation for every account the user is
+// authorized to view, or the ones selected, with bounded concurrency.  The
+// results and errors of the operation are collected by account, the error
+// returned being reserved to the failure to list the accounts.  Accounts not
+// yet started when the context is done fail with the error of the context.
+func (e *Accounts) ForEachAccountWithContext(ctx context.Context, fn AccountFunc, opts ...FanOutOption) (AccountResults, error) {
+	f := fanOut{
+		concurrency: DefaultFanOutConcurrency,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&f); err != nil {
+			return nil, err
+		}
+	}
+
+	accounts, err := e.fanOutAccounts(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(AccountResults, len(accounts))
+	sem := make(chan struct{}, f.concurrency)
+	var wg sync.WaitGroup
+
+	var tick <-chan time.Time
+	if f.interval > 0 {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for i, account := range accounts {
+		results[i].Account = account
+
+		if i > 0 && tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+			}
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, account AccountOutline) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i].Value, results[i].Err = fn(ctx, account)
+		}(i, account)
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+func (e *Accounts) fanOutAccounts(ctx context.Context, f fanOut) ([]AccountOutline, error) {
+	var accounts []AccountOutline
+
+	if f.selected {
+		for _, id := range f.accountIDs {
+			accounts = append(accounts, AccountOutline{ID: id})
+		}
+	} else {
+		var err error
+		if accounts, err = e.ListAccountsWithContext(ctx, f.params); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.filter == nil {
+		return accounts, nil
+	}
+
+	selected := []AccountOutline{}
+	for _, account := range accounts {
+		if f.filter(account) {
+			selected = append(selected, account)
+		}
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,167 @@
+// +build unit
+
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mock "github.com/newrelic/newrelic-client-go/pkg/testhelpers"
+)
+
+var testListAccountsResponseJSON = `{
+	"data": {
+		"actor": {
+			"accounts": [
+				{"id": 1, "name": "one"},
+				{"id": 2, "name": "two"},
+				{"id": 3, "name": "three"},
+				{"id": 4, "name": "four"}
+			]
+		}
+	}
+}`
+
+func newMockResponse(t *testing.T, mockJSONResponse string, statusCode int) Accounts {
+	ts := mock.NewMockServer(t, mockJSONResponse, statusCode)
+	tc := mock.NewTestConfig(t, ts)
+
+	return New(tc)
+}
+
+func TestForEachAccount(t *testing.T) {
+	t.Parallel()
+	client := newMockResponse(t, testListAccountsResponseJSON, http.StatusOK)
+
+	var running, maxRunning int32
+	results, err := client.ForEachAccount(func(ctx context.Context, account AccountOutline) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		if account.ID == 3 {
+			return nil, errors.New("failed")
+		}
+
+		return account.Name, nil
+	}, FanOutConcurrency(2))
+
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.LessOrEqual(t, maxRunning, int32(2))
+
+	for i, name := range []string{"one", "two", "three", "four"} {
+		assert.Equal(t, i+1, results[i].Account.ID)
+		assert.Equal(t, name, results[i].Account.Name)
+	}
+
+	assert.Equal(t, "one", results[0].Value)
+	assert.Equal(t, "four", results[3].Value)
+	assert.Nil(t, results[2].Value)
+
+	errs := results.Errors()
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[3], "failed")
+	assert.EqualError(t, results.Err(), "operation failed for 1 of 4 accounts, first for account 3: failed")
+}
+
+func TestForEachAccount_filter(t *testing.T) {
+	t.Parallel()
+	client := newMockResponse(t, testListAccountsResponseJSON, http.StatusOK)
+
+	results, err := client.ForEachAccount(func(ctx context.Context, account AccountOutline) (interface{}, error) {
+		return account.ID * 10, nil
+	}, FanOutFilter(func(account AccountOutline) bool {
+		return account.ID%2 == 0
+	}))
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 2, results[0].Account.ID)
+	assert.Equal(t, 20, results[0].Value)
+	assert.Equal(t, 4, results[1].Account.ID)
+	assert.Equal(t, 40, results[1].Value)
+	assert.NoError(t, results.Err())
+}
+
+func TestForEachAccount_accountIDs(t *testing.T) {
+	t.Parallel()
+	client := newMockResponse(t, `{"errors": [{"message": "should not list"}]}`, http.StatusOK)
+
+	results, err := client.ForEachAccount(func(ctx context.Context, account AccountOutline) (interface{}, error) {
+		return account.ID, nil
+	}, FanOutAccountIDs(7, 8), FanOutInterval(time.Millisecond))
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 7, results[0].Value)
+	assert.Equal(t, 8, results[1].Value)
+}
+
+func TestForEachAccount_noAccountIDs(t *testing.T) {
+	t.Parallel()
+	client := newMockResponse(t, `{"errors": [{"message": "should not list"}]}`, http.StatusOK)
+
+	var filtered []int
+	results, err := client.ForEachAccount(func(ctx context.Context, account AccountOutline) (interface{}, error) {
+		t.Errorf("operation run for account %d", account.ID)
+		return nil, nil
+	}, FanOutAccountIDs(filtered...))
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestForEachAccount_listError(t *testing.T) {
+	t.Parallel()
+	client := newMockResponse(t, `{"errors": [{"message": "failed"}]}`, http.StatusOK)
+
+	results, err := client.ForEachAccount(func(ctx context.Context, account AccountOutline) (interface{}, error) {
+		t.Fatal("operation should not be run")
+		return nil, nil
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, results)
+}
+
+func TestForEachAccount_invalidOption(t *testing.T) {
+	t.Parallel()
+	client := newMockResponse(t, testListAccountsResponseJSON, http.StatusOK)
+
+	_, err := client.ForEachAccount(nil, FanOutConcurrency(0))
+	assert.Error(t, err)
+}
+
+func TestForEachAccountWithContext_canceled(t *testing.T) {
+	t.Parallel()
+	client := newMockResponse(t, testListAccountsResponseJSON, http.StatusOK)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results, err := client.ForEachAccountWithContext(ctx, func(ctx context.Context, account AccountOutline) (interface{}, error) {
+		cancel()
+		return account.ID, nil
+	}, FanOutAccountIDs(1, 2, 3), FanOutConcurrency(1))
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, 1, results[0].Value)
+	assert.Equal(t, context.Canceled, results[2].Err)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/newrelic/newrelic-client-go/internal/logging"
 	"github.com/newrelic/newrelic-client-go/pkg/config"
 	"github.com/newrelic/newrelic-client-go/pkg/infrastructure"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // Alerts is used to communicate with New Relic Alerts.
@@ -37,3 +38,22 @@ func New(config config.Config) Alerts {
 
 	return pkg
 }
+
+// canQueryNerdGraph returns whether the client can look up objects of its
+// account with NerdGraph, which requires a personal API key.  Clients of only
+// an admin API key list them from the REST API instead.
+func (a *Alerts) canQueryNerdGraph() bool {
+	return a.config.AccountID != 0 && a.config.PersonalAPIKey != ""
+}
+
+// findItem reads the items of an iterator until one matches, so that no page
+// past the one holding it is fetched.  It returns nil when none matches.
+func findItem(it *paging.Iterator, match func(item interface{}) bool) (interface{}, error) {
+	for it.Next() {
+		if match(it.Value()) {
+			return it.Value(), nil
+		}
+	}
+
+	return nil, it.Err()
+}
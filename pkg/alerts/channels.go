@@ -6,7 +6,9 @@ import (
 
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/internal/serialization"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // ChannelType specifies the channel type used when creating the alert channel.
@@ -82,43 +84,55 @@ func (a *Alerts) ListChannels() ([]*Channel, error) {
 // ListChannelsWithContext returns all alert channels for a given account.
 func (a *Alerts) ListChannelsWithContext(ctx context.Context) ([]*Channel, error) {
 	alertChannels := []*Channel{}
-	nextURL := a.config.Region().RestURL("/alerts_channels.json")
 
-	for nextURL != "" {
-		response := alertChannelsResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, nil, &response)
-
-		if err != nil {
-			return nil, err
-		}
-
-		alertChannels = append(alertChannels, response.Channels...)
+	it := a.ListChannelsIteratorWithContext(ctx)
+	for it.Next() {
+		alertChannels = append(alertChannels, it.Value().(*Channel))
+	}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return alertChannels, nil
 }
 
+// ListChannelsIterator returns an iterator over the alert channels for a given account.
+func (a *Alerts) ListChannelsIterator(opts ...paging.Option) *paging.Iterator {
+	return a.ListChannelsIteratorWithContext(context.Background(), opts...)
+}
+
+// ListChannelsIteratorWithContext returns an iterator over the alert channels for a given account.
+func (a *Alerts) ListChannelsIteratorWithContext(ctx context.Context, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("/alerts_channels.json"), nil,
+		func() interface{} { return &alertChannelsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*alertChannelsResponse).Channels) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 // GetChannel returns a specific alert channel by ID for a given account.
 func (a *Alerts) GetChannel(id int) (*Channel, error) {
 	return a.GetChannelWithContext(context.Background(), id)
 }
 
-// GetChannelWithContext returns a specific alert channel by ID for a given account.
+// GetChannelWithContext returns a specific alert channel by ID for a given
+// account.  Neither the REST API nor NerdGraph looking channels up by ID,
+// they are listed until the channel is found.
 func (a *Alerts) GetChannelWithContext(ctx context.Context, id int) (*Channel, error) {
-	channels, err := a.ListChannelsWithContext(ctx)
+	channel, err := findItem(a.ListChannelsIteratorWithContext(ctx), func(item interface{}) bool {
+		return item.(*Channel).ID == id
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, channel := range channels {
-		if channel.ID == id {
-			return channel, nil
-		}
+	if channel == nil {
+		return nil, errors.NewNotFoundf("no channel found for id %d", id)
 	}
-	return nil, errors.NewNotFoundf("no channel found for id %d", id)
+
+	return channel.(*Channel), nil
 }
 
 // CreateChannel creates an alert channel within a given account.
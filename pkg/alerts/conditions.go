@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // ConditionType specifies the condition type used when creating the alert condition.
@@ -210,27 +212,36 @@ func (a *Alerts) ListConditions(policyID int) ([]*Condition, error) {
 // ListConditionsWithContext returns alert conditions for a specified policy.
 func (a *Alerts) ListConditionsWithContext(ctx context.Context, policyID int) ([]*Condition, error) {
 	alertConditions := []*Condition{}
-	queryParams := listConditionsParams{
-		PolicyID: policyID,
-	}
 
-	nextURL := a.config.Region().RestURL("/alerts_conditions.json")
+	it := a.ListConditionsIteratorWithContext(ctx, policyID)
+	for it.Next() {
+		alertConditions = append(alertConditions, it.Value().(*Condition))
+	}
 
-	for nextURL != "" {
-		response := alertConditionsResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, &queryParams, &response)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	return alertConditions, nil
+}
 
-		alertConditions = append(alertConditions, response.Conditions...)
+// ListConditionsIterator returns an iterator over the alert conditions for a specified policy.
+func (a *Alerts) ListConditionsIterator(policyID int, opts ...paging.Option) *paging.Iterator {
+	return a.ListConditionsIteratorWithContext(context.Background(), policyID, opts...)
+}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+// ListConditionsIteratorWithContext returns an iterator over the alert conditions for a specified policy.
+func (a *Alerts) ListConditionsIteratorWithContext(ctx context.Context, policyID int, opts ...paging.Option) *paging.Iterator {
+	queryParams := listConditionsParams{
+		PolicyID: policyID,
 	}
 
-	return alertConditions, nil
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("/alerts_conditions.json"), &queryParams,
+		func() interface{} { return &alertConditionsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*alertConditionsResponse).Conditions) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // GetCondition gets an alert condition for a specified policy ID and condition ID.
@@ -239,19 +250,21 @@ func (a *Alerts) GetCondition(policyID int, id int) (*Condition, error) {
 }
 
 // GetConditionWithContext gets an alert condition for a specified policy ID and condition ID.
+// Neither the REST API nor NerdGraph looking these conditions up by ID, the
+// conditions of the policy are listed until the condition is found.
 func (a *Alerts) GetConditionWithContext(ctx context.Context, policyID int, id int) (*Condition, error) {
-	conditions, err := a.ListConditionsWithContext(ctx, policyID)
+	condition, err := findItem(a.ListConditionsIteratorWithContext(ctx, policyID), func(item interface{}) bool {
+		return item.(*Condition).ID == id
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, condition := range conditions {
-		if condition.ID == id {
-			return condition, nil
-		}
+	if condition == nil {
+		return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, id)
 	}
 
-	return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, id)
+	return condition.(*Condition), nil
 }
 
 // CreateCondition creates an alert condition for a specified policy.
@@ -261,6 +274,10 @@ func (a *Alerts) CreateCondition(policyID int, condition Condition) (*Condition,
 
 // CreateConditionWithContext creates an alert condition for a specified policy.
 func (a *Alerts) CreateConditionWithContext(ctx context.Context, policyID int, condition Condition) (*Condition, error) {
+	if err := a.validate(&condition); err != nil {
+		return nil, err
+	}
+
 	reqBody := alertConditionRequestBody{
 		Condition: condition,
 	}
@@ -283,6 +300,10 @@ func (a *Alerts) UpdateCondition(condition Condition) (*Condition, error) {
 
 // UpdateConditionWithContext updates an alert condition.
 func (a *Alerts) UpdateConditionWithContext(ctx context.Context, condition Condition) (*Condition, error) {
+	if err := a.validate(&condition); err != nil {
+		return nil, err
+	}
+
 	reqBody := alertConditionRequestBody{
 		Condition: condition,
 	}
@@ -3,7 +3,9 @@ package alerts
 import (
 	"context"
 
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/internal/serialization"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // AlertEvent response struct
@@ -40,25 +42,34 @@ func (a *Alerts) ListAlertEvents(params *ListAlertEventsParams) ([]*AlertEvent,
 // ListAlertEventsWithContext is used to retrieve New Relic alert events
 func (a *Alerts) ListAlertEventsWithContext(ctx context.Context, params *ListAlertEventsParams) ([]*AlertEvent, error) {
 	alertEvents := []*AlertEvent{}
-	nextURL := a.config.Region().RestURL("alerts_events.json")
 
-	for nextURL != "" {
-		response := alertEventsResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, &params, &response)
-
-		if err != nil {
-			return nil, err
-		}
-
-		alertEvents = append(alertEvents, response.AlertEvents...)
+	it := a.ListAlertEventsIteratorWithContext(ctx, params)
+	for it.Next() {
+		alertEvents = append(alertEvents, it.Value().(*AlertEvent))
+	}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return alertEvents, nil
 }
 
+// ListAlertEventsIterator returns an iterator over the New Relic alert events.
+func (a *Alerts) ListAlertEventsIterator(params *ListAlertEventsParams, opts ...paging.Option) *paging.Iterator {
+	return a.ListAlertEventsIteratorWithContext(context.Background(), params, opts...)
+}
+
+// ListAlertEventsIteratorWithContext returns an iterator over the New Relic alert events.
+func (a *Alerts) ListAlertEventsIteratorWithContext(ctx context.Context, params *ListAlertEventsParams, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("alerts_events.json"), &params,
+		func() interface{} { return &alertEventsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*alertEventsResponse).AlertEvents) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 type alertEventsResponse struct {
 	AlertEvents []*AlertEvent `json:"alert_events,omitempty"`
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/internal/serialization"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // Incident represents a New Relic alert incident.
@@ -30,28 +32,37 @@ func (a *Alerts) ListIncidents(onlyOpen bool, excludeViolations bool) ([]*Incide
 // ListIncidentsWithContext returns all alert incidents.
 func (a *Alerts) ListIncidentsWithContext(ctx context.Context, onlyOpen bool, excludeViolations bool) ([]*Incident, error) {
 	incidents := []*Incident{}
-	queryParams := listIncidentsParams{
-		OnlyOpen:          onlyOpen,
-		ExcludeViolations: excludeViolations,
-	}
 
-	nextURL := a.config.Region().RestURL("/alerts_incidents.json")
+	it := a.ListIncidentsIteratorWithContext(ctx, onlyOpen, excludeViolations)
+	for it.Next() {
+		incidents = append(incidents, it.Value().(*Incident))
+	}
 
-	for nextURL != "" {
-		incidentsResponse := alertIncidentsResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, queryParams, &incidentsResponse)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	return incidents, nil
+}
 
-		incidents = append(incidents, incidentsResponse.Incidents...)
+// ListIncidentsIterator returns an iterator over the alert incidents.
+func (a *Alerts) ListIncidentsIterator(onlyOpen bool, excludeViolations bool, opts ...paging.Option) *paging.Iterator {
+	return a.ListIncidentsIteratorWithContext(context.Background(), onlyOpen, excludeViolations, opts...)
+}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+// ListIncidentsIteratorWithContext returns an iterator over the alert incidents.
+func (a *Alerts) ListIncidentsIteratorWithContext(ctx context.Context, onlyOpen bool, excludeViolations bool, opts ...paging.Option) *paging.Iterator {
+	queryParams := listIncidentsParams{
+		OnlyOpen:          onlyOpen,
+		ExcludeViolations: excludeViolations,
 	}
 
-	return incidents, nil
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("/alerts_incidents.json"), queryParams,
+		func() interface{} { return &alertIncidentsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*alertIncidentsResponse).Incidents) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // AcknowledgeIncident acknowledges an existing incident.
@@ -81,6 +81,10 @@ func (a *Alerts) CreateInfrastructureCondition(condition InfrastructureCondition
 
 // CreateInfrastructureConditionWithContext is used to create a New Relic Infrastructure alert condition.
 func (a *Alerts) CreateInfrastructureConditionWithContext(ctx context.Context, condition InfrastructureCondition) (*InfrastructureCondition, error) {
+	if err := a.validate(&condition); err != nil {
+		return nil, err
+	}
+
 	resp := infrastructureConditionResponse{}
 	reqBody := infrastructureConditionRequest{condition}
 
@@ -100,6 +104,10 @@ func (a *Alerts) UpdateInfrastructureCondition(condition InfrastructureCondition
 
 // UpdateInfrastructureConditionWithContext is used to update a New Relic Infrastructure alert condition.
 func (a *Alerts) UpdateInfrastructureConditionWithContext(ctx context.Context, condition InfrastructureCondition) (*InfrastructureCondition, error) {
+	if err := a.validate(&condition); err != nil {
+		return nil, err
+	}
+
 	resp := infrastructureConditionResponse{}
 	reqBody := infrastructureConditionRequest{condition}
 
@@ -0,0 +1,248 @@
+// +build unit
+
+package alerts
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/testhelpers/fakeserver"
+)
+
+const (
+	lookupTestPolicies   = 2000
+	lookupTestConditions = 500
+	lookupTestPageSize   = 50
+)
+
+// newLookupTestServer seeds a fake server with many policies, the first of
+// them holding many conditions, and returns a client of the given account
+// along with its count of requests.
+func newLookupTestServer(tb testing.TB, accountID int) (Alerts, *int64, []int, []int) {
+	server := fakeserver.New(tb)
+	server.SetPageSize(lookupTestPageSize)
+
+	policyIDs := make([]int, lookupTestPolicies)
+	for i := range policyIDs {
+		id := server.Seed(fakeserver.Resources.AlertPolicies, map[string]interface{}{
+			"name":                fmt.Sprintf("policy %04d", i),
+			"incident_preference": "PER_POLICY",
+		})
+		policyIDs[i], _ = strconv.Atoi(id)
+	}
+
+	conditionIDs := make([]int, lookupTestConditions)
+	for i := range conditionIDs {
+		id := server.Seed(fakeserver.Resources.AlertConditions, map[string]interface{}{
+			"name": fmt.Sprintf("condition %04d", i),
+			"type": "apm_app_metric",
+		}, strconv.Itoa(policyIDs[0]))
+		conditionIDs[i], _ = strconv.Atoi(id)
+	}
+
+	var requests int64
+	cfg := server.Config()
+	cfg.AccountID = accountID
+	cfg.Middleware = append(cfg.Middleware, config.MiddlewareFuncs{
+		Before: func(*http.Request) error {
+			atomic.AddInt64(&requests, 1)
+			return nil
+		},
+	})
+
+	return New(cfg), &requests, policyIDs, conditionIDs
+}
+
+func TestGetPolicy_lookups(t *testing.T) {
+	t.Parallel()
+
+	client, requests, policyIDs, _ := newLookupTestServer(t, 1)
+	last := policyIDs[len(policyIDs)-1]
+
+	// The policy is queried by ID, then listed by name.
+	policy, err := client.GetPolicy(last)
+	require.NoError(t, err)
+	assert.Equal(t, last, policy.ID)
+	assert.Equal(t, "policy 1999", policy.Name)
+	assert.Equal(t, int64(2), atomic.SwapInt64(requests, 0))
+
+	// Missing policies fall back to listing every policy.
+	_, err = client.GetPolicy(-1)
+	assert.IsType(t, &errors.NotFound{}, err)
+	assert.Equal(t, int64(1+lookupTestPolicies/lookupTestPageSize), atomic.SwapInt64(requests, 0))
+
+	// Without an account, the policies are listed until found.
+	client, requests, policyIDs, _ = newLookupTestServer(t, 0)
+
+	policy, err = client.GetPolicy(policyIDs[lookupTestPageSize])
+	require.NoError(t, err)
+	assert.Equal(t, policyIDs[lookupTestPageSize], policy.ID)
+	assert.Equal(t, int64(2), atomic.LoadInt64(requests))
+}
+
+func TestGetPolicy_nerdGraphError(t *testing.T) {
+	t.Parallel()
+
+	var queries int64
+	server := fakeserver.New(t)
+	server.HandleNerdGraph("policy", func(map[string]interface{}) (interface{}, error) {
+		atomic.AddInt64(&queries, 1)
+		return nil, &errors.GraphQLError{Message: "Access denied", Extensions: errors.GraphQLErrorExtensions{ErrorClass: errors.GraphQLErrorClassForbidden}}
+	})
+	id := server.Seed(fakeserver.Resources.AlertPolicies, map[string]interface{}{"name": "policy"})
+	policyID, _ := strconv.Atoi(id)
+
+	cfg := server.Config()
+	cfg.AccountID = 1
+	client := New(cfg)
+
+	// Errors of NerdGraph fall back to listing the policies.
+	policy, err := client.GetPolicy(policyID)
+	require.NoError(t, err)
+	assert.Equal(t, policyID, policy.ID)
+	assert.Equal(t, int64(1), atomic.SwapInt64(&queries, 0))
+
+	// Clients of only an admin API key do not query NerdGraph.
+	cfg.PersonalAPIKey = ""
+	client = New(cfg)
+
+	policy, err = client.GetPolicy(policyID)
+	require.NoError(t, err)
+	assert.Equal(t, policyID, policy.ID)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&queries))
+}
+
+func TestGetCondition_stopsPaging(t *testing.T) {
+	t.Parallel()
+
+	client, requests, policyIDs, conditionIDs := newLookupTestServer(t, 0)
+
+	condition, err := client.GetCondition(policyIDs[0], conditionIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, conditionIDs[0], condition.ID)
+	assert.Equal(t, int64(1), atomic.SwapInt64(requests, 0))
+
+	_, err = client.GetCondition(policyIDs[0], -1)
+	assert.IsType(t, &errors.NotFound{}, err)
+	assert.Equal(t, int64(lookupTestConditions/lookupTestPageSize), atomic.LoadInt64(requests))
+}
+
+func TestGetNrqlCondition_nerdGraph(t *testing.T) {
+	t.Parallel()
+
+	server := fakeserver.New(t)
+	policyID := server.Seed(fakeserver.Resources.AlertPolicies, map[string]interface{}{"name": "policy"})
+	id := server.Seed(fakeserver.Resources.AlertNrqlConditions, map[string]interface{}{"name": "listed"}, policyID)
+
+	threshold := 1.0
+	valueFunction := NrqlConditionValueFunctions.SingleValue
+	found := &NrqlAlertCondition{
+		ID:       id,
+		PolicyID: policyID,
+		NrqlConditionBase: NrqlConditionBase{
+			Enabled:            true,
+			Name:               "queried",
+			Nrql:               NrqlConditionQuery{Query: "SELECT count(*) FROM Transactions", EvaluationOffset: 3},
+			Type:               NrqlConditionTypes.Static,
+			ViolationTimeLimit: NrqlConditionViolationTimeLimits.OneHour,
+			Terms: []NrqlConditionTerm{{
+				Operator:             AlertsNRQLConditionTermsOperatorTypes.ABOVE,
+				Priority:             NrqlConditionPriorities.Critical,
+				Threshold:            &threshold,
+				ThresholdDuration:    300,
+				ThresholdOccurrences: ThresholdOccurrences.All,
+			}},
+		},
+		ValueFunction: &valueFunction,
+	}
+
+	server.HandleNerdGraph("nrqlCondition", func(vars map[string]interface{}) (interface{}, error) {
+		if vars["id"] != id {
+			return nil, &errors.GraphQLError{Message: "not found", DownstreamResponse: []errors.GraphQLDownstreamResponse{{Message: "Not Found"}}}
+		}
+
+		return map[string]interface{}{"actor": map[string]interface{}{"account": map[string]interface{}{"alerts": map[string]interface{}{
+			"nrqlCondition": found,
+		}}}}, nil
+	})
+
+	cfg := server.Config()
+	cfg.AccountID = 1
+	client := New(cfg)
+
+	pID, _ := strconv.Atoi(policyID)
+	cID, _ := strconv.Atoi(id)
+
+	condition, err := client.GetNrqlCondition(pID, cID)
+	require.NoError(t, err)
+	assert.Equal(t, &NrqlCondition{
+		Enabled:             true,
+		ID:                  cID,
+		Name:                "queried",
+		Nrql:                NrqlQuery{Query: "SELECT count(*) FROM Transactions", SinceValue: "3"},
+		Terms:               []ConditionTerm{{Duration: 5, Operator: OperatorTypes.Above, Priority: PriorityTypes.Critical, Threshold: 1, TimeFunction: TimeFunctionTypes.All}},
+		Type:                "static",
+		ValueFunction:       ValueFunctionTypes.SingleValue,
+		ViolationCloseTimer: 3600,
+	}, condition)
+
+	_, err = client.GetNrqlCondition(pID+1, cID)
+	assert.IsType(t, &errors.NotFound{}, err)
+
+	// Conditions the REST API can not represent are listed.
+	found.Terms[0].ThresholdDuration = 90
+	condition, err = client.GetNrqlCondition(pID, cID)
+	require.NoError(t, err)
+	assert.Equal(t, "listed", condition.Name)
+
+	_, err = client.GetNrqlCondition(pID, cID+1)
+	assert.IsType(t, &errors.NotFound{}, err)
+}
+
+// BenchmarkGetPolicy looks up the last of many policies, the worst case of
+// listing them.
+func BenchmarkGetPolicy(b *testing.B) {
+	benchmarks := map[string]int{
+		"nerdgraph": 1,
+		"fallback":  0,
+	}
+
+	for name, accountID := range benchmarks {
+		b.Run(name, func(b *testing.B) {
+			client, requests, policyIDs, _ := newLookupTestServer(b, accountID)
+			last := policyIDs[len(policyIDs)-1]
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := client.GetPolicy(last); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(atomic.LoadInt64(requests))/float64(b.N), "requests/op")
+		})
+	}
+}
+
+// BenchmarkGetCondition looks up the first of many conditions of a policy,
+// only its page being fetched.
+func BenchmarkGetCondition(b *testing.B) {
+	client, requests, policyIDs, conditionIDs := newLookupTestServer(b, 0)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.GetCondition(policyIDs[0], conditionIDs[0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportMetric(float64(atomic.LoadInt64(requests))/float64(b.N), "requests/op")
+}
@@ -3,10 +3,12 @@ package alerts
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // AlertsNrqlConditionExpiration
@@ -264,27 +266,36 @@ func (a *Alerts) ListNrqlConditions(policyID int) ([]*NrqlCondition, error) {
 // ListNrqlConditionsWithContext returns NRQL alert conditions for a specified policy.
 func (a *Alerts) ListNrqlConditionsWithContext(ctx context.Context, policyID int) ([]*NrqlCondition, error) {
 	conditions := []*NrqlCondition{}
-	queryParams := listNrqlConditionsParams{
-		PolicyID: policyID,
-	}
 
-	nextURL := a.config.Region().RestURL("/alerts_nrql_conditions.json")
+	it := a.ListNrqlConditionsIteratorWithContext(ctx, policyID)
+	for it.Next() {
+		conditions = append(conditions, it.Value().(*NrqlCondition))
+	}
 
-	for nextURL != "" {
-		response := nrqlConditionsResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, &queryParams, &response)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	return conditions, nil
+}
 
-		conditions = append(conditions, response.NrqlConditions...)
+// ListNrqlConditionsIterator returns an iterator over the NRQL alert conditions for a specified policy.
+func (a *Alerts) ListNrqlConditionsIterator(policyID int, opts ...paging.Option) *paging.Iterator {
+	return a.ListNrqlConditionsIteratorWithContext(context.Background(), policyID, opts...)
+}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+// ListNrqlConditionsIteratorWithContext returns an iterator over the NRQL alert conditions for a specified policy.
+func (a *Alerts) ListNrqlConditionsIteratorWithContext(ctx context.Context, policyID int, opts ...paging.Option) *paging.Iterator {
+	queryParams := listNrqlConditionsParams{
+		PolicyID: policyID,
 	}
 
-	return conditions, nil
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("/alerts_nrql_conditions.json"), &queryParams,
+		func() interface{} { return &nrqlConditionsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*nrqlConditionsResponse).NrqlConditions) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // GetNrqlCondition gets information about a NRQL alert condition
@@ -294,20 +305,44 @@ func (a *Alerts) GetNrqlCondition(policyID int, id int) (*NrqlCondition, error)
 }
 
 // GetNrqlConditionWithContext gets information about a NRQL alert condition
-// for a specified policy ID and condition ID.
+// for a specified policy ID and condition ID.  When the account ID and
+// personal API key of the client are configured, the condition is queried by
+// ID from NerdGraph.  Otherwise, or when NerdGraph fails, does not find the
+// condition or it can not be represented in the REST API, the conditions of
+// the policy are listed until it is found.
 func (a *Alerts) GetNrqlConditionWithContext(ctx context.Context, policyID int, id int) (*NrqlCondition, error) {
-	conditions, err := a.ListNrqlConditionsWithContext(ctx, policyID)
+	if a.canQueryNerdGraph() {
+		condition, err := a.GetNrqlConditionQueryWithContext(ctx, a.config.AccountID, strconv.Itoa(id))
+
+		switch {
+		case err != nil:
+			a.logger.Debug(fmt.Sprintf("listing NRQL conditions of policy %d: %s", policyID, err))
+		case condition.ID != "":
+			if condition.PolicyID != strconv.Itoa(policyID) {
+				return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, id)
+			}
+
+			c, convErr := nrqlConditionFromNerdGraph(condition)
+			if convErr == nil {
+				return c, nil
+			}
+
+			a.logger.Debug(fmt.Sprintf("listing NRQL conditions of policy %d: %s", policyID, convErr))
+		}
+	}
+
+	condition, err := findItem(a.ListNrqlConditionsIteratorWithContext(ctx, policyID), func(item interface{}) bool {
+		return item.(*NrqlCondition).ID == id
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, condition := range conditions {
-		if condition.ID == id {
-			return condition, nil
-		}
+	if condition == nil {
+		return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, id)
 	}
 
-	return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, id)
+	return condition.(*NrqlCondition), nil
 }
 
 // CreateNrqlCondition creates a NRQL alert condition.
@@ -425,27 +460,51 @@ func (a *Alerts) SearchNrqlConditionsQueryWithContext(
 	searchCriteria NrqlConditionsSearchCriteria,
 ) ([]*NrqlAlertCondition, error) {
 	conditions := []*NrqlAlertCondition{}
-	var nextCursor *string
-
-	for ok := true; ok; ok = nextCursor != nil {
-		resp := searchNrqlConditionsResponse{}
-		vars := map[string]interface{}{
-			"accountId":      accountID,
-			"searchCriteria": searchCriteria,
-			"cursor":         nextCursor,
-		}
 
-		if err := a.client.NerdGraphQueryWithContext(ctx, searchNrqlConditionsQuery, vars, &resp); err != nil {
-			return nil, err
-		}
+	it := a.SearchNrqlConditionsQueryIteratorWithContext(ctx, accountID, searchCriteria)
+	for it.Next() {
+		conditions = append(conditions, it.Value().(*NrqlAlertCondition))
+	}
 
-		conditions = append(conditions, resp.Actor.Account.Alerts.NrqlConditionsSearch.NrqlConditions...)
-		nextCursor = resp.Actor.Account.Alerts.NrqlConditionsSearch.NextCursor
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return conditions, nil
 }
 
+// SearchNrqlConditionsQueryIterator returns an iterator over the NRQL alert conditions matching the provided search criteria.
+func (a *Alerts) SearchNrqlConditionsQueryIterator(
+	accountID int,
+	searchCriteria NrqlConditionsSearchCriteria,
+	opts ...paging.Option,
+) *paging.Iterator {
+	return a.SearchNrqlConditionsQueryIteratorWithContext(context.Background(), accountID, searchCriteria, opts...)
+}
+
+// SearchNrqlConditionsQueryIteratorWithContext returns an iterator over the NRQL alert conditions matching the provided search criteria.
+func (a *Alerts) SearchNrqlConditionsQueryIteratorWithContext(
+	ctx context.Context,
+	accountID int,
+	searchCriteria NrqlConditionsSearchCriteria,
+	opts ...paging.Option,
+) *paging.Iterator {
+	vars := map[string]interface{}{
+		"accountId":      accountID,
+		"searchCriteria": searchCriteria,
+	}
+
+	fetch := a.client.NerdGraphCursorFetcher(searchNrqlConditionsQuery, vars,
+		func() interface{} { return &searchNrqlConditionsResponse{} },
+		func(resp interface{}) ([]interface{}, *string) {
+			search := resp.(*searchNrqlConditionsResponse).Actor.Account.Alerts.NrqlConditionsSearch
+			return http.Items(search.NrqlConditions), search.NextCursor
+		},
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 // CreateNrqlConditionBaselineMutation creates a baseline NRQL alert condition via New Relic's NerdGraph API.
 func (a *Alerts) CreateNrqlConditionBaselineMutation(
 	accountID int,
@@ -462,6 +521,10 @@ func (a *Alerts) CreateNrqlConditionBaselineMutationWithContext(
 	policyID string,
 	nrqlCondition NrqlConditionInput,
 ) (*NrqlAlertCondition, error) {
+	if err := a.validateNrqlCondition(NrqlConditionTypes.Baseline, nrqlCondition, false); err != nil {
+		return nil, err
+	}
+
 	resp := nrqlConditionBaselineCreateResponse{}
 	vars := map[string]interface{}{
 		"accountId": accountID,
@@ -492,6 +555,10 @@ func (a *Alerts) UpdateNrqlConditionBaselineMutationWithContext(
 	conditionID string,
 	nrqlCondition NrqlConditionInput,
 ) (*NrqlAlertCondition, error) {
+	if err := a.validateNrqlCondition(NrqlConditionTypes.Baseline, nrqlCondition, true); err != nil {
+		return nil, err
+	}
+
 	resp := nrqlConditionBaselineUpdateResponse{}
 	vars := map[string]interface{}{
 		"accountId": accountID,
@@ -522,6 +589,10 @@ func (a *Alerts) CreateNrqlConditionStaticMutationWithContext(
 	policyID string,
 	nrqlCondition NrqlConditionInput,
 ) (*NrqlAlertCondition, error) {
+	if err := a.validateNrqlCondition(NrqlConditionTypes.Static, nrqlCondition, false); err != nil {
+		return nil, err
+	}
+
 	resp := nrqlConditionStaticCreateResponse{}
 	vars := map[string]interface{}{
 		"accountId": accountID,
@@ -552,6 +623,10 @@ func (a *Alerts) UpdateNrqlConditionStaticMutationWithContext(
 	conditionID string,
 	nrqlCondition NrqlConditionInput,
 ) (*NrqlAlertCondition, error) {
+	if err := a.validateNrqlCondition(NrqlConditionTypes.Static, nrqlCondition, true); err != nil {
+		return nil, err
+	}
+
 	resp := nrqlConditionStaticUpdateResponse{}
 	vars := map[string]interface{}{
 		"accountId": accountID,
@@ -582,6 +657,10 @@ func (a *Alerts) CreateNrqlConditionOutlierMutationWithContext(
 	policyID string,
 	nrqlCondition NrqlConditionInput,
 ) (*NrqlAlertCondition, error) {
+	if err := a.validateNrqlCondition(NrqlConditionTypes.Outlier, nrqlCondition, false); err != nil {
+		return nil, err
+	}
+
 	resp := nrqlConditionOutlierCreateResponse{}
 	vars := map[string]interface{}{
 		"accountId": accountID,
@@ -612,6 +691,10 @@ func (a *Alerts) UpdateNrqlConditionOutlierMutationWithContext(
 	conditionID string,
 	nrqlCondition NrqlConditionInput,
 ) (*NrqlAlertCondition, error) {
+	if err := a.validateNrqlCondition(NrqlConditionTypes.Outlier, nrqlCondition, true); err != nil {
+		return nil, err
+	}
+
 	resp := nrqlConditionOutlierUpdateResponse{}
 	vars := map[string]interface{}{
 		"accountId": accountID,
@@ -626,6 +709,74 @@ func (a *Alerts) UpdateNrqlConditionOutlierMutationWithContext(
 	return &resp.AlertsNrqlConditionOutlierUpdate, nil
 }
 
+// CreateNrqlConditionMutation creates a NRQL alert condition of any type via
+// New Relic's NerdGraph API, with the mutation of its type.
+func (a *Alerts) CreateNrqlConditionMutation(
+	accountID int,
+	policyID string,
+	nrqlCondition NrqlConditionInput,
+) (*NrqlAlertCondition, error) {
+	return a.CreateNrqlConditionMutationWithContext(context.Background(), accountID, policyID, nrqlCondition)
+}
+
+// CreateNrqlConditionMutationWithContext creates a NRQL alert condition of any
+// type via New Relic's NerdGraph API, with the mutation of its type.  The
+// condition is validated as by the mutation of its type, its type being
+// required.
+func (a *Alerts) CreateNrqlConditionMutationWithContext(
+	ctx context.Context,
+	accountID int,
+	policyID string,
+	nrqlCondition NrqlConditionInput,
+) (*NrqlAlertCondition, error) {
+	switch nrqlCondition.Type {
+	case NrqlConditionTypes.Baseline:
+		return a.CreateNrqlConditionBaselineMutationWithContext(ctx, accountID, policyID, nrqlCondition)
+	case NrqlConditionTypes.Outlier:
+		return a.CreateNrqlConditionOutlierMutationWithContext(ctx, accountID, policyID, nrqlCondition)
+	case NrqlConditionTypes.Static:
+		return a.CreateNrqlConditionStaticMutationWithContext(ctx, accountID, policyID, nrqlCondition)
+	default:
+		v := validator{}
+		nrqlCondition.validateType(&v, false)
+		return nil, v.err("NRQL condition", nrqlCondition.Name)
+	}
+}
+
+// UpdateNrqlConditionMutation updates a NRQL alert condition of any type via
+// New Relic's NerdGraph API, with the mutation of its type.
+func (a *Alerts) UpdateNrqlConditionMutation(
+	accountID int,
+	conditionID string,
+	nrqlCondition NrqlConditionInput,
+) (*NrqlAlertCondition, error) {
+	return a.UpdateNrqlConditionMutationWithContext(context.Background(), accountID, conditionID, nrqlCondition)
+}
+
+// UpdateNrqlConditionMutationWithContext updates a NRQL alert condition of any
+// type via New Relic's NerdGraph API, with the mutation of its type.  The
+// condition is validated as by the mutation of its type, its type being
+// required.
+func (a *Alerts) UpdateNrqlConditionMutationWithContext(
+	ctx context.Context,
+	accountID int,
+	conditionID string,
+	nrqlCondition NrqlConditionInput,
+) (*NrqlAlertCondition, error) {
+	switch nrqlCondition.Type {
+	case NrqlConditionTypes.Baseline:
+		return a.UpdateNrqlConditionBaselineMutationWithContext(ctx, accountID, conditionID, nrqlCondition)
+	case NrqlConditionTypes.Outlier:
+		return a.UpdateNrqlConditionOutlierMutationWithContext(ctx, accountID, conditionID, nrqlCondition)
+	case NrqlConditionTypes.Static:
+		return a.UpdateNrqlConditionStaticMutationWithContext(ctx, accountID, conditionID, nrqlCondition)
+	default:
+		v := validator{}
+		nrqlCondition.validateType(&v, false)
+		return nil, v.err("NRQL condition", nrqlCondition.Name)
+	}
+}
+
 func (a *Alerts) DeleteNrqlConditionMutation(
 	accountID int,
 	conditionID string,
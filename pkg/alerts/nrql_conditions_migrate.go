@@ -0,0 +1,212 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NrqlConditionInputFromREST converts a NRQL alert condition of the REST API
+// into the input creating it via New Relic's NerdGraph API, such as with
+// CreateNrqlConditionMutation.
+//
+// The durations of the terms are converted from minutes to seconds, the time
+// functions to threshold occurrences, and the since value of the query to its
+// evaluation offset.  Conditions of the REST API do not hold the direction of
+// baseline conditions, which must be given for them and is ignored otherwise.
+func NrqlConditionInputFromREST(condition NrqlCondition, baselineDirection *NrqlBaselineDirection) (*NrqlConditionInput, error) {
+	conditionType := NrqlConditionType(strings.ToUpper(condition.Type))
+	if conditionType == "" {
+		conditionType = NrqlConditionTypes.Static
+	}
+
+	input := NrqlConditionInput{
+		NrqlConditionBase: NrqlConditionBase{
+			Enabled:                   condition.Enabled,
+			Name:                      condition.Name,
+			Nrql:                      NrqlConditionQuery{Query: condition.Nrql.Query},
+			RunbookURL:                condition.RunbookURL,
+			Type:                      conditionType,
+			ViolationTimeLimitSeconds: condition.ViolationCloseTimer,
+		},
+	}
+
+	if condition.Nrql.SinceValue != "" {
+		since, err := strconv.Atoi(condition.Nrql.SinceValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since value %q of NRQL condition %q", condition.Nrql.SinceValue, condition.Name)
+		}
+
+		input.Nrql.EvaluationOffset = since
+	}
+
+	for _, term := range condition.Terms {
+		t, err := nrqlConditionTermFromREST(term)
+		if err != nil {
+			return nil, fmt.Errorf("NRQL condition %q: %w", condition.Name, err)
+		}
+
+		input.Terms = append(input.Terms, t)
+	}
+
+	switch conditionType {
+	case NrqlConditionTypes.Static:
+		if condition.ValueFunction != "" {
+			valueFunction := NrqlConditionValueFunction(strings.ToUpper(string(condition.ValueFunction)))
+			input.ValueFunction = &valueFunction
+		}
+	case NrqlConditionTypes.Outlier:
+		expectedGroups := condition.ExpectedGroups
+		openViolationOnGroupOverlap := !condition.IgnoreOverlap
+		input.ExpectedGroups = &expectedGroups
+		input.OpenViolationOnGroupOverlap = &openViolationOnGroupOverlap
+	case NrqlConditionTypes.Baseline:
+		if baselineDirection == nil {
+			return nil, fmt.Errorf("NRQL condition %q is a baseline condition, whose direction must be given", condition.Name)
+		}
+
+		direction := *baselineDirection
+		input.BaselineDirection = &direction
+	default:
+		return nil, fmt.Errorf("NRQL condition %q has unknown type %q", condition.Name, condition.Type)
+	}
+
+	return &input, nil
+}
+
+func nrqlConditionTermFromREST(term ConditionTerm) (NrqlConditionTerm, error) {
+	t := NrqlConditionTerm{
+		Priority:          NrqlConditionPriority(strings.ToUpper(string(term.Priority))),
+		ThresholdDuration: term.Duration * 60,
+	}
+
+	threshold := term.Threshold
+	t.Threshold = &threshold
+
+	switch term.Operator {
+	case OperatorTypes.Above:
+		t.Operator = AlertsNRQLConditionTermsOperatorTypes.ABOVE
+	case OperatorTypes.Below:
+		t.Operator = AlertsNRQLConditionTermsOperatorTypes.BELOW
+	case OperatorTypes.Equal:
+		t.Operator = AlertsNRQLConditionTermsOperatorTypes.EQUALS
+	default:
+		return t, fmt.Errorf("unknown term operator %q", term.Operator)
+	}
+
+	switch term.TimeFunction {
+	case TimeFunctionTypes.All:
+		t.ThresholdOccurrences = ThresholdOccurrences.All
+	case TimeFunctionTypes.Any:
+		t.ThresholdOccurrences = ThresholdOccurrences.AtLeastOnce
+	default:
+		return t, fmt.Errorf("unknown term time function %q", term.TimeFunction)
+	}
+
+	return t, nil
+}
+
+// nrqlConditionViolationTimeLimitSeconds are the durations in seconds of the
+// violation time limits of NerdGraph.
+var nrqlConditionViolationTimeLimitSeconds = map[NrqlConditionViolationTimeLimit]int{
+	NrqlConditionViolationTimeLimits.OneHour:         3600,
+	NrqlConditionViolationTimeLimits.TwoHours:        7200,
+	NrqlConditionViolationTimeLimits.FourHours:       14400,
+	NrqlConditionViolationTimeLimits.EightHours:      28800,
+	NrqlConditionViolationTimeLimits.TwelveHours:     43200,
+	NrqlConditionViolationTimeLimits.TwentyFourHours: 86400,
+}
+
+// nrqlConditionFromNerdGraph converts a NRQL alert condition of NerdGraph
+// into its form in the REST API, the reverse of NrqlConditionInputFromREST.
+// Conditions the REST API can not represent, such as those with terms not
+// lasting whole minutes, return an error.
+func nrqlConditionFromNerdGraph(condition *NrqlAlertCondition) (*NrqlCondition, error) {
+	id, err := strconv.Atoi(condition.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID %q of NRQL condition %q", condition.ID, condition.Name)
+	}
+
+	c := NrqlCondition{
+		Enabled:             condition.Enabled,
+		ID:                  id,
+		Name:                condition.Name,
+		Nrql:                NrqlQuery{Query: condition.Nrql.Query},
+		RunbookURL:          condition.RunbookURL,
+		Type:                strings.ToLower(string(condition.Type)),
+		ViolationCloseTimer: condition.ViolationTimeLimitSeconds,
+	}
+
+	if condition.Nrql.EvaluationOffset != 0 {
+		c.Nrql.SinceValue = strconv.Itoa(condition.Nrql.EvaluationOffset)
+	}
+
+	if c.ViolationCloseTimer == 0 && condition.ViolationTimeLimit != "" {
+		seconds, ok := nrqlConditionViolationTimeLimitSeconds[condition.ViolationTimeLimit]
+		if !ok {
+			return nil, fmt.Errorf("NRQL condition %q has unknown violation time limit %q", condition.Name, condition.ViolationTimeLimit)
+		}
+
+		c.ViolationCloseTimer = seconds
+	}
+
+	for _, term := range condition.Terms {
+		t, err := conditionTermFromNerdGraph(term)
+		if err != nil {
+			return nil, fmt.Errorf("NRQL condition %q: %w", condition.Name, err)
+		}
+
+		c.Terms = append(c.Terms, t)
+	}
+
+	if condition.ValueFunction != nil {
+		c.ValueFunction = ValueFunctionType(strings.ToLower(string(*condition.ValueFunction)))
+	}
+
+	if condition.ExpectedGroups != nil {
+		c.ExpectedGroups = *condition.ExpectedGroups
+	}
+
+	if condition.OpenViolationOnGroupOverlap != nil {
+		c.IgnoreOverlap = !*condition.OpenViolationOnGroupOverlap
+	}
+
+	return &c, nil
+}
+
+func conditionTermFromNerdGraph(term NrqlConditionTerm) (ConditionTerm, error) {
+	t := ConditionTerm{
+		Priority: PriorityType(strings.ToLower(string(term.Priority))),
+	}
+
+	if term.ThresholdDuration%60 != 0 {
+		return t, fmt.Errorf("term duration of %d seconds is not in minutes", term.ThresholdDuration)
+	}
+	t.Duration = term.ThresholdDuration / 60
+
+	if term.Threshold != nil {
+		t.Threshold = *term.Threshold
+	}
+
+	switch term.Operator {
+	case AlertsNRQLConditionTermsOperatorTypes.ABOVE:
+		t.Operator = OperatorTypes.Above
+	case AlertsNRQLConditionTermsOperatorTypes.BELOW:
+		t.Operator = OperatorTypes.Below
+	case AlertsNRQLConditionTermsOperatorTypes.EQUALS:
+		t.Operator = OperatorTypes.Equal
+	default:
+		return t, fmt.Errorf("unknown term operator %q", term.Operator)
+	}
+
+	switch term.ThresholdOccurrences {
+	case ThresholdOccurrences.All:
+		t.TimeFunction = TimeFunctionTypes.All
+	case ThresholdOccurrences.AtLeastOnce:
+		t.TimeFunction = TimeFunctionTypes.Any
+	default:
+		return t, fmt.Errorf("unknown term threshold occurrences %q", term.ThresholdOccurrences)
+	}
+
+	return t, nil
+}
@@ -3,10 +3,12 @@
 package alerts
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -283,3 +285,173 @@ func TestDeleteNrqlCondition(t *testing.T) {
 	assert.NotNil(t, actual)
 	assert.Equal(t, expected, actual)
 }
+
+func TestCreateNrqlConditionMutation(t *testing.T) {
+	t.Parallel()
+
+	var query string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Query string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		query = req.Query
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data": {
+			"alertsNrqlConditionBaselineCreate": {"id": "1", "type": "BASELINE"},
+			"alertsNrqlConditionStaticCreate": {"id": "2", "type": "STATIC"},
+			"alertsNrqlConditionOutlierCreate": {"id": "3", "type": "OUTLIER"}
+		}}`))
+		require.NoError(t, err)
+	}))
+
+	direction := NrqlBaselineDirections.UpperOnly
+	expectedGroups := 2
+
+	cases := map[NrqlConditionType]struct {
+		input    NrqlConditionInput
+		mutation string
+		id       string
+	}{
+		NrqlConditionTypes.Baseline: {NrqlConditionInput{BaselineDirection: &direction}, "alertsNrqlConditionBaselineCreate", "1"},
+		NrqlConditionTypes.Static:   {NrqlConditionInput{}, "alertsNrqlConditionStaticCreate", "2"},
+		NrqlConditionTypes.Outlier:  {NrqlConditionInput{ExpectedGroups: &expectedGroups}, "alertsNrqlConditionOutlierCreate", "3"},
+	}
+
+	for conditionType, c := range cases {
+		c.input.NrqlConditionBase = testNrqlConditionBase(conditionType)
+
+		condition, err := client.CreateNrqlConditionMutation(1, "10", c.input)
+		require.NoError(t, err)
+		assert.Contains(t, query, c.mutation)
+		assert.Equal(t, c.id, condition.ID)
+	}
+}
+
+func TestCreateNrqlConditionMutation_invalidType(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request should be made")
+	}))
+
+	direction := NrqlBaselineDirections.UpperOnly
+	valueFunction := NrqlConditionValueFunctions.Sum
+
+	cases := map[string]NrqlConditionInput{
+		`invalid NRQL condition "test": type: is required, expected one of BASELINE, STATIC or OUTLIER`: {
+			NrqlConditionBase: testNrqlConditionBase(""),
+		},
+		`invalid NRQL condition "test": type: unknown value "OTHER", expected one of BASELINE, STATIC or OUTLIER`: {
+			NrqlConditionBase: testNrqlConditionBase("OTHER"),
+		},
+		`invalid NRQL condition "test": baselineDirection: is required for BASELINE conditions`: {
+			NrqlConditionBase: testNrqlConditionBase(NrqlConditionTypes.Baseline),
+		},
+		`invalid NRQL condition "test": valueFunction: can not be set for BASELINE conditions`: {
+			NrqlConditionBase: testNrqlConditionBase(NrqlConditionTypes.Baseline),
+			BaselineDirection: &direction,
+			ValueFunction:     &valueFunction,
+		},
+		`invalid NRQL condition "test": baselineDirection: can not be set for STATIC conditions`: {
+			NrqlConditionBase: testNrqlConditionBase(NrqlConditionTypes.Static),
+			BaselineDirection: &direction,
+		},
+		`invalid NRQL condition "test": expectedGroups: is required for OUTLIER conditions`: {
+			NrqlConditionBase: testNrqlConditionBase(NrqlConditionTypes.Outlier),
+		},
+	}
+
+	for expected, input := range cases {
+		_, err := client.CreateNrqlConditionMutation(1, "10", input)
+		assert.EqualError(t, err, expected)
+	}
+
+	// Updates only check the fields set.
+	for _, input := range []NrqlConditionInput{{}, {NrqlConditionBase: NrqlConditionBase{Type: "OTHER"}}} {
+		_, err := client.UpdateNrqlConditionMutation(1, "20", input)
+		assert.Error(t, err)
+	}
+}
+
+func testNrqlConditionBase(conditionType NrqlConditionType) NrqlConditionBase {
+	threshold := 10.0
+
+	return NrqlConditionBase{
+		Name:    "test",
+		Enabled: true,
+		Type:    conditionType,
+		Nrql:    NrqlConditionQuery{Query: "SELECT count(*) FROM Transaction"},
+		Terms: []NrqlConditionTerm{{
+			Operator:             AlertsNRQLConditionTermsOperatorTypes.ABOVE,
+			Priority:             NrqlConditionPriorities.Critical,
+			Threshold:            &threshold,
+			ThresholdDuration:    300,
+			ThresholdOccurrences: ThresholdOccurrences.All,
+		}},
+	}
+}
+
+func TestNrqlConditionInputFromREST(t *testing.T) {
+	t.Parallel()
+
+	input, err := NrqlConditionInputFromREST(NrqlCondition{
+		Enabled:             true,
+		Name:                "static",
+		ViolationCloseTimer: 3600,
+		Nrql:                NrqlQuery{Query: "SELECT count(*) FROM Transaction", SinceValue: "3"},
+		RunbookURL:          "https://example.com/runbook",
+		Terms: []ConditionTerm{{
+			Duration:     5,
+			Operator:     OperatorTypes.Equal,
+			Priority:     PriorityTypes.Critical,
+			Threshold:    1,
+			TimeFunction: TimeFunctionTypes.Any,
+		}},
+		Type:          "static",
+		ValueFunction: ValueFunctionTypes.SingleValue,
+	}, nil)
+	require.NoError(t, err)
+
+	threshold := 1.0
+	valueFunction := NrqlConditionValueFunctions.SingleValue
+	assert.Equal(t, &NrqlConditionInput{
+		NrqlConditionBase: NrqlConditionBase{
+			Enabled:                   true,
+			Name:                      "static",
+			Nrql:                      NrqlConditionQuery{Query: "SELECT count(*) FROM Transaction", EvaluationOffset: 3},
+			RunbookURL:                "https://example.com/runbook",
+			Type:                      NrqlConditionTypes.Static,
+			ViolationTimeLimitSeconds: 3600,
+			Terms: []NrqlConditionTerm{{
+				Operator:             AlertsNRQLConditionTermsOperatorTypes.EQUALS,
+				Priority:             NrqlConditionPriorities.Critical,
+				Threshold:            &threshold,
+				ThresholdDuration:    300,
+				ThresholdOccurrences: ThresholdOccurrences.AtLeastOnce,
+			}},
+		},
+		ValueFunction: &valueFunction,
+	}, input)
+
+	input, err = NrqlConditionInputFromREST(NrqlCondition{Name: "outlier", Type: "outlier", ExpectedGroups: 3, IgnoreOverlap: true}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, input.ExpectedGroups)
+	assert.Equal(t, 3, *input.ExpectedGroups)
+	require.NotNil(t, input.OpenViolationOnGroupOverlap)
+	assert.False(t, *input.OpenViolationOnGroupOverlap)
+	assert.Nil(t, input.ValueFunction)
+
+	direction := NrqlBaselineDirections.UpperOnly
+	input, err = NrqlConditionInputFromREST(NrqlCondition{Name: "baseline", Type: "baseline"}, &direction)
+	require.NoError(t, err)
+	assert.Equal(t, &direction, input.BaselineDirection)
+
+	_, err = NrqlConditionInputFromREST(NrqlCondition{Name: "baseline", Type: "baseline"}, nil)
+	assert.EqualError(t, err, `NRQL condition "baseline" is a baseline condition, whose direction must be given`)
+
+	_, err = NrqlConditionInputFromREST(NrqlCondition{Name: "other", Type: "other"}, nil)
+	assert.EqualError(t, err, `NRQL condition "other" has unknown type "other"`)
+
+	_, err = NrqlConditionInputFromREST(NrqlCondition{Name: "term", Terms: []ConditionTerm{{Operator: "over"}}}, nil)
+	assert.EqualError(t, err, `NRQL condition "term": unknown term operator "over"`)
+}
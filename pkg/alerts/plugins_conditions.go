@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // PluginsCondition represents an alert condition for New Relic Plugins.
@@ -35,27 +37,38 @@ func (a *Alerts) ListPluginsConditions(policyID int) ([]*PluginsCondition, error
 // ListPluginsConditionsWithContext returns alert conditions for New Relic plugins for a given alert policy.
 func (a *Alerts) ListPluginsConditionsWithContext(ctx context.Context, policyID int) ([]*PluginsCondition, error) {
 	conditions := []*PluginsCondition{}
-	queryParams := listPluginsConditionsParams{
-		PolicyID: policyID,
-	}
 
-	nextURL := a.config.Region().RestURL("/alerts_plugins_conditions.json")
+	it := a.ListPluginsConditionsIteratorWithContext(ctx, policyID)
+	for it.Next() {
+		conditions = append(conditions, it.Value().(*PluginsCondition))
+	}
 
-	for nextURL != "" {
-		response := pluginsConditionsResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, &queryParams, &response)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	return conditions, nil
+}
 
-		conditions = append(conditions, response.PluginsConditions...)
+// ListPluginsConditionsIterator returns an iterator over the alert conditions for New Relic plugins for a given alert policy.
+func (a *Alerts) ListPluginsConditionsIterator(policyID int, opts ...paging.Option) *paging.Iterator {
+	return a.ListPluginsConditionsIteratorWithContext(context.Background(), policyID, opts...)
+}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+// ListPluginsConditionsIteratorWithContext returns an iterator over the alert conditions for New Relic plugins for a given alert policy.
+func (a *Alerts) ListPluginsConditionsIteratorWithContext(ctx context.Context, policyID int, opts ...paging.Option) *paging.Iterator {
+	queryParams := listPluginsConditionsParams{
+		PolicyID: policyID,
 	}
 
-	return conditions, nil
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("/alerts_plugins_conditions.json"), &queryParams,
+		func() interface{} { return &pluginsConditionsResponse{} },
+		func(page interface{}) []interface{} {
+			return http.Items(page.(*pluginsConditionsResponse).PluginsConditions)
+		},
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // GetPluginsCondition gets information about an alert condition for a plugin
@@ -65,21 +78,22 @@ func (a *Alerts) GetPluginsCondition(policyID int, pluginID int) (*PluginsCondit
 }
 
 // GetPluginsConditionWithContext gets information about an alert condition for a plugin
-// given a policy ID and plugin ID.
+// given a policy ID and plugin ID.  Neither the REST API nor NerdGraph looking
+// these conditions up by ID, the conditions of the policy are listed until the
+// condition is found.
 func (a *Alerts) GetPluginsConditionWithContext(ctx context.Context, policyID int, pluginID int) (*PluginsCondition, error) {
-	conditions, err := a.ListPluginsConditionsWithContext(ctx, policyID)
-
+	condition, err := findItem(a.ListPluginsConditionsIteratorWithContext(ctx, policyID), func(item interface{}) bool {
+		return item.(*PluginsCondition).ID == pluginID
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, condition := range conditions {
-		if condition.ID == pluginID {
-			return condition, nil
-		}
+	if condition == nil {
+		return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, pluginID)
 	}
 
-	return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, pluginID)
+	return condition.(*PluginsCondition), nil
 }
 
 // CreatePluginsCondition creates an alert condition for a plugin.
@@ -3,11 +3,13 @@ package alerts
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 
 	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/internal/serialization"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // IncidentPreferenceType specifies rollup settings for alert policies.
@@ -50,23 +52,31 @@ func (a *Alerts) ListPolicies(params *ListPoliciesParams) ([]Policy, error) {
 func (a *Alerts) ListPoliciesWithContext(ctx context.Context, params *ListPoliciesParams) ([]Policy, error) {
 	alertPolicies := []Policy{}
 
-	nextURL := a.config.Region().RestURL("/alerts_policies.json")
+	it := a.ListPoliciesIteratorWithContext(ctx, params)
+	for it.Next() {
+		alertPolicies = append(alertPolicies, it.Value().(Policy))
+	}
 
-	for nextURL != "" {
-		response := alertPoliciesResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, &params, &response)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	return alertPolicies, nil
+}
 
-		alertPolicies = append(alertPolicies, response.Policies...)
+// ListPoliciesIterator returns an iterator over the Alert Policies for a given account.
+func (a *Alerts) ListPoliciesIterator(params *ListPoliciesParams, opts ...paging.Option) *paging.Iterator {
+	return a.ListPoliciesIteratorWithContext(context.Background(), params, opts...)
+}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
-	}
+// ListPoliciesIteratorWithContext returns an iterator over the Alert Policies for a given account.
+func (a *Alerts) ListPoliciesIteratorWithContext(ctx context.Context, params *ListPoliciesParams, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("/alerts_policies.json"), &params,
+		func() interface{} { return &alertPoliciesResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*alertPoliciesResponse).Policies) },
+	)
 
-	return alertPolicies, nil
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // GetPolicy returns a specific alert policy by ID for a given account.
@@ -74,20 +84,65 @@ func (a *Alerts) GetPolicy(id int) (*Policy, error) {
 	return a.GetPolicyWithContext(context.Background(), id)
 }
 
-// GetPolicyWithContext returns a specific alert policy by ID for a given account.
+// GetPolicyWithContext returns a specific alert policy by ID for a given
+// account.  When the account ID and personal API key of the client are
+// configured, the name of the policy is first queried from NerdGraph so that
+// only the policies of that name are listed.  Otherwise, or when NerdGraph
+// fails or does not find the policy, such as when it belongs to another
+// account, the policies are listed until it is found.
 func (a *Alerts) GetPolicyWithContext(ctx context.Context, id int) (*Policy, error) {
-	policies, err := a.ListPoliciesWithContext(ctx, nil)
+	if a.canQueryNerdGraph() {
+		policy, err := a.getPolicyByNameWithContext(ctx, id)
+		if err == nil && policy != nil {
+			return policy, nil
+		}
+
+		if err != nil {
+			a.logger.Debug(fmt.Sprintf("querying policy %d of account %d, listing all policies: %s", id, a.config.AccountID, err))
+		} else {
+			a.logger.Debug(fmt.Sprintf("policy %d not found in account %d, listing all policies", id, a.config.AccountID))
+		}
+	}
+
+	policy, err := a.findPolicyWithContext(ctx, nil, id)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, policy := range policies {
-		if policy.ID == id {
-			return &policy, nil
-		}
+	if policy == nil {
+		return nil, errors.NewNotFoundf("no alert policy found for id %d", id)
+	}
+
+	return policy, nil
+}
+
+// getPolicyByNameWithContext looks up the name of a policy of the account of
+// the client with NerdGraph, then lists the policies of that name.
+func (a *Alerts) getPolicyByNameWithContext(ctx context.Context, id int) (*Policy, error) {
+	policy, err := a.QueryPolicyWithContext(ctx, a.config.AccountID, strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.Name == "" {
+		return nil, nil
+	}
+
+	return a.findPolicyWithContext(ctx, &ListPoliciesParams{Name: policy.Name}, id)
+}
+
+// findPolicyWithContext lists the policies matching the given filters until
+// the one of the given ID is found, returning nil if none.
+func (a *Alerts) findPolicyWithContext(ctx context.Context, params *ListPoliciesParams, id int) (*Policy, error) {
+	policy, err := findItem(a.ListPoliciesIteratorWithContext(ctx, params), func(item interface{}) bool {
+		return item.(Policy).ID == id
+	})
+	if err != nil || policy == nil {
+		return nil, err
 	}
 
-	return nil, errors.NewNotFoundf("no alert policy found for id %d", id)
+	p := policy.(Policy)
+	return &p, nil
 }
 
 // CreatePolicy creates a new alert policy for a given account.
@@ -376,11 +431,11 @@ const (
 		}
 	}`
 
-	alertsPolicyQuerySearch = `query($accountID: Int!, $cursor: String, $criteria: AlertsPoliciesSearchCriteriaInput) {
+	alertsPolicyQuerySearch = `query($accountID: Int!, $cursor: String, $searchCriteria: AlertsPoliciesSearchCriteriaInput) {
 		actor {
 			account(id: $accountID) {
 				alerts {
-					policiesSearch(cursor: $cursor, searchCriteria: $criteria) {
+					policiesSearch(cursor: $cursor, searchCriteria: $searchCriteria) {
 						nextCursor
 						totalCount
 						policies {
@@ -3,6 +3,7 @@
 package alerts
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
@@ -199,3 +200,31 @@ func TestDeletePolicy(t *testing.T) {
 	require.NotNil(t, actual)
 	require.Equal(t, expected, actual)
 }
+
+func TestQueryPolicySearch(t *testing.T) {
+	t.Parallel()
+
+	alerts := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// The search criteria are bound to the variable they are sent as.
+		require.Contains(t, req.Query, "$searchCriteria: AlertsPoliciesSearchCriteriaInput")
+		require.Contains(t, req.Query, "searchCriteria: $searchCriteria")
+		require.Equal(t, map[string]interface{}{"ids": []interface{}{"1"}}, req.Variables["searchCriteria"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data": {"actor": {"account": {"alerts": {"policiesSearch": {
+			"policies": [{"id": "1", "name": "policy"}]
+		}}}}}}`))
+		require.NoError(t, err)
+	}))
+
+	policies, err := alerts.QueryPolicySearch(1, AlertsPoliciesSearchCriteriaInput{IDs: []string{"1"}})
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	require.Equal(t, "policy", policies[0].Name)
+}
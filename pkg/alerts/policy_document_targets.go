@@ -0,0 +1,210 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/newrelic/newrelic-client-go/pkg/apm"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+)
+
+// PolicyDocumentTargetType specifies the type of the objects targeted by the
+// conditions of a policy document.
+type PolicyDocumentTargetType string
+
+var (
+	// PolicyDocumentTargetTypes enumerates the types of objects targeted by
+	// the conditions of a policy document which are resolved by name.
+	PolicyDocumentTargetTypes = struct {
+		Application    PolicyDocumentTargetType
+		KeyTransaction PolicyDocumentTargetType
+		Monitor        PolicyDocumentTargetType
+	}{
+		Application:    "application",
+		KeyTransaction: "key_transaction",
+		Monitor:        "monitor",
+	}
+)
+
+// PolicyDocumentTarget names an object targeted by ID by the conditions of a
+// policy document.
+type PolicyDocumentTarget struct {
+	Type PolicyDocumentTargetType `json:"type"`
+	ID   string                   `json:"id"`
+	Name string                   `json:"name"`
+}
+
+// conditionTargetTypes are the types of the entities of the alert conditions
+// resolved by name.
+var conditionTargetTypes = map[ConditionType]PolicyDocumentTargetType{
+	ConditionTypes.APMApplicationMetric:    PolicyDocumentTargetTypes.Application,
+	ConditionTypes.APMKeyTransactionMetric: PolicyDocumentTargetTypes.KeyTransaction,
+}
+
+// targetFunc is called with each ID of a field of the conditions of a policy
+// document targeting objects resolved by name, returning its replacement.
+type targetFunc func(field string, t PolicyDocumentTargetType, id string) string
+
+// eachTarget calls f with the IDs targeted by the conditions of the document
+// which are resolved by name, replacing them with its results.  The
+// conditions are copied, leaving the ones of other copies of the document
+// untouched.
+func (d *PolicyDocument) eachTarget(f targetFunc) {
+	if len(d.Conditions) > 0 {
+		conditions := make([]Condition, len(d.Conditions))
+		for i, c := range d.Conditions {
+			if t, ok := conditionTargetTypes[c.Type]; ok {
+				c.Entities = mapTargets(fmt.Sprintf("conditions[%d].entities", i), t, c.Entities, f)
+			}
+			conditions[i] = c
+		}
+		d.Conditions = conditions
+	}
+
+	if len(d.SyntheticsConditions) > 0 {
+		conditions := make([]SyntheticsCondition, len(d.SyntheticsConditions))
+		for i, c := range d.SyntheticsConditions {
+			if c.MonitorID != "" {
+				c.MonitorID = f(fmt.Sprintf("synthetics_conditions[%d].monitor_id", i), PolicyDocumentTargetTypes.Monitor, c.MonitorID)
+			}
+			conditions[i] = c
+		}
+		d.SyntheticsConditions = conditions
+	}
+
+	if len(d.MultiLocationSyntheticsConditions) > 0 {
+		conditions := make([]MultiLocationSyntheticsCondition, len(d.MultiLocationSyntheticsConditions))
+		for i, c := range d.MultiLocationSyntheticsConditions {
+			c.Entities = mapTargets(fmt.Sprintf("multi_location_synthetics_conditions[%d].entities", i), PolicyDocumentTargetTypes.Monitor, c.Entities, f)
+			conditions[i] = c
+		}
+		d.MultiLocationSyntheticsConditions = conditions
+	}
+}
+
+func mapTargets(field string, t PolicyDocumentTargetType, ids []string, f targetFunc) []string {
+	if ids == nil {
+		return nil
+	}
+
+	mapped := make([]string, len(ids))
+	for i, id := range ids {
+		mapped[i] = f(field, t, id)
+	}
+
+	return mapped
+}
+
+// nameTargets names the objects targeted by the conditions of the document.
+// IDs of objects which no longer exist are left unnamed.
+func (a *Alerts) nameTargets(ctx context.Context, doc *PolicyDocument) error {
+	ids := map[PolicyDocumentTargetType][]string{}
+	doc.eachTarget(func(_ string, t PolicyDocumentTargetType, id string) string {
+		ids[t] = append(ids[t], id)
+		return id
+	})
+
+	names := map[PolicyDocumentTargetType]map[string]string{}
+	for t := range ids {
+		names[t] = map[string]string{}
+
+		err := a.listTargets(ctx, t, func(id string, name string) {
+			names[t][id] = name
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	named := map[PolicyDocumentTarget]bool{}
+	doc.eachTarget(func(_ string, t PolicyDocumentTargetType, id string) string {
+		target := PolicyDocumentTarget{Type: t, ID: id, Name: names[t][id]}
+		if target.Name != "" && !named[target] {
+			named[target] = true
+			doc.Targets = append(doc.Targets, target)
+		}
+
+		return id
+	})
+
+	return nil
+}
+
+// resolveTargets replaces the IDs targeted by the conditions of the document
+// with the IDs of the objects of the same names in the account of the
+// client.
+func (a *Alerts) resolveTargets(ctx context.Context, doc *PolicyDocument) error {
+	names := map[PolicyDocumentTargetType]map[string]string{}
+	for _, target := range doc.Targets {
+		if names[target.Type] == nil {
+			names[target.Type] = map[string]string{}
+		}
+		names[target.Type][target.ID] = target.Name
+	}
+
+	ids := map[PolicyDocumentTargetType]map[string][]string{}
+	for t := range names {
+		ids[t] = map[string][]string{}
+
+		err := a.listTargets(ctx, t, func(id string, name string) {
+			ids[t][name] = append(ids[t][name], id)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var err error
+	doc.eachTarget(func(field string, t PolicyDocumentTargetType, id string) string {
+		name := names[t][id]
+
+		switch n := len(ids[t][name]); {
+		case err != nil:
+		case n == 0:
+			err = fmt.Errorf("%s: no %s named %q", field, t, name)
+		case n > 1:
+			err = fmt.Errorf("%s: %d objects of type %s are named %q", field, n, t, name)
+		default:
+			return ids[t][name][0]
+		}
+
+		return id
+	})
+
+	return err
+}
+
+// listTargets calls f with the ID and name of each object of a type targeted
+// by conditions.
+func (a *Alerts) listTargets(ctx context.Context, t PolicyDocumentTargetType, f func(id string, name string)) error {
+	var it *paging.Iterator
+
+	switch t {
+	case PolicyDocumentTargetTypes.Application:
+		client := apm.New(a.config)
+		it = client.ListApplicationsIteratorWithContext(ctx, nil)
+	case PolicyDocumentTargetTypes.KeyTransaction:
+		client := apm.New(a.config)
+		it = client.ListKeyTransactionsIteratorWithContext(ctx, nil)
+	case PolicyDocumentTargetTypes.Monitor:
+		client := synthetics.New(a.config)
+		it = client.ListMonitorsIteratorWithContext(ctx)
+	default:
+		return fmt.Errorf("unknown target type %q", t)
+	}
+
+	for it.Next() {
+		switch v := it.Value().(type) {
+		case *apm.Application:
+			f(strconv.Itoa(v.ID), v.Name)
+		case *apm.KeyTransaction:
+			f(strconv.Itoa(v.ID), v.Name)
+		case *synthetics.Monitor:
+			f(v.ID, v.Name)
+		}
+	}
+
+	return it.Err()
+}
@@ -0,0 +1,486 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// policyImportRollbackTimeout bounds the rollback of a failed policy import,
+// which runs on its own context as the one of the import may be done.
+const policyImportRollbackTimeout = 2 * time.Minute
+
+// PolicyDocument is a declarative snapshot of an alert policy with all its
+// conditions and the channels it notifies, used to re-create the policy in
+// another account.  Server-owned fields, such as IDs and timestamps, are left
+// out and channels are referred to by name.
+//
+// The APM applications, key transactions and Synthetics monitors targeted by
+// the conditions are kept as IDs and named in Targets, so that they are
+// resolved by name when importing the document into another account.  The
+// IDs of other targets, such as browser and mobile applications, servers and
+// plugins, are only valid in the account the policy was exported from, and
+// documents holding them can not be imported into another account.
+type PolicyDocument struct {
+	// AccountID is the account in which the IDs targeted by the conditions
+	// are valid.
+	AccountID          int                    `json:"account_id,omitempty"`
+	Name               string                 `json:"name"`
+	IncidentPreference IncidentPreferenceType `json:"incident_preference,omitempty"`
+	Channels           []string               `json:"channels,omitempty"`
+	// Targets names the objects whose IDs are targeted by the conditions.
+	Targets []PolicyDocumentTarget `json:"targets,omitempty"`
+
+	Conditions                        []Condition                        `json:"conditions,omitempty"`
+	NrqlConditions                    []NrqlConditionInput               `json:"nrql_conditions,omitempty"`
+	InfrastructureConditions          []InfrastructureCondition          `json:"infrastructure_conditions,omitempty"`
+	SyntheticsConditions              []SyntheticsCondition              `json:"synthetics_conditions,omitempty"`
+	MultiLocationSyntheticsConditions []MultiLocationSyntheticsCondition `json:"multi_location_synthetics_conditions,omitempty"`
+	PluginsConditions                 []PluginsCondition                 `json:"plugins_conditions,omitempty"`
+}
+
+// ParsePolicyDocument parses a policy document from JSON or YAML.
+func ParsePolicyDocument(data []byte) (*PolicyDocument, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse policy document: %s", err)
+	}
+
+	// Documents go through JSON, so that the JSON field names and
+	// serialization of the alerts types apply to YAML too.
+	j, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy document: %s", err)
+	}
+
+	doc := PolicyDocument{}
+	if err := json.Unmarshal(j, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse policy document: %s", err)
+	}
+
+	return &doc, nil
+}
+
+// YAML returns the policy document serialized as YAML.  It is serialized as
+// JSON with encoding/json.
+func (d *PolicyDocument) YAML() ([]byte, error) {
+	j, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.UseNumber()
+
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(yamlNumbers(raw)); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// yamlNumbers replaces the JSON numbers of a decoded document with integers
+// or floats, serialized as such by YAML.
+func yamlNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = yamlNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = yamlNumbers(e)
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := t.Float64()
+		return f
+	}
+
+	return v
+}
+
+// ExportPolicy returns a policy document holding an alert policy with all its
+// conditions and the channels it notifies.
+func (a *Alerts) ExportPolicy(accountID int, policyID int) (*PolicyDocument, error) {
+	return a.ExportPolicyWithContext(context.Background(), accountID, policyID)
+}
+
+// ExportPolicyWithContext returns a policy document holding an alert policy
+// with all its conditions and the channels it notifies.
+func (a *Alerts) ExportPolicyWithContext(ctx context.Context, accountID int, policyID int) (*PolicyDocument, error) {
+	policy, err := a.GetPolicyWithContext(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := PolicyDocument{
+		AccountID:          accountID,
+		Name:               policy.Name,
+		IncidentPreference: policy.IncidentPreference,
+	}
+
+	channels, err := a.ListChannelsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range channels {
+		for _, id := range c.Links.PolicyIDs {
+			if id == policyID {
+				doc.Channels = append(doc.Channels, c.Name)
+				break
+			}
+		}
+	}
+
+	conditions, err := a.ListConditionsWithContext(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range conditions {
+		c.ID = 0
+		doc.Conditions = append(doc.Conditions, *c)
+	}
+
+	nrqlConditions, err := a.SearchNrqlConditionsQueryWithContext(ctx, accountID, NrqlConditionsSearchCriteria{
+		PolicyID: strconv.Itoa(policyID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range nrqlConditions {
+		doc.NrqlConditions = append(doc.NrqlConditions, c.input())
+	}
+
+	infrastructureConditions, err := a.ListInfrastructureConditionsWithContext(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range infrastructureConditions {
+		doc.InfrastructureConditions = append(doc.InfrastructureConditions, c.withoutServerFields())
+	}
+
+	syntheticsConditions, err := a.ListSyntheticsConditionsWithContext(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range syntheticsConditions {
+		c.ID = 0
+		doc.SyntheticsConditions = append(doc.SyntheticsConditions, *c)
+	}
+
+	multiLocationSyntheticsConditions, err := a.ListMultiLocationSyntheticsConditionsWithContext(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range multiLocationSyntheticsConditions {
+		c.ID = 0
+		doc.MultiLocationSyntheticsConditions = append(doc.MultiLocationSyntheticsConditions, *c)
+	}
+
+	pluginsConditions, err := a.ListPluginsConditionsWithContext(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range pluginsConditions {
+		c.ID = 0
+		doc.PluginsConditions = append(doc.PluginsConditions, *c)
+	}
+
+	if err := a.nameTargets(ctx, &doc); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// input returns the input re-creating the NRQL condition.
+func (c *NrqlAlertCondition) input() NrqlConditionInput {
+	return NrqlConditionInput{
+		NrqlConditionBase:           c.NrqlConditionBase,
+		BaselineDirection:           c.BaselineDirection,
+		ValueFunction:               c.ValueFunction,
+		ExpectedGroups:              c.ExpectedGroups,
+		OpenViolationOnGroupOverlap: c.OpenViolationOnGroupOverlap,
+	}
+}
+
+// ImportPolicy creates the alert policy of a policy document in an account,
+// along with its conditions, and links it to the channels named.
+func (a *Alerts) ImportPolicy(accountID int, doc PolicyDocument) (*Policy, error) {
+	return a.ImportPolicyWithContext(context.Background(), accountID, doc)
+}
+
+// ImportPolicyWithContext creates the alert policy of a policy document in an
+// account, along with its conditions, and links it to the channels named.
+// The channels must exist in the account.  When the document belongs to
+// another account, the IDs targeted by the conditions are replaced with the
+// ones of the objects named the same in the account, and conditions
+// targeting objects by IDs not named are rejected.  Objects are created in
+// dependency order, the policy first, and deleted in reverse order should
+// any of them fail to be created, even when ctx is done.
+func (a *Alerts) ImportPolicyWithContext(ctx context.Context, accountID int, doc PolicyDocument) (*Policy, error) {
+	if doc.AccountID != accountID {
+		if err := doc.validateTargets(accountID); err != nil {
+			return nil, err
+		}
+
+		if err := a.resolveTargets(ctx, &doc); err != nil {
+			return nil, fmt.Errorf("failed to import policy %q: %w", doc.Name, err)
+		}
+	}
+
+	channelIDs, err := a.policyDocumentChannelIDs(ctx, doc.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := policyImport{alerts: a, accountID: accountID}
+
+	policy, err := imp.run(ctx, doc, channelIDs)
+	if err != nil {
+		rollbackCtx, cancel := context.WithTimeout(context.Background(), policyImportRollbackTimeout)
+		defer cancel()
+
+		if rollbackErr := imp.rollback(rollbackCtx); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to import policy %q: %w, and to roll back: %s", doc.Name, err, rollbackErr)
+		}
+
+		return nil, fmt.Errorf("failed to import policy %q, rolled back: %w", doc.Name, err)
+	}
+
+	return policy, nil
+}
+
+// validateTargets rejects the fields of the conditions targeting objects by
+// IDs which differ between accounts and are not named in the targets of the
+// document.
+func (d *PolicyDocument) validateTargets(accountID int) error {
+	v := validator{}
+	foreign := func(field string, set bool) {
+		if set {
+			v.addf(field, "holds IDs from another account, replace them and set account_id to %d", accountID)
+		}
+	}
+
+	named := map[PolicyDocumentTargetType]map[string]bool{}
+	for _, target := range d.Targets {
+		if named[target.Type] == nil {
+			named[target.Type] = map[string]bool{}
+		}
+		named[target.Type][target.ID] = target.Name != ""
+	}
+
+	unnamed := map[string]bool{}
+	d.eachTarget(func(field string, t PolicyDocumentTargetType, id string) string {
+		if !named[t][id] && !unnamed[field] {
+			unnamed[field] = true
+			v.addf(field, "holds IDs from another account not named in targets, name them or replace them and set account_id to %d", accountID)
+		}
+
+		return id
+	})
+
+	for i, c := range d.Conditions {
+		_, resolved := conditionTargetTypes[c.Type]
+		foreign(fmt.Sprintf("conditions[%d].entities", i), !resolved && len(c.Entities) > 0)
+	}
+
+	for i, c := range d.PluginsConditions {
+		foreign(fmt.Sprintf("plugins_conditions[%d].entities", i), len(c.Entities) > 0)
+		foreign(fmt.Sprintf("plugins_conditions[%d].plugin.id", i), c.Plugin.ID != "")
+	}
+
+	return v.err("policy document", d.Name)
+}
+
+// policyDocumentChannelIDs resolves the names of the channels of a policy
+// document to the IDs of the channels of the account.
+func (a *Alerts) policyDocumentChannelIDs(ctx context.Context, names []string) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	channels, err := a.ListChannelsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string][]int{}
+	for _, c := range channels {
+		ids[c.Name] = append(ids[c.Name], c.ID)
+	}
+
+	channelIDs := []int{}
+	for _, name := range names {
+		switch len(ids[name]) {
+		case 0:
+			return nil, fmt.Errorf("no alert channel named %q", name)
+		case 1:
+			channelIDs = append(channelIDs, ids[name][0])
+		default:
+			return nil, fmt.Errorf("%d alert channels named %q", len(ids[name]), name)
+		}
+	}
+
+	return channelIDs, nil
+}
+
+// policyImport creates the objects of a policy document, keeping track of
+// how to delete them.
+type policyImport struct {
+	alerts    *Alerts
+	accountID int
+	undo      []func(context.Context) error
+}
+
+func (p *policyImport) run(ctx context.Context, doc PolicyDocument, channelIDs []int) (*Policy, error) {
+	a := p.alerts
+
+	policy, err := a.CreatePolicyWithContext(ctx, Policy{
+		Name:               doc.Name,
+		IncidentPreference: doc.IncidentPreference,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.onRollback(func(ctx context.Context) error {
+		_, err := a.DeletePolicyWithContext(ctx, policy.ID)
+		return err
+	})
+
+	for _, c := range doc.Conditions {
+		created, err := a.CreateConditionWithContext(ctx, policy.ID, c)
+		if err != nil {
+			return nil, fmt.Errorf("condition %q: %w", c.Name, err)
+		}
+
+		p.onRollback(func(ctx context.Context) error {
+			_, err := a.DeleteConditionWithContext(ctx, created.ID)
+			return err
+		})
+	}
+
+	for _, c := range doc.NrqlConditions {
+		created, err := a.CreateNrqlConditionMutationWithContext(ctx, p.accountID, strconv.Itoa(policy.ID), c)
+		if err != nil {
+			return nil, fmt.Errorf("NRQL condition %q: %w", c.Name, err)
+		}
+
+		p.onRollback(func(ctx context.Context) error {
+			_, err := a.DeleteNrqlConditionMutationWithContext(ctx, p.accountID, created.ID)
+			return err
+		})
+	}
+
+	for _, c := range doc.InfrastructureConditions {
+		c.PolicyID = policy.ID
+
+		created, err := a.CreateInfrastructureConditionWithContext(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("infrastructure condition %q: %w", c.Name, err)
+		}
+
+		p.onRollback(func(ctx context.Context) error {
+			return a.DeleteInfrastructureConditionWithContext(ctx, created.ID)
+		})
+	}
+
+	for _, c := range doc.SyntheticsConditions {
+		created, err := a.CreateSyntheticsConditionWithContext(ctx, policy.ID, c)
+		if err != nil {
+			return nil, fmt.Errorf("synthetics condition %q: %w", c.Name, err)
+		}
+
+		p.onRollback(func(ctx context.Context) error {
+			_, err := a.DeleteSyntheticsConditionWithContext(ctx, created.ID)
+			return err
+		})
+	}
+
+	for _, c := range doc.MultiLocationSyntheticsConditions {
+		created, err := a.CreateMultiLocationSyntheticsConditionWithContext(ctx, c, policy.ID)
+		if err != nil {
+			return nil, fmt.Errorf("multi-location synthetics condition %q: %w", c.Name, err)
+		}
+
+		p.onRollback(func(ctx context.Context) error {
+			_, err := a.DeleteMultiLocationSyntheticsConditionWithContext(ctx, created.ID)
+			return err
+		})
+	}
+
+	for _, c := range doc.PluginsConditions {
+		created, err := a.CreatePluginsConditionWithContext(ctx, policy.ID, c)
+		if err != nil {
+			return nil, fmt.Errorf("plugins condition %q: %w", c.Name, err)
+		}
+
+		p.onRollback(func(ctx context.Context) error {
+			_, err := a.DeletePluginsConditionWithContext(ctx, created.ID)
+			return err
+		})
+	}
+
+	if len(channelIDs) > 0 {
+		if _, err := a.UpdatePolicyChannelsWithContext(ctx, policy.ID, channelIDs); err != nil {
+			return nil, fmt.Errorf("channels: %w", err)
+		}
+	}
+
+	return policy, nil
+}
+
+func (p *policyImport) onRollback(fn func(context.Context) error) {
+	p.undo = append(p.undo, fn)
+}
+
+// rollback deletes the objects created, in reverse order.  The deletion
+// carries on past failures, which are all reported.
+func (p *policyImport) rollback(ctx context.Context) error {
+	var failures []string
+
+	for i := len(p.undo) - 1; i >= 0; i-- {
+		if err := p.undo[i](ctx); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	p.undo = nil
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,358 @@
+// +build unit
+
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/apm"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/newrelic/newrelic-client-go/pkg/testhelpers/fakeserver"
+)
+
+// newPolicyDocumentTestServer returns a fake server also storing NRQL
+// conditions through NerdGraph, optionally failing to create them.
+func newPolicyDocumentTestServer(t *testing.T, failNrql bool) (*fakeserver.Server, Alerts, *[]map[string]interface{}) {
+	server := fakeserver.New(t)
+	nrqlConditions := &[]map[string]interface{}{}
+
+	server.HandleNerdGraph("nrqlConditionsSearch", func(vars map[string]interface{}) (interface{}, error) {
+		criteria := vars["searchCriteria"].(map[string]interface{})
+
+		found := []interface{}{}
+		for _, c := range *nrqlConditions {
+			if c["policyId"] == criteria["policyId"] {
+				found = append(found, c)
+			}
+		}
+
+		return map[string]interface{}{"actor": map[string]interface{}{"account": map[string]interface{}{"alerts": map[string]interface{}{
+			"nrqlConditionsSearch": map[string]interface{}{"nrqlConditions": found},
+		}}}}, nil
+	})
+
+	server.HandleNerdGraph("alertsNrqlConditionStaticCreate", func(vars map[string]interface{}) (interface{}, error) {
+		if failNrql {
+			return nil, errors.New("NRQL condition rejected")
+		}
+
+		c := vars["condition"].(map[string]interface{})
+		c["id"] = strconv.Itoa(len(*nrqlConditions) + 1000)
+		c["policyId"] = vars["policyId"]
+		*nrqlConditions = append(*nrqlConditions, c)
+
+		return map[string]interface{}{"alertsNrqlConditionStaticCreate": c}, nil
+	})
+
+	server.HandleNerdGraph("alertsConditionDelete", func(vars map[string]interface{}) (interface{}, error) {
+		remaining := []map[string]interface{}{}
+		for _, c := range *nrqlConditions {
+			if c["id"] != vars["id"] {
+				remaining = append(remaining, c)
+			}
+		}
+		*nrqlConditions = remaining
+
+		return map[string]interface{}{"alertsConditionDelete": map[string]interface{}{"id": vars["id"]}}, nil
+	})
+
+	return server, New(server.Config()), nrqlConditions
+}
+
+func seedPolicyDocumentPolicy(t *testing.T, client Alerts) *Policy {
+	policy, err := client.CreatePolicy(Policy{Name: "source", IncidentPreference: IncidentPreferenceTypes.PerCondition})
+	require.NoError(t, err)
+
+	channel, err := client.CreateChannel(Channel{Name: "on-call", Type: ChannelTypes.Email, Configuration: ChannelConfiguration{Recipients: "oncall@example.com"}})
+	require.NoError(t, err)
+
+	_, err = client.UpdatePolicyChannels(policy.ID, []int{channel.ID})
+	require.NoError(t, err)
+
+	_, err = client.CreateCondition(policy.ID, Condition{
+		Type:     ConditionTypes.APMApplicationMetric,
+		Name:     "apdex",
+		Enabled:  true,
+		Entities: []string{"123"},
+		Metric:   MetricTypes.Apdex,
+		Terms:    []ConditionTerm{{Duration: 5, Operator: OperatorTypes.Below, Priority: PriorityTypes.Critical, Threshold: 0.7, TimeFunction: TimeFunctionTypes.All}},
+	})
+	require.NoError(t, err)
+
+	threshold := 90.0
+	_, err = client.CreateInfrastructureCondition(InfrastructureCondition{
+		PolicyID: policy.ID,
+		Name:     "cpu",
+		Type:     "infra_metric",
+		Enabled:  true,
+		Event:      "SystemSample",
+		Select:     "cpuPercent",
+		Comparison: "above",
+		Critical:   &InfrastructureConditionThreshold{Duration: 5, Function: "all", Value: &threshold},
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateSyntheticsCondition(policy.ID, SyntheticsCondition{Name: "monitor", Enabled: true, MonitorID: "abc"})
+	require.NoError(t, err)
+
+	_, err = client.CreateNrqlConditionStaticMutation(1, strconv.Itoa(policy.ID), NrqlConditionInput{
+		NrqlConditionBase: NrqlConditionBase{
+			Name:    "errors",
+			Enabled: true,
+			Type:    NrqlConditionTypes.Static,
+			Nrql:    NrqlConditionQuery{Query: "SELECT count(*) FROM TransactionError"},
+			Terms:   []NrqlConditionTerm{{Operator: AlertsNRQLConditionTermsOperatorTypes.ABOVE, Threshold: &threshold, ThresholdDuration: 300}},
+		},
+	})
+	require.NoError(t, err)
+
+	return policy
+}
+
+func TestExportImportPolicy(t *testing.T) {
+	t.Parallel()
+	server, client, nrqlConditions := newPolicyDocumentTestServer(t, false)
+	policy := seedPolicyDocumentPolicy(t, client)
+
+	doc, err := client.ExportPolicy(1, policy.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "source", doc.Name)
+	assert.Equal(t, IncidentPreferenceTypes.PerCondition, doc.IncidentPreference)
+	assert.Equal(t, []string{"on-call"}, doc.Channels)
+	require.Len(t, doc.Conditions, 1)
+	assert.Zero(t, doc.Conditions[0].ID)
+	require.Len(t, doc.InfrastructureConditions, 1)
+	assert.Zero(t, doc.InfrastructureConditions[0].ID)
+	assert.Zero(t, doc.InfrastructureConditions[0].PolicyID)
+	assert.Nil(t, doc.InfrastructureConditions[0].CreatedAt)
+	require.Len(t, doc.SyntheticsConditions, 1)
+	require.Len(t, doc.NrqlConditions, 1)
+	assert.Equal(t, "errors", doc.NrqlConditions[0].Name)
+
+	y, err := doc.YAML()
+	require.NoError(t, err)
+	assert.Contains(t, string(y), "name: source")
+	assert.NotContains(t, string(y), "policy_id")
+
+	parsed, err := ParsePolicyDocument(y)
+	require.NoError(t, err)
+	assert.Equal(t, doc, parsed)
+
+	parsed.Name = "copy"
+	imported, err := client.ImportPolicy(1, *parsed)
+	require.NoError(t, err)
+	assert.Equal(t, "copy", imported.Name)
+	assert.NotEqual(t, policy.ID, imported.ID)
+
+	copied, err := client.ExportPolicy(1, imported.ID)
+	require.NoError(t, err)
+	assert.Equal(t, parsed, copied)
+
+	assert.Len(t, server.Objects(fakeserver.Resources.AlertPolicies), 2)
+	assert.Len(t, *nrqlConditions, 2)
+}
+
+func TestImportPolicy_otherAccount(t *testing.T) {
+	t.Parallel()
+	server, client, _ := newPolicyDocumentTestServer(t, false)
+	policy := seedPolicyDocumentPolicy(t, client)
+
+	doc, err := client.ExportPolicy(1, policy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, doc.AccountID)
+
+	assert.Empty(t, doc.Targets)
+
+	// The application and monitor targeted do not exist, so are not named.
+	_, err = client.ImportPolicy(2, *doc)
+	assert.EqualError(t, err, `invalid policy document "source": `+
+		`conditions[0].entities: holds IDs from another account not named in targets, name them or replace them and set account_id to 2; `+
+		`synthetics_conditions[0].monitor_id: holds IDs from another account not named in targets, name them or replace them and set account_id to 2`)
+	assert.Len(t, server.Objects(fakeserver.Resources.AlertPolicies), 1)
+
+	browser := *doc
+	browser.Conditions = []Condition{{Type: ConditionTypes.BrowserMetric, Name: "load", Entities: []string{"456"}}}
+	browser.SyntheticsConditions = nil
+	_, err = client.ImportPolicy(2, browser)
+	assert.EqualError(t, err, `invalid policy document "source": `+
+		`conditions[0].entities: holds IDs from another account, replace them and set account_id to 2`)
+
+	doc.AccountID = 2
+	_, err = client.ImportPolicy(2, *doc)
+	require.NoError(t, err)
+	assert.Len(t, server.Objects(fakeserver.Resources.AlertPolicies), 2)
+}
+
+func TestImportPolicy_resolvesTargets(t *testing.T) {
+	t.Parallel()
+	server, client, _ := newPolicyDocumentTestServer(t, false)
+
+	policy, err := client.CreatePolicy(Policy{Name: "source", IncidentPreference: IncidentPreferenceTypes.PerPolicy})
+	require.NoError(t, err)
+
+	syntheticsClient := synthetics.New(server.Config())
+	monitor, err := syntheticsClient.CreateMonitor(synthetics.Monitor{Name: "home page", Type: synthetics.MonitorTypes.Ping})
+	require.NoError(t, err)
+
+	appID := server.Seed(fakeserver.Resources.Applications, map[string]interface{}{"name": "checkout"})
+	monitorID := monitor.ID
+
+	_, err = client.CreateCondition(policy.ID, Condition{
+		Type:     ConditionTypes.APMApplicationMetric,
+		Name:     "apdex",
+		Enabled:  true,
+		Entities: []string{appID},
+		Metric:   MetricTypes.Apdex,
+		Terms:    []ConditionTerm{{Duration: 5, Operator: OperatorTypes.Below, Priority: PriorityTypes.Critical, Threshold: 0.7, TimeFunction: TimeFunctionTypes.All}},
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateSyntheticsCondition(policy.ID, SyntheticsCondition{Name: "monitor", Enabled: true, MonitorID: monitorID})
+	require.NoError(t, err)
+
+	doc, err := client.ExportPolicy(1, policy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyDocumentTarget{
+		{Type: PolicyDocumentTargetTypes.Application, ID: appID, Name: "checkout"},
+		{Type: PolicyDocumentTargetTypes.Monitor, ID: monitorID, Name: "home page"},
+	}, doc.Targets)
+
+	// Objects of the same names in the other account are targeted instead.
+	apmClient := apm.New(server.Config())
+	id, _ := strconv.Atoi(appID)
+	_, err = apmClient.DeleteApplication(id)
+	require.NoError(t, err)
+
+	require.NoError(t, syntheticsClient.DeleteMonitor(monitorID))
+
+	otherAppID := server.Seed(fakeserver.Resources.Applications, map[string]interface{}{"name": "checkout"})
+	otherMonitor, err := syntheticsClient.CreateMonitor(synthetics.Monitor{Name: "home page", Type: synthetics.MonitorTypes.Ping})
+	require.NoError(t, err)
+
+	doc.Name = "copy"
+	imported, err := client.ImportPolicy(2, *doc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{appID}, doc.Conditions[0].Entities)
+
+	conditions, err := client.ListConditions(imported.ID)
+	require.NoError(t, err)
+	require.Len(t, conditions, 1)
+	assert.Equal(t, []string{otherAppID}, conditions[0].Entities)
+
+	syntheticsConditions, err := client.ListSyntheticsConditions(imported.ID)
+	require.NoError(t, err)
+	require.Len(t, syntheticsConditions, 1)
+	assert.Equal(t, otherMonitor.ID, syntheticsConditions[0].MonitorID)
+
+	// Names matching several objects are ambiguous.
+	server.Seed(fakeserver.Resources.Applications, map[string]interface{}{"name": "checkout"})
+	_, err = client.ImportPolicy(2, *doc)
+	assert.EqualError(t, err, `failed to import policy "copy": conditions[0].entities: 2 objects of type application are named "checkout"`)
+}
+
+func TestParsePolicyDocument_json(t *testing.T) {
+	t.Parallel()
+
+	doc := PolicyDocument{
+		Name:     "policy",
+		Channels: []string{"channel"},
+		Conditions: []Condition{{
+			Name:  "condition",
+			Terms: []ConditionTerm{{Duration: 5, Threshold: 1.5}},
+		}},
+	}
+
+	j, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	parsed, err := ParsePolicyDocument(j)
+	require.NoError(t, err)
+	assert.Equal(t, &doc, parsed)
+
+	_, err = ParsePolicyDocument([]byte("name: [unterminated"))
+	assert.Error(t, err)
+}
+
+func TestImportPolicy_rollback(t *testing.T) {
+	t.Parallel()
+	server, client, _ := newPolicyDocumentTestServer(t, true)
+	threshold := 10.0
+
+	_, err := client.CreateChannel(Channel{Name: "on-call", Type: ChannelTypes.Email, Configuration: ChannelConfiguration{Recipients: "oncall@example.com"}})
+	require.NoError(t, err)
+
+	doc := PolicyDocument{
+		Name:       "policy",
+		Channels:   []string{"on-call"},
+		Conditions: []Condition{{
+			Type:   ConditionTypes.APMApplicationMetric,
+			Name:   "apdex",
+			Metric: MetricTypes.Apdex,
+			Terms:  []ConditionTerm{{Duration: 5, Operator: OperatorTypes.Below, Priority: PriorityTypes.Critical, Threshold: 0.7, TimeFunction: TimeFunctionTypes.All}},
+		}},
+		NrqlConditions: []NrqlConditionInput{{
+			NrqlConditionBase: NrqlConditionBase{
+				Name:  "errors",
+				Type:  NrqlConditionTypes.Static,
+				Nrql:  NrqlConditionQuery{Query: "SELECT count(*) FROM TransactionError"},
+				Terms: []NrqlConditionTerm{{Operator: AlertsNRQLConditionTermsOperatorTypes.ABOVE, Threshold: &threshold, ThresholdDuration: 300}},
+			},
+		}},
+	}
+
+	_, err = client.ImportPolicy(1, doc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rolled back")
+	assert.Contains(t, err.Error(), "NRQL condition rejected")
+
+	assert.Empty(t, server.Objects(fakeserver.Resources.AlertPolicies))
+	assert.Empty(t, server.Objects(fakeserver.Resources.AlertConditions))
+}
+
+func TestImportPolicy_rollbackCanceled(t *testing.T) {
+	t.Parallel()
+	server, client, _ := newPolicyDocumentTestServer(t, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server.HandleNerdGraph("alertsNrqlConditionStaticCreate", func(vars map[string]interface{}) (interface{}, error) {
+		cancel()
+		return nil, errors.New("NRQL condition rejected")
+	})
+
+	threshold := 10.0
+	_, err := client.ImportPolicyWithContext(ctx, 1, PolicyDocument{
+		Name: "policy",
+		NrqlConditions: []NrqlConditionInput{{
+			NrqlConditionBase: NrqlConditionBase{
+				Name:  "errors",
+				Type:  NrqlConditionTypes.Static,
+				Nrql:  NrqlConditionQuery{Query: "SELECT count(*) FROM TransactionError"},
+				Terms: []NrqlConditionTerm{{Operator: AlertsNRQLConditionTermsOperatorTypes.ABOVE, Threshold: &threshold, ThresholdDuration: 300}},
+			},
+		}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rolled back")
+
+	// The policy is deleted although the context of the import is done.
+	assert.Empty(t, server.Objects(fakeserver.Resources.AlertPolicies))
+}
+
+func TestImportPolicy_unknownChannel(t *testing.T) {
+	t.Parallel()
+	server, client, _ := newPolicyDocumentTestServer(t, false)
+
+	_, err := client.ImportPolicy(1, PolicyDocument{Name: "policy", Channels: []string{"missing"}})
+	assert.EqualError(t, err, `no alert channel named "missing"`)
+	assert.Empty(t, server.Objects(fakeserver.Resources.AlertPolicies))
+}
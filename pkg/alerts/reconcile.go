@@ -0,0 +1,645 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PolicyState is the desired state of the NRQL and infrastructure conditions
+// of an alert policy and of the channels it notifies, identified by name.
+type PolicyState struct {
+	NrqlConditions           []NrqlConditionInput
+	InfrastructureConditions []InfrastructureCondition
+	Channels                 []Channel
+}
+
+// ChangeAction specifies the action taken by a change of a policy plan.
+type ChangeAction string
+
+var (
+	// ChangeActions enumerates the actions taken by the changes of a policy plan.
+	ChangeActions = struct {
+		Create ChangeAction
+		Update ChangeAction
+		Delete ChangeAction
+	}{
+		Create: "create",
+		Update: "update",
+		Delete: "delete",
+	}
+)
+
+// ChangeKind specifies the kind of object changed by a change of a policy plan.
+type ChangeKind string
+
+var (
+	// ChangeKinds enumerates the kinds of objects changed by a policy plan.
+	ChangeKinds = struct {
+		NrqlCondition           ChangeKind
+		InfrastructureCondition ChangeKind
+		Channel                 ChangeKind
+	}{
+		NrqlCondition:           "nrql_condition",
+		InfrastructureCondition: "infrastructure_condition",
+		Channel:                 "channel",
+	}
+)
+
+// FieldDiff is the difference of a field between the current and the desired
+// state of an object, the field being given as a path such as
+// "terms[0].threshold".  Current is nil for fields not set.
+type FieldDiff struct {
+	Path    string
+	Current interface{}
+	Desired interface{}
+}
+
+// Change is a change to an object of a policy plan.  Channels are shared by
+// the policies of the account, and are only created, linked to the policy or
+// unlinked from it.
+type Change struct {
+	Action ChangeAction
+	Kind   ChangeKind
+	Name   string
+	// The ID of the current object, empty for creations.
+	ID     string
+	Fields []FieldDiff
+
+	nrqlCondition           *NrqlConditionInput
+	infrastructureCondition *InfrastructureCondition
+	channel                 *Channel
+}
+
+// PolicyPlan holds the changes bringing an alert policy to its desired state.
+type PolicyPlan struct {
+	AccountID int
+	PolicyID  int
+	Changes   []Change
+	// Drift holds the channels whose settings differ from the desired ones.
+	// It is reported only, as channels can not be updated and replacing them
+	// would affect the other policies they notify.
+	Drift []Change
+}
+
+// Empty returns whether the policy is in its desired state, leaving aside
+// the drift of its channels.
+func (p *PolicyPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns a human-readable summary of the changes and the drift of
+// the plan.
+func (p *PolicyPlan) String() string {
+	if p.Empty() && len(p.Drift) == 0 {
+		return "no changes"
+	}
+
+	var b strings.Builder
+	writeChanges(&b, p.Changes, "")
+	writeChanges(&b, p.Drift, "drift of ")
+
+	return b.String()
+}
+
+func writeChanges(b *strings.Builder, changes []Change, prefix string) {
+	for _, c := range changes {
+		fmt.Fprintf(b, "%s%s %s %q", prefix, c.Action, c.Kind, c.Name)
+		if c.ID != "" {
+			fmt.Fprintf(b, " (%s)", c.ID)
+		}
+		b.WriteString("\n")
+
+		for _, f := range c.Fields {
+			fmt.Fprintf(b, "  %s: %s => %s\n", f.Path, diffValueString(f.Current), diffValueString(f.Desired))
+		}
+	}
+}
+
+// PlanPolicy computes the changes bringing the NRQL and infrastructure
+// conditions of an alert policy and the channels it notifies to the desired
+// state, without performing any of them.
+func (a *Alerts) PlanPolicy(accountID int, policyID int, desired PolicyState) (*PolicyPlan, error) {
+	return a.PlanPolicyWithContext(context.Background(), accountID, policyID, desired)
+}
+
+// PlanPolicyWithContext computes the changes bringing the NRQL and
+// infrastructure conditions of an alert policy and the channels it notifies
+// to the desired state, without performing any of them.
+//
+// Objects are matched by name, and planning fails when several current
+// objects have the name of a desired one.  Only the fields set in the desired state are
+// compared, so that the defaults filled in by New Relic are not reported as
+// changes, and lists are compared as a whole when their length differs.
+// Conditions of the policy which are not desired are deleted, and channels
+// which are not desired unlinked from the policy.  Channels are never
+// deleted nor replaced, their differing settings being reported as drift,
+// and their secrets, which New Relic does not return, are not compared.
+func (a *Alerts) PlanPolicyWithContext(ctx context.Context, accountID int, policyID int, desired PolicyState) (*PolicyPlan, error) {
+	if err := desired.validateNames(); err != nil {
+		return nil, err
+	}
+
+	plan := PolicyPlan{
+		AccountID: accountID,
+		PolicyID:  policyID,
+	}
+
+	if err := a.planChannels(ctx, &plan, desired.Channels); err != nil {
+		return nil, err
+	}
+
+	if err := a.planNrqlConditions(ctx, &plan, desired.NrqlConditions); err != nil {
+		return nil, err
+	}
+
+	if err := a.planInfrastructureConditions(ctx, &plan, desired.InfrastructureConditions); err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// ApplyPolicyPlan performs the changes of a plan, stopping at the first one
+// failing.  Planning again from the state reached then gives the changes
+// left.
+func (a *Alerts) ApplyPolicyPlan(plan *PolicyPlan) error {
+	return a.ApplyPolicyPlanWithContext(context.Background(), plan)
+}
+
+// ApplyPolicyPlanWithContext performs the changes of a plan, stopping at the
+// first one failing.  Planning again from the state reached then gives the
+// changes left.
+func (a *Alerts) ApplyPolicyPlanWithContext(ctx context.Context, plan *PolicyPlan) error {
+	for _, c := range plan.Changes {
+		if err := a.applyChange(ctx, plan, c); err != nil {
+			return fmt.Errorf("failed to %s %s %q: %w", c.Action, c.Kind, c.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ReconcilePolicy brings the NRQL and infrastructure conditions of an alert
+// policy and the channels it notifies to the desired state, returning the
+// changes made.  In dry-run mode, the changes are planned only.
+func (a *Alerts) ReconcilePolicy(accountID int, policyID int, desired PolicyState, dryRun bool) (*PolicyPlan, error) {
+	return a.ReconcilePolicyWithContext(context.Background(), accountID, policyID, desired, dryRun)
+}
+
+// ReconcilePolicyWithContext brings the NRQL and infrastructure conditions of
+// an alert policy and the channels it notifies to the desired state,
+// returning the changes made.  In dry-run mode, the changes are planned only.
+// As the changes are planned from the current state, reconciling again once
+// in the desired state makes no changes.
+func (a *Alerts) ReconcilePolicyWithContext(ctx context.Context, accountID int, policyID int, desired PolicyState, dryRun bool) (*PolicyPlan, error) {
+	plan, err := a.PlanPolicyWithContext(ctx, accountID, policyID, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return plan, nil
+	}
+
+	if err := a.ApplyPolicyPlanWithContext(ctx, plan); err != nil {
+		return plan, err
+	}
+
+	return plan, nil
+}
+
+func (s *PolicyState) validateNames() error {
+	for kind, names := range map[ChangeKind][]string{
+		ChangeKinds.NrqlCondition:           nrqlConditionInputNames(s.NrqlConditions),
+		ChangeKinds.InfrastructureCondition: infrastructureConditionNames(s.InfrastructureConditions),
+		ChangeKinds.Channel:                 channelNames(s.Channels),
+	} {
+		seen := map[string]bool{}
+		for _, name := range names {
+			if name == "" {
+				return fmt.Errorf("desired %s has no name", kind)
+			}
+
+			if seen[name] {
+				return fmt.Errorf("desired %s %q is defined more than once", kind, name)
+			}
+
+			seen[name] = true
+		}
+	}
+
+	return nil
+}
+
+func (a *Alerts) planChannels(ctx context.Context, plan *PolicyPlan, desired []Channel) error {
+	channels, err := a.ListChannelsWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := map[string][]*Channel{}
+	for _, c := range channels {
+		current[c.Name] = append(current[c.Name], c)
+	}
+
+	wanted := map[string]bool{}
+	for i := range desired {
+		d := desired[i]
+		d.ID = 0
+		d.Links = ChannelLinks{}
+		wanted[d.Name] = true
+
+		if len(current[d.Name]) > 1 {
+			return fmt.Errorf("%s %q is ambiguous, %d channels have this name", ChangeKinds.Channel, d.Name, len(current[d.Name]))
+		}
+
+		if len(current[d.Name]) == 0 {
+			if err := plan.add(Change{Action: ChangeActions.Create, Kind: ChangeKinds.Channel, Name: d.Name, channel: &d}, nil, d); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		c := current[d.Name][0]
+		id := strconv.Itoa(c.ID)
+
+		fields, err := diffObjects(c.withoutSecrets(), d.withoutSecrets())
+		if err != nil {
+			return err
+		}
+
+		if len(fields) > 0 {
+			plan.Drift = append(plan.Drift, Change{Action: ChangeActions.Update, Kind: ChangeKinds.Channel, Name: d.Name, ID: id, Fields: fields})
+		}
+
+		if !containsInt(c.Links.PolicyIDs, plan.PolicyID) {
+			plan.Changes = append(plan.Changes, Change{
+				Action: ChangeActions.Update,
+				Kind:   ChangeKinds.Channel,
+				Name:   d.Name,
+				ID:     id,
+				Fields: []FieldDiff{{Path: "links.policy_ids", Current: nil, Desired: plan.PolicyID}},
+			})
+		}
+	}
+
+	for _, c := range channels {
+		if containsInt(c.Links.PolicyIDs, plan.PolicyID) && !wanted[c.Name] {
+			plan.Changes = append(plan.Changes, Change{Action: ChangeActions.Delete, Kind: ChangeKinds.Channel, Name: c.Name, ID: strconv.Itoa(c.ID)})
+		}
+	}
+
+	return nil
+}
+
+func (a *Alerts) planNrqlConditions(ctx context.Context, plan *PolicyPlan, desired []NrqlConditionInput) error {
+	conditions, err := a.SearchNrqlConditionsQueryWithContext(ctx, plan.AccountID, NrqlConditionsSearchCriteria{
+		PolicyID: strconv.Itoa(plan.PolicyID),
+	})
+	if err != nil {
+		return err
+	}
+
+	current := map[string][]*NrqlAlertCondition{}
+	for _, c := range conditions {
+		current[c.Name] = append(current[c.Name], c)
+	}
+
+	wanted := map[string]bool{}
+	for i := range desired {
+		d := desired[i]
+		wanted[d.Name] = true
+
+		if len(current[d.Name]) > 1 {
+			return fmt.Errorf("%s %q is ambiguous, %d conditions have this name", ChangeKinds.NrqlCondition, d.Name, len(current[d.Name]))
+		}
+
+		if len(current[d.Name]) == 0 {
+			if err := plan.add(Change{Action: ChangeActions.Create, Kind: ChangeKinds.NrqlCondition, Name: d.Name, nrqlCondition: &d}, nil, d); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		c := current[d.Name][0]
+		err := plan.add(Change{Action: ChangeActions.Update, Kind: ChangeKinds.NrqlCondition, Name: d.Name, ID: c.ID, nrqlCondition: &d}, c.input(), d)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, c := range conditions {
+		if !wanted[c.Name] {
+			plan.Changes = append(plan.Changes, Change{Action: ChangeActions.Delete, Kind: ChangeKinds.NrqlCondition, Name: c.Name, ID: c.ID})
+		}
+	}
+
+	return nil
+}
+
+func (a *Alerts) planInfrastructureConditions(ctx context.Context, plan *PolicyPlan, desired []InfrastructureCondition) error {
+	conditions, err := a.ListInfrastructureConditionsWithContext(ctx, plan.PolicyID)
+	if err != nil {
+		return err
+	}
+
+	current := map[string][]InfrastructureCondition{}
+	for _, c := range conditions {
+		current[c.Name] = append(current[c.Name], c)
+	}
+
+	wanted := map[string]bool{}
+	for i := range desired {
+		d := desired[i].withoutServerFields()
+		wanted[d.Name] = true
+
+		if len(current[d.Name]) > 1 {
+			return fmt.Errorf("%s %q is ambiguous, %d conditions have this name", ChangeKinds.InfrastructureCondition, d.Name, len(current[d.Name]))
+		}
+
+		if len(current[d.Name]) == 0 {
+			if err := plan.add(Change{Action: ChangeActions.Create, Kind: ChangeKinds.InfrastructureCondition, Name: d.Name, infrastructureCondition: &d}, nil, d); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		c := current[d.Name][0]
+		err := plan.add(Change{Action: ChangeActions.Update, Kind: ChangeKinds.InfrastructureCondition, Name: d.Name, ID: strconv.Itoa(c.ID), infrastructureCondition: &d}, c.withoutServerFields(), d)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, c := range conditions {
+		if !wanted[c.Name] {
+			plan.Changes = append(plan.Changes, Change{Action: ChangeActions.Delete, Kind: ChangeKinds.InfrastructureCondition, Name: c.Name, ID: strconv.Itoa(c.ID)})
+		}
+	}
+
+	return nil
+}
+
+// add adds a change to the plan with the differences between the current and
+// the desired object, unless an update changes nothing.
+func (p *PolicyPlan) add(change Change, current interface{}, desired interface{}) error {
+	fields, err := diffObjects(current, desired)
+	if err != nil {
+		return err
+	}
+
+	if change.Action == ChangeActions.Update && len(fields) == 0 {
+		return nil
+	}
+
+	change.Fields = fields
+	p.Changes = append(p.Changes, change)
+
+	return nil
+}
+
+func (a *Alerts) applyChange(ctx context.Context, plan *PolicyPlan, c Change) error {
+	policyID := strconv.Itoa(plan.PolicyID)
+
+	switch c.Kind {
+	case ChangeKinds.NrqlCondition:
+		switch c.Action {
+		case ChangeActions.Create:
+			_, err := a.CreateNrqlConditionMutationWithContext(ctx, plan.AccountID, policyID, *c.nrqlCondition)
+			return err
+		case ChangeActions.Update:
+			_, err := a.UpdateNrqlConditionMutationWithContext(ctx, plan.AccountID, c.ID, *c.nrqlCondition)
+			return err
+		case ChangeActions.Delete:
+			_, err := a.DeleteNrqlConditionMutationWithContext(ctx, plan.AccountID, c.ID)
+			return err
+		}
+
+	case ChangeKinds.InfrastructureCondition:
+		switch c.Action {
+		case ChangeActions.Create:
+			condition := *c.infrastructureCondition
+			condition.PolicyID = plan.PolicyID
+
+			_, err := a.CreateInfrastructureConditionWithContext(ctx, condition)
+			return err
+		case ChangeActions.Update:
+			id, err := strconv.Atoi(c.ID)
+			if err != nil {
+				return err
+			}
+
+			condition := *c.infrastructureCondition
+			condition.ID = id
+			condition.PolicyID = plan.PolicyID
+
+			_, err = a.UpdateInfrastructureConditionWithContext(ctx, condition)
+			return err
+		case ChangeActions.Delete:
+			id, err := strconv.Atoi(c.ID)
+			if err != nil {
+				return err
+			}
+
+			return a.DeleteInfrastructureConditionWithContext(ctx, id)
+		}
+
+	case ChangeKinds.Channel:
+		return a.applyChannelChange(ctx, plan, c)
+	}
+
+	return fmt.Errorf("unsupported change %s of %s", c.Action, c.Kind)
+}
+
+func (a *Alerts) applyChannelChange(ctx context.Context, plan *PolicyPlan, c Change) error {
+	var id int
+	if c.ID != "" {
+		var err error
+		if id, err = strconv.Atoi(c.ID); err != nil {
+			return err
+		}
+	}
+
+	switch c.Action {
+	case ChangeActions.Delete:
+		_, err := a.DeletePolicyChannelWithContext(ctx, plan.PolicyID, id)
+		return err
+
+	case ChangeActions.Create:
+		created, err := a.CreateChannelWithContext(ctx, *c.channel)
+		if err != nil {
+			return err
+		}
+
+		id = created.ID
+	}
+
+	_, err := a.UpdatePolicyChannelsWithContext(ctx, plan.PolicyID, []int{id})
+	return err
+}
+
+// diffObjects returns the differences of the fields set in the desired
+// object with the current one, as serialized to JSON.  A nil current object
+// differs by all the fields of the desired one.
+func diffObjects(current interface{}, desired interface{}) ([]FieldDiff, error) {
+	var c interface{}
+	if current != nil {
+		var err error
+		if c, err = decodeDiffObject(current); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := decodeDiffObject(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []FieldDiff{}
+	diffValues("", c, d, &diffs)
+
+	return diffs, nil
+}
+
+func decodeDiffObject(v interface{}) (interface{}, error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.UseNumber()
+
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+func diffValues(path string, current interface{}, desired interface{}, diffs *[]FieldDiff) {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if current != nil && !ok {
+			*diffs = append(*diffs, FieldDiff{Path: path, Current: current, Desired: desired})
+			return
+		}
+
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			diffValues(p, c[k], d[k], diffs)
+		}
+
+	case []interface{}:
+		c, ok := current.([]interface{})
+		if !ok || len(c) != len(d) {
+			*diffs = append(*diffs, FieldDiff{Path: path, Current: current, Desired: desired})
+			return
+		}
+
+		for i := range d {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), c[i], d[i], diffs)
+		}
+
+	default:
+		if !reflect.DeepEqual(current, desired) {
+			*diffs = append(*diffs, FieldDiff{Path: path, Current: current, Desired: desired})
+		}
+	}
+}
+
+func diffValueString(v interface{}) string {
+	if v == nil {
+		return "(unset)"
+	}
+
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(j)
+}
+
+func (c InfrastructureCondition) withoutServerFields() InfrastructureCondition {
+	c.ID = 0
+	c.PolicyID = 0
+	c.CreatedAt = nil
+	c.UpdatedAt = nil
+
+	return c
+}
+
+// withoutSecrets returns the channel without the fields not compared by
+// plans: its ID and links, and the secrets of its configuration, which New
+// Relic does not return.
+func (c Channel) withoutSecrets() Channel {
+	c.ID = 0
+	c.Links = ChannelLinks{}
+	c.Configuration.AuthToken = ""
+	c.Configuration.APIKey = ""
+	c.Configuration.Key = ""
+	c.Configuration.ServiceKey = ""
+	c.Configuration.AuthPassword = ""
+	c.Configuration.Headers = nil
+
+	return c
+}
+
+func nrqlConditionInputNames(conditions []NrqlConditionInput) []string {
+	names := []string{}
+	for _, c := range conditions {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+func infrastructureConditionNames(conditions []InfrastructureCondition) []string {
+	names := []string{}
+	for _, c := range conditions {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+func channelNames(channels []Channel) []string {
+	names := []string{}
+	for _, c := range channels {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
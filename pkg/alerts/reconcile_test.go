@@ -0,0 +1,231 @@
+// +build unit
+
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/testhelpers/fakeserver"
+)
+
+func TestReconcilePolicy(t *testing.T) {
+	t.Parallel()
+	server, client, nrqlConditions := newPolicyDocumentTestServer(t, false)
+
+	server.HandleNerdGraph("alertsNrqlConditionStaticUpdate", func(vars map[string]interface{}) (interface{}, error) {
+		for _, c := range *nrqlConditions {
+			if c["id"] == vars["id"] {
+				for k, v := range vars["condition"].(map[string]interface{}) {
+					c[k] = v
+				}
+
+				return map[string]interface{}{"alertsNrqlConditionStaticUpdate": c}, nil
+			}
+		}
+
+		return nil, nil
+	})
+
+	policy, err := client.CreatePolicy(Policy{Name: "policy", IncidentPreference: IncidentPreferenceTypes.PerPolicy})
+	require.NoError(t, err)
+
+	email := ChannelConfiguration{Recipients: "oncall@example.com"}
+
+	old, err := client.CreateChannel(Channel{Name: "old", Type: ChannelTypes.Email, Configuration: email})
+	require.NoError(t, err)
+	_, err = client.UpdatePolicyChannels(policy.ID, []int{old.ID})
+	require.NoError(t, err)
+
+	_, err = client.CreateChannel(Channel{Name: "keep", Type: ChannelTypes.Email, Configuration: email})
+	require.NoError(t, err)
+
+	threshold := 10.0
+	nrqlCondition := func(name string, query string) NrqlConditionInput {
+		return NrqlConditionInput{
+			NrqlConditionBase: NrqlConditionBase{
+				Name:    name,
+				Enabled: true,
+				Type:    NrqlConditionTypes.Static,
+				Nrql:    NrqlConditionQuery{Query: query},
+				Terms:   []NrqlConditionTerm{{Operator: AlertsNRQLConditionTermsOperatorTypes.ABOVE, Threshold: &threshold, ThresholdDuration: 300}},
+			},
+		}
+	}
+
+	for _, c := range []NrqlConditionInput{
+		nrqlCondition("errors", "SELECT count(*) FROM TransactionError"),
+		nrqlCondition("stale", "SELECT count(*) FROM Transaction"),
+	} {
+		_, err = client.CreateNrqlConditionStaticMutation(1, strconv.Itoa(policy.ID), c)
+		require.NoError(t, err)
+	}
+
+	infrastructureCondition := func(name string, threshold float64) InfrastructureCondition {
+		return InfrastructureCondition{
+			Name:       name,
+			Type:       "infra_metric",
+			Enabled:    true,
+			Event:      "SystemSample",
+			Select:     "cpuPercent",
+			Comparison: "above",
+			Critical:   &InfrastructureConditionThreshold{Duration: 5, Function: "all", Value: &threshold},
+		}
+	}
+
+	cpu := infrastructureCondition("cpu", 90)
+	cpu.PolicyID = policy.ID
+	_, err = client.CreateInfrastructureCondition(cpu)
+	require.NoError(t, err)
+
+	desired := PolicyState{
+		NrqlConditions: []NrqlConditionInput{
+			nrqlCondition("errors", "SELECT count(*) FROM TransactionError WHERE appName = 'app'"),
+			nrqlCondition("latency", "SELECT average(duration) FROM Transaction"),
+		},
+		InfrastructureConditions: []InfrastructureCondition{
+			infrastructureCondition("cpu", 80),
+			infrastructureCondition("disk", 95),
+		},
+		Channels: []Channel{
+			{Name: "keep", Type: ChannelTypes.Email, Configuration: email},
+			{Name: "new", Type: ChannelTypes.Email, Configuration: email},
+		},
+	}
+
+	plan, err := client.ReconcilePolicy(1, policy.ID, desired, true)
+	require.NoError(t, err)
+
+	type change struct {
+		Action ChangeAction
+		Kind   ChangeKind
+		Name   string
+	}
+
+	changes := []change{}
+	for _, c := range plan.Changes {
+		changes = append(changes, change{c.Action, c.Kind, c.Name})
+	}
+
+	assert.Equal(t, []change{
+		{ChangeActions.Update, ChangeKinds.Channel, "keep"},
+		{ChangeActions.Create, ChangeKinds.Channel, "new"},
+		{ChangeActions.Delete, ChangeKinds.Channel, "old"},
+		{ChangeActions.Update, ChangeKinds.NrqlCondition, "errors"},
+		{ChangeActions.Create, ChangeKinds.NrqlCondition, "latency"},
+		{ChangeActions.Delete, ChangeKinds.NrqlCondition, "stale"},
+		{ChangeActions.Update, ChangeKinds.InfrastructureCondition, "cpu"},
+		{ChangeActions.Create, ChangeKinds.InfrastructureCondition, "disk"},
+	}, changes)
+
+	assert.Equal(t, []FieldDiff{{Path: "links.policy_ids", Desired: policy.ID}}, plan.Changes[0].Fields)
+	assert.Equal(t, []FieldDiff{{
+		Path:    "nrql.query",
+		Current: "SELECT count(*) FROM TransactionError",
+		Desired: "SELECT count(*) FROM TransactionError WHERE appName = 'app'",
+	}}, plan.Changes[3].Fields)
+	assert.Equal(t, []FieldDiff{{
+		Path:    "critical_threshold.value",
+		Current: json.Number("90"),
+		Desired: json.Number("80"),
+	}}, plan.Changes[6].Fields)
+	assert.Contains(t, plan.String(), "update infrastructure_condition \"cpu\"")
+	assert.Contains(t, plan.String(), "  critical_threshold.value: 90 => 80\n")
+
+	// The dry run changed nothing.
+	assert.Len(t, server.Objects(fakeserver.Resources.AlertChannels), 2)
+	assert.Len(t, server.Objects(fakeserver.Resources.InfrastructureConditions), 1)
+	assert.Len(t, *nrqlConditions, 2)
+
+	_, err = client.ReconcilePolicy(1, policy.ID, desired, false)
+	require.NoError(t, err)
+
+	plan, err = client.PlanPolicy(1, policy.ID, desired)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty(), plan.String())
+	assert.Equal(t, "no changes", plan.String())
+
+	// Channels unlinked from the policy are kept.
+	assert.Len(t, server.Objects(fakeserver.Resources.AlertChannels), 3)
+	assert.Len(t, server.Objects(fakeserver.Resources.InfrastructureConditions), 2)
+	assert.Len(t, *nrqlConditions, 2)
+}
+
+func TestPlanPolicy_channelDrift(t *testing.T) {
+	t.Parallel()
+	_, client, _ := newPolicyDocumentTestServer(t, false)
+
+	policy, err := client.CreatePolicy(Policy{Name: "policy", IncidentPreference: IncidentPreferenceTypes.PerPolicy})
+	require.NoError(t, err)
+
+	channel, err := client.CreateChannel(Channel{Name: "on-call", Type: ChannelTypes.Email, Configuration: ChannelConfiguration{Recipients: "a@example.com"}})
+	require.NoError(t, err)
+
+	// Secrets are not returned, and so not compared.
+	desired := PolicyState{
+		Channels: []Channel{{Name: "on-call", Type: ChannelTypes.Email, Configuration: ChannelConfiguration{Recipients: "b@example.com", AuthPassword: "secret"}}},
+	}
+
+	plan, err := client.ReconcilePolicy(1, policy.ID, desired, false)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, []FieldDiff{{Path: "links.policy_ids", Current: nil, Desired: policy.ID}}, plan.Changes[0].Fields)
+	require.Len(t, plan.Drift, 1)
+	assert.Equal(t, []FieldDiff{{Path: "configuration.recipients", Current: "a@example.com", Desired: "b@example.com"}}, plan.Drift[0].Fields)
+
+	// The shared channel is linked, not replaced.
+	channels, err := client.ListChannels()
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assert.Equal(t, channel.ID, channels[0].ID)
+	assert.Equal(t, "a@example.com", channels[0].Configuration.Recipients)
+	assert.Equal(t, []int{policy.ID}, channels[0].Links.PolicyIDs)
+
+	plan, err = client.PlanPolicy(1, policy.ID, desired)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+	assert.Equal(t, fmt.Sprintf("drift of update channel \"on-call\" (%d)\n  configuration.recipients: \"a@example.com\" => \"b@example.com\"\n", channel.ID), plan.String())
+}
+
+func TestPlanPolicy_ambiguousConditions(t *testing.T) {
+	t.Parallel()
+	_, client, _ := newPolicyDocumentTestServer(t, false)
+
+	policy, err := client.CreatePolicy(Policy{Name: "policy", IncidentPreference: IncidentPreferenceTypes.PerPolicy})
+	require.NoError(t, err)
+
+	threshold := 90.0
+	cpu := InfrastructureCondition{
+		PolicyID:   policy.ID,
+		Name:       "cpu",
+		Type:       "infra_metric",
+		Enabled:    true,
+		Event:      "SystemSample",
+		Select:     "cpuPercent",
+		Comparison: "above",
+		Critical:   &InfrastructureConditionThreshold{Duration: 5, Function: "all", Value: &threshold},
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = client.CreateInfrastructureCondition(cpu)
+		require.NoError(t, err)
+	}
+
+	_, err = client.PlanPolicy(1, policy.ID, PolicyState{InfrastructureConditions: []InfrastructureCondition{cpu}})
+	assert.EqualError(t, err, `infrastructure_condition "cpu" is ambiguous, 2 conditions have this name`)
+}
+
+func TestPlanPolicy_invalidState(t *testing.T) {
+	t.Parallel()
+	_, client, _ := newPolicyDocumentTestServer(t, false)
+
+	_, err := client.PlanPolicy(1, 1, PolicyState{
+		InfrastructureConditions: []InfrastructureCondition{{Name: "cpu"}, {Name: "cpu"}},
+	})
+	assert.EqualError(t, err, `desired infrastructure_condition "cpu" is defined more than once`)
+}
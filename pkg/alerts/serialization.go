@@ -12,21 +12,26 @@ func (c *ConditionTerm) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	threshold, err := strconv.ParseFloat(v["threshold"].(string), 64)
+	str := func(key string) string {
+		s, _ := v[key].(string)
+		return s
+	}
+
+	threshold, err := strconv.ParseFloat(str("threshold"), 64)
 	if err != nil {
 		return err
 	}
 
-	duration, err := strconv.ParseInt(v["duration"].(string), 10, 32)
+	duration, err := strconv.ParseInt(str("duration"), 10, 32)
 	if err != nil {
 		return err
 	}
 
 	c.Threshold = threshold
 	c.Duration = int(duration)
-	c.Operator = OperatorType(v["operator"].(string))
-	c.Priority = PriorityType(v["priority"].(string))
-	c.TimeFunction = TimeFunctionType(v["time_function"].(string))
+	c.Operator = OperatorType(str("operator"))
+	c.Priority = PriorityType(str("priority"))
+	c.TimeFunction = TimeFunctionType(str("time_function"))
 
 	return nil
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // SyntheticsCondition represents a New Relic Synthetics alert condition.
@@ -24,26 +26,38 @@ func (a *Alerts) ListSyntheticsConditions(policyID int) ([]*SyntheticsCondition,
 // ListSyntheticsConditionsWithContext returns a list of Synthetics alert conditions for a given policy.
 func (a *Alerts) ListSyntheticsConditionsWithContext(ctx context.Context, policyID int) ([]*SyntheticsCondition, error) {
 	conditions := []*SyntheticsCondition{}
-	nextURL := a.config.Region().RestURL("/alerts_synthetics_conditions.json")
-	queryParams := listSyntheticsConditionsParams{
-		PolicyID: policyID,
+
+	it := a.ListSyntheticsConditionsIteratorWithContext(ctx, policyID)
+	for it.Next() {
+		conditions = append(conditions, it.Value().(*SyntheticsCondition))
 	}
 
-	for nextURL != "" {
-		response := syntheticsConditionsResponse{}
-		resp, err := a.client.GetWithContext(ctx, nextURL, &queryParams, &response)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	return conditions, nil
+}
 
-		conditions = append(conditions, response.Conditions...)
+// ListSyntheticsConditionsIterator returns an iterator over the Synthetics alert conditions for a given policy.
+func (a *Alerts) ListSyntheticsConditionsIterator(policyID int, opts ...paging.Option) *paging.Iterator {
+	return a.ListSyntheticsConditionsIteratorWithContext(context.Background(), policyID, opts...)
+}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+// ListSyntheticsConditionsIteratorWithContext returns an iterator over the Synthetics alert conditions for a given policy.
+func (a *Alerts) ListSyntheticsConditionsIteratorWithContext(ctx context.Context, policyID int, opts ...paging.Option) *paging.Iterator {
+	queryParams := listSyntheticsConditionsParams{
+		PolicyID: policyID,
 	}
 
-	return conditions, nil
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("/alerts_synthetics_conditions.json"), &queryParams,
+		func() interface{} { return &syntheticsConditionsResponse{} },
+		func(page interface{}) []interface{} {
+			return http.Items(page.(*syntheticsConditionsResponse).Conditions)
+		},
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // GetSyntheticsCondition retrieves a specific Synthetics alert condition.
@@ -52,20 +66,21 @@ func (a *Alerts) GetSyntheticsCondition(policyID int, conditionID int) (*Synthet
 }
 
 // GetSyntheticsConditionWithContext retrieves a specific Synthetics alert condition.
+// Neither the REST API nor NerdGraph looking these conditions up by ID, the
+// conditions of the policy are listed until the condition is found.
 func (a *Alerts) GetSyntheticsConditionWithContext(ctx context.Context, policyID int, conditionID int) (*SyntheticsCondition, error) {
-	conditions, err := a.ListSyntheticsConditionsWithContext(ctx, policyID)
-
+	condition, err := findItem(a.ListSyntheticsConditionsIteratorWithContext(ctx, policyID), func(item interface{}) bool {
+		return item.(*SyntheticsCondition).ID == conditionID
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, c := range conditions {
-		if c.ID == conditionID {
-			return c, nil
-		}
+	if condition == nil {
+		return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, conditionID)
 	}
 
-	return nil, errors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, conditionID)
+	return condition.(*SyntheticsCondition), nil
 }
 
 // CreateSyntheticsCondition creates a new Synthetics alert condition.
@@ -75,6 +90,10 @@ func (a *Alerts) CreateSyntheticsCondition(policyID int, condition SyntheticsCon
 
 // CreateSyntheticsConditionWithContext creates a new Synthetics alert condition.
 func (a *Alerts) CreateSyntheticsConditionWithContext(ctx context.Context, policyID int, condition SyntheticsCondition) (*SyntheticsCondition, error) {
+	if err := a.validate(&condition); err != nil {
+		return nil, err
+	}
+
 	resp := syntheticsConditionResponse{}
 	reqBody := syntheticsConditionRequest{condition}
 	url := fmt.Sprintf("/alerts_synthetics_conditions/policies/%d.json", policyID)
@@ -94,6 +113,10 @@ func (a *Alerts) UpdateSyntheticsCondition(condition SyntheticsCondition) (*Synt
 
 // UpdateSyntheticsConditionWithContext updates an existing Synthetics alert condition.
 func (a *Alerts) UpdateSyntheticsConditionWithContext(ctx context.Context, condition SyntheticsCondition) (*SyntheticsCondition, error) {
+	if err := a.validate(&condition); err != nil {
+		return nil, err
+	}
+
 	resp := syntheticsConditionResponse{}
 	reqBody := syntheticsConditionRequest{condition}
 	url := fmt.Sprintf("/alerts_synthetics_conditions/%d.json", condition.ID)
@@ -0,0 +1,404 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+const (
+	// defaultAggregationWindow is the aggregation window of NRQL conditions,
+	// in seconds, when their signal does not set one.
+	defaultAggregationWindow = 60
+
+	minViolationTimeLimitSeconds = 300
+	maxViolationTimeLimitSeconds = 2592000
+
+	minInfrastructureDuration = 1
+	maxInfrastructureDuration = 60
+)
+
+// conditionTermDurations are the durations of the terms of alert conditions,
+// in minutes, allowed by the REST API.
+var conditionTermDurations = []int{5, 10, 15, 30, 60, 120}
+
+// Types of infrastructure alert conditions.
+const (
+	infrastructureMetricType         = "infra_metric"
+	infrastructureProcessRunningType = "infra_process_running"
+	infrastructureHostNotReportType  = "infra_host_not_reporting"
+)
+
+// validator collects the invalid fields of an input.
+type validator struct {
+	fields []errors.FieldError
+}
+
+func (v *validator) addf(field string, format string, args ...interface{}) {
+	v.fields = append(v.fields, errors.FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) required(field string, set bool) {
+	if !set {
+		v.addf(field, "is required")
+	}
+}
+
+func (v *validator) oneOf(field string, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	v.addf(field, "unknown value %q, expected one of %s", value, list(allowed))
+}
+
+// err returns the error for the invalid fields of the named input, if any.
+func (v *validator) err(kind string, name string) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+
+	input := kind
+	if name != "" {
+		input = fmt.Sprintf("%s %q", kind, name)
+	}
+
+	return errors.NewValidationError(input, v.fields)
+}
+
+// list formats values as "a, b or c".
+func list(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
+
+// Validate checks the condition against the rules documented for NRQL alert
+// conditions before it is created, returning an *errors.ValidationError
+// listing every invalid field.  It is called by the mutations creating or
+// updating the condition, unless disabled in the configuration of the
+// client.
+func (c *NrqlConditionInput) Validate() error {
+	return c.validate(false)
+}
+
+// validate checks the condition, only checking the fields set for the partial
+// inputs of updates.
+func (c *NrqlConditionInput) validate(partial bool) error {
+	v := validator{}
+
+	if !partial {
+		v.required("name", c.Name != "")
+		v.required("nrql.query", c.Nrql.Query != "")
+		v.required("terms", len(c.Terms) > 0)
+	}
+
+	c.validateType(&v, partial)
+
+	// The aggregation window of the condition updated is unknown unless set.
+	aggregationWindow := defaultAggregationWindow
+	if partial && (c.Signal == nil || c.Signal.AggregationWindow == nil) {
+		aggregationWindow = 0
+	}
+
+	if c.Signal != nil {
+		if c.Signal.AggregationWindow != nil {
+			aggregationWindow = *c.Signal.AggregationWindow
+			if aggregationWindow <= 0 {
+				v.addf("signal.aggregationWindow", "must be positive")
+			}
+		}
+
+		fillOption := AlertsFillOption("")
+		if c.Signal.FillOption != nil {
+			fillOption = *c.Signal.FillOption
+			v.oneOf("signal.fillOption", string(fillOption),
+				string(AlertsFillOptionTypes.LAST_VALUE), string(AlertsFillOptionTypes.NONE), string(AlertsFillOptionTypes.STATIC))
+		}
+
+		if c.Signal.FillValue != nil && fillOption != AlertsFillOptionTypes.STATIC {
+			v.addf("signal.fillValue", "can only be set with the %s fill option", AlertsFillOptionTypes.STATIC)
+		}
+
+		if c.Signal.FillValue == nil && fillOption == AlertsFillOptionTypes.STATIC {
+			v.addf("signal.fillValue", "is required with the %s fill option", AlertsFillOptionTypes.STATIC)
+		}
+	}
+
+	priorities := map[NrqlConditionPriority]int{}
+	for i, term := range c.Terms {
+		field := fmt.Sprintf("terms[%d]", i)
+
+		priority := term.Priority
+		if priority == "" {
+			priority = NrqlConditionPriorities.Critical
+		}
+
+		v.oneOf(field+".priority", string(priority),
+			string(NrqlConditionPriorities.Critical), string(NrqlConditionPriorities.Warning))
+
+		if j, ok := priorities[priority]; ok {
+			v.addf(field+".priority", "duplicates the %s priority of terms[%d]", priority, j)
+		}
+		priorities[priority] = i
+
+		if term.Operator == "" {
+			v.required(field+".operator", false)
+		} else if c.Type == NrqlConditionTypes.Baseline || c.Type == NrqlConditionTypes.Outlier {
+			if term.Operator != AlertsNRQLConditionTermsOperatorTypes.ABOVE {
+				v.addf(field+".operator", "must be %s for %s conditions", AlertsNRQLConditionTermsOperatorTypes.ABOVE, c.Type)
+			}
+		} else {
+			v.oneOf(field+".operator", string(term.Operator),
+				string(AlertsNRQLConditionTermsOperatorTypes.ABOVE),
+				string(AlertsNRQLConditionTermsOperatorTypes.BELOW),
+				string(AlertsNRQLConditionTermsOperatorTypes.EQUALS))
+		}
+
+		v.required(field+".threshold", term.Threshold != nil)
+
+		switch {
+		case term.ThresholdDuration <= 0:
+			v.required(field+".thresholdDuration", false)
+		case aggregationWindow > 0 && term.ThresholdDuration%aggregationWindow != 0:
+			v.addf(field+".thresholdDuration", "must be a multiple of the aggregation window of %d seconds", aggregationWindow)
+		}
+
+		if term.ThresholdOccurrences != "" {
+			v.oneOf(field+".thresholdOccurrences", string(term.ThresholdOccurrences),
+				string(ThresholdOccurrences.All), string(ThresholdOccurrences.AtLeastOnce))
+		}
+	}
+
+	if c.ViolationTimeLimit != "" && c.ViolationTimeLimitSeconds != 0 {
+		v.addf("violationTimeLimitSeconds", "can not be set along with violationTimeLimit")
+	}
+
+	if c.ViolationTimeLimitSeconds != 0 &&
+		(c.ViolationTimeLimitSeconds < minViolationTimeLimitSeconds || c.ViolationTimeLimitSeconds > maxViolationTimeLimitSeconds) {
+		v.addf("violationTimeLimitSeconds", "must be between %d and %d", minViolationTimeLimitSeconds, maxViolationTimeLimitSeconds)
+	}
+
+	return v.err("NRQL condition", c.Name)
+}
+
+// validateType checks that the condition is of a known type, and only sets
+// the fields applying to its type.
+func (c *NrqlConditionInput) validateType(v *validator, partial bool) {
+	types := []string{string(NrqlConditionTypes.Baseline), string(NrqlConditionTypes.Static), string(NrqlConditionTypes.Outlier)}
+
+	// The fields of each type, required or not.
+	var fields map[string]bool
+
+	switch c.Type {
+	case NrqlConditionTypes.Baseline:
+		fields = map[string]bool{"baselineDirection": true}
+	case NrqlConditionTypes.Static:
+		fields = map[string]bool{"valueFunction": false}
+	case NrqlConditionTypes.Outlier:
+		fields = map[string]bool{"expectedGroups": true, "openViolationOnGroupOverlap": false}
+	case "":
+		v.addf("type", "is required, expected one of %s", list(types))
+		return
+	default:
+		v.oneOf("type", string(c.Type), types...)
+		return
+	}
+
+	set := map[string]bool{
+		"baselineDirection":           c.BaselineDirection != nil,
+		"valueFunction":               c.ValueFunction != nil,
+		"expectedGroups":              c.ExpectedGroups != nil,
+		"openViolationOnGroupOverlap": c.OpenViolationOnGroupOverlap != nil,
+	}
+
+	for _, field := range []string{"baselineDirection", "valueFunction", "expectedGroups", "openViolationOnGroupOverlap"} {
+		required, ok := fields[field]
+
+		if set[field] && !ok {
+			v.addf(field, "can not be set for %s conditions", c.Type)
+		}
+
+		if !set[field] && required && !partial {
+			v.addf(field, "is required for %s conditions", c.Type)
+		}
+	}
+
+	if c.Type == NrqlConditionTypes.Baseline && c.BaselineDirection != nil {
+		v.oneOf("baselineDirection", string(*c.BaselineDirection),
+			string(NrqlBaselineDirections.LowerOnly), string(NrqlBaselineDirections.UpperAndLower), string(NrqlBaselineDirections.UpperOnly))
+	}
+
+	if c.Type == NrqlConditionTypes.Static && c.ValueFunction != nil {
+		v.oneOf("valueFunction", string(*c.ValueFunction),
+			string(NrqlConditionValueFunctions.SingleValue), string(NrqlConditionValueFunctions.Sum))
+	}
+
+	if c.Type == NrqlConditionTypes.Outlier && c.ExpectedGroups != nil && *c.ExpectedGroups < 1 {
+		v.addf("expectedGroups", "must be at least 1")
+	}
+}
+
+// Validate checks the condition against the rules documented for alert
+// conditions of the REST API, such as the durations allowed for its terms,
+// returning an *errors.ValidationError listing every invalid field.  Its type
+// and metric are only required, the API judging the ones not enumerated by
+// ConditionTypes and MetricTypes.  It is called before the condition is
+// created or updated, unless disabled in the configuration of the client.
+func (c *Condition) Validate() error {
+	v := validator{}
+
+	v.required("name", c.Name != "")
+	v.required("type", c.Type != "")
+	v.required("metric", c.Metric != "")
+
+	if c.Metric == MetricTypes.UserDefined {
+		v.required("user_defined.metric", c.UserDefined.Metric != "")
+
+		if c.UserDefined.ValueFunction == "" {
+			v.required("user_defined.value_function", false)
+		} else {
+			v.oneOf("user_defined.value_function", string(c.UserDefined.ValueFunction),
+				string(ValueFunctionTypes.Average), string(ValueFunctionTypes.Min), string(ValueFunctionTypes.Max),
+				string(ValueFunctionTypes.Total), string(ValueFunctionTypes.SampleSize), string(ValueFunctionTypes.SingleValue))
+		}
+	}
+
+	v.required("terms", len(c.Terms) > 0)
+
+	durations := make([]string, len(conditionTermDurations))
+	for i, d := range conditionTermDurations {
+		durations[i] = fmt.Sprint(d)
+	}
+
+	for i, term := range c.Terms {
+		field := fmt.Sprintf("terms[%d]", i)
+
+		v.oneOf(field+".duration", fmt.Sprint(term.Duration), durations...)
+		v.oneOf(field+".operator", string(term.Operator),
+			string(OperatorTypes.Above), string(OperatorTypes.Below), string(OperatorTypes.Equal))
+
+		// The priority defaults to critical, and the time function is left
+		// for the API to check when not set.
+		priority := term.Priority
+		if priority == "" {
+			priority = PriorityTypes.Critical
+		}
+
+		v.oneOf(field+".priority", string(priority),
+			string(PriorityTypes.Critical), string(PriorityTypes.Warning))
+
+		if term.TimeFunction != "" {
+			v.oneOf(field+".time_function", string(term.TimeFunction),
+				string(TimeFunctionTypes.All), string(TimeFunctionTypes.Any))
+		}
+	}
+
+	return v.err("condition", c.Name)
+}
+
+// Validate checks the condition against the rules documented for
+// infrastructure alert conditions, returning an *errors.ValidationError
+// listing every invalid field.  It is called before the condition is created
+// or updated, unless disabled in the configuration of the client.
+func (c *InfrastructureCondition) Validate() error {
+	v := validator{}
+
+	v.required("name", c.Name != "")
+
+	switch c.Type {
+	case "":
+		v.required("type", false)
+	case infrastructureMetricType:
+		v.required("event_type", c.Event != "")
+		v.required("select_value", c.Select != "")
+		c.validateComparison(&v)
+	case infrastructureProcessRunningType:
+		c.validateComparison(&v)
+	case infrastructureHostNotReportType:
+	default:
+		v.oneOf("type", c.Type, infrastructureMetricType, infrastructureProcessRunningType, infrastructureHostNotReportType)
+	}
+
+	v.required("critical_threshold", c.Critical != nil)
+	c.Critical.validate(&v, "critical_threshold")
+	c.Warning.validate(&v, "warning_threshold")
+
+	return v.err("infrastructure condition", c.Name)
+}
+
+func (c *InfrastructureCondition) validateComparison(v *validator) {
+	if c.Comparison == "" {
+		v.required("comparison", false)
+		return
+	}
+
+	v.oneOf("comparison", c.Comparison, "above", "below", "equal")
+}
+
+func (t *InfrastructureConditionThreshold) validate(v *validator, field string) {
+	if t == nil {
+		return
+	}
+
+	if t.Duration < minInfrastructureDuration || t.Duration > maxInfrastructureDuration {
+		v.addf(field+".duration_minutes", "must be between %d and %d", minInfrastructureDuration, maxInfrastructureDuration)
+	}
+
+	if t.Function != "" {
+		v.oneOf(field+".time_function", t.Function, string(TimeFunctionTypes.All), string(TimeFunctionTypes.Any))
+	}
+}
+
+// Validate checks the required fields of the condition, returning an
+// *errors.ValidationError listing every invalid field.  It is called before
+// the condition is created or updated, unless disabled in the configuration
+// of the client.
+func (c *SyntheticsCondition) Validate() error {
+	v := validator{}
+
+	v.required("name", c.Name != "")
+	v.required("monitor_id", c.MonitorID != "")
+
+	return v.err("synthetics condition", c.Name)
+}
+
+// validate validates an input before it is sent, unless disabled in the
+// configuration of the client.
+func (a *Alerts) validate(input interface{ Validate() error }) error {
+	if a.config.SkipInputValidation {
+		return nil
+	}
+
+	return input.Validate()
+}
+
+// validateNrqlCondition validates a NRQL condition before it is sent with the
+// mutation of the given type, unless disabled in the configuration of the
+// client.  The type of the condition defaults to the one of the mutation,
+// the inputs of the mutations not requiring it.
+func (a *Alerts) validateNrqlCondition(conditionType NrqlConditionType, condition NrqlConditionInput, partial bool) error {
+	if a.config.SkipInputValidation {
+		return nil
+	}
+
+	if condition.Type == "" {
+		condition.Type = conditionType
+	}
+
+	if condition.Type != conditionType {
+		v := validator{}
+		v.addf("type", "must be %s for the mutation of %s conditions", conditionType, conditionType)
+		return v.err("NRQL condition", condition.Name)
+	}
+
+	return condition.validate(partial)
+}
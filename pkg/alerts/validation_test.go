@@ -0,0 +1,196 @@
+// +build unit
+
+package alerts
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+func TestNrqlConditionInput_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := NrqlConditionInput{NrqlConditionBase: testNrqlConditionBase(NrqlConditionTypes.Static)}
+	require.NoError(t, valid.Validate())
+
+	window := 120
+	static := AlertsFillOptionTypes.STATIC
+	none := AlertsFillOptionTypes.NONE
+	fillValue := 1.0
+
+	cases := map[string]func(c *NrqlConditionInput){
+		`invalid NRQL condition: name: is required; nrql.query: is required; terms: is required`: func(c *NrqlConditionInput) {
+			c.Name = ""
+			c.Nrql.Query = ""
+			c.Terms = nil
+		},
+		`invalid NRQL condition "test": terms[0].thresholdDuration: must be a multiple of the aggregation window of 120 seconds`: func(c *NrqlConditionInput) {
+			c.Signal = &AlertsNrqlConditionSignal{AggregationWindow: &window}
+		},
+		`invalid NRQL condition "test": signal.fillValue: can only be set with the STATIC fill option`: func(c *NrqlConditionInput) {
+			c.Signal = &AlertsNrqlConditionSignal{FillOption: &none, FillValue: &fillValue}
+		},
+		`invalid NRQL condition "test": signal.fillValue: is required with the STATIC fill option`: func(c *NrqlConditionInput) {
+			c.Signal = &AlertsNrqlConditionSignal{FillOption: &static}
+		},
+		`invalid NRQL condition "test": violationTimeLimitSeconds: can not be set along with violationTimeLimit`: func(c *NrqlConditionInput) {
+			c.ViolationTimeLimit = NrqlConditionViolationTimeLimits.OneHour
+			c.ViolationTimeLimitSeconds = 3600
+		},
+		`invalid NRQL condition "test": violationTimeLimitSeconds: must be between 300 and 2592000`: func(c *NrqlConditionInput) {
+			c.ViolationTimeLimitSeconds = 60
+		},
+		`invalid NRQL condition "test": terms[1].priority: duplicates the CRITICAL priority of terms[0]; terms[1].operator: is required; terms[1].threshold: is required; terms[1].thresholdDuration: is required`: func(c *NrqlConditionInput) {
+			c.Terms = append(c.Terms, NrqlConditionTerm{})
+		},
+		`invalid NRQL condition "test": terms[0].operator: unknown value "EQUAL", expected one of ABOVE, BELOW or EQUALS`: func(c *NrqlConditionInput) {
+			c.Terms[0].Operator = "EQUAL"
+		},
+	}
+
+	for expected, modify := range cases {
+		c := NrqlConditionInput{NrqlConditionBase: testNrqlConditionBase(NrqlConditionTypes.Static)}
+		modify(&c)
+
+		assert.EqualError(t, c.Validate(), expected)
+	}
+
+	direction := NrqlBaselineDirections.UpperOnly
+	baseline := NrqlConditionInput{NrqlConditionBase: testNrqlConditionBase(NrqlConditionTypes.Baseline), BaselineDirection: &direction}
+	baseline.Terms[0].Operator = AlertsNRQLConditionTermsOperatorTypes.BELOW
+
+	err := baseline.Validate()
+	assert.EqualError(t, err, `invalid NRQL condition "test": terms[0].operator: must be ABOVE for BASELINE conditions`)
+
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []errors.FieldError{{Field: "terms[0].operator", Message: "must be ABOVE for BASELINE conditions"}}, validationErr.Fields())
+}
+
+func TestCondition_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() Condition {
+		return Condition{
+			Type:    ConditionTypes.APMApplicationMetric,
+			Name:    "apdex",
+			Enabled: true,
+			Metric:  MetricTypes.Apdex,
+			Terms:   []ConditionTerm{{Duration: 5, Operator: OperatorTypes.Below, Priority: PriorityTypes.Critical, Threshold: 0.7, TimeFunction: TimeFunctionTypes.All}},
+		}
+	}
+
+	c := valid()
+	require.NoError(t, c.Validate())
+
+	// The priority defaults to critical, and the time function is left to the API.
+	c.Terms[0].Priority = ""
+	c.Terms[0].TimeFunction = ""
+	require.NoError(t, c.Validate())
+
+	c.Terms[0].Priority = "info"
+	assert.EqualError(t, c.Validate(), `invalid condition "apdex": terms[0].priority: unknown value "info", expected one of critical or warning`)
+
+	// Types and metrics not enumerated are left to the API.
+	c = valid()
+	c.Type = "apm_jvm_metric"
+	c.Metric = "heap_memory_usage"
+	require.NoError(t, c.Validate())
+
+	c = valid()
+	c.Metric = MetricTypes.UserDefined
+	c.Terms[0].Duration = 7
+	assert.EqualError(t, c.Validate(), `invalid condition "apdex": user_defined.metric: is required; user_defined.value_function: is required; terms[0].duration: unknown value "7", expected one of 5, 10, 15, 30, 60 or 120`)
+
+	c = Condition{Type: "other"}
+	assert.EqualError(t, c.Validate(), `invalid condition: name: is required; metric: is required; terms: is required`)
+}
+
+func TestInfrastructureCondition_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, testInfrastructureCondition.Validate())
+
+	threshold := 90.0
+	c := InfrastructureCondition{
+		Name:     "cpu",
+		Type:     "infra_metric",
+		Critical: &InfrastructureConditionThreshold{Duration: 90, Function: "some", Value: &threshold},
+	}
+	assert.EqualError(t, c.Validate(), `invalid infrastructure condition "cpu": event_type: is required; select_value: is required; comparison: is required; critical_threshold.duration_minutes: must be between 1 and 60; critical_threshold.time_function: unknown value "some", expected one of all or any`)
+
+	c = InfrastructureCondition{Name: "host", Type: "infra_host_not_reporting"}
+	assert.EqualError(t, c.Validate(), `invalid infrastructure condition "host": critical_threshold: is required`)
+}
+
+func TestSyntheticsCondition_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, testSyntheticsCondition.Validate())
+
+	c := SyntheticsCondition{}
+	assert.EqualError(t, c.Validate(), `invalid synthetics condition: name: is required; monitor_id: is required`)
+}
+
+func TestValidation_beforeMutations(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+	alerts := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data": {"alertsNrqlConditionStaticUpdate": {"id": "1"}}, "condition": {}}`))
+		require.NoError(t, err)
+	}))
+
+	_, err := alerts.CreateCondition(1, Condition{})
+	assert.Error(t, err)
+	_, err = alerts.CreateInfrastructureCondition(InfrastructureCondition{})
+	assert.Error(t, err)
+	_, err = alerts.UpdateSyntheticsCondition(SyntheticsCondition{})
+	assert.Error(t, err)
+	_, err = alerts.CreateNrqlConditionStaticMutation(1, "10", NrqlConditionInput{})
+	assert.Error(t, err)
+	_, err = alerts.UpdateNrqlConditionBaselineMutation(1, "1", NrqlConditionInput{
+		NrqlConditionBase: NrqlConditionBase{Type: NrqlConditionTypes.Static},
+	})
+	assert.EqualError(t, err, `invalid NRQL condition: type: must be BASELINE for the mutation of BASELINE conditions`)
+	assert.Zero(t, requests)
+
+	// Updates only check the fields set, the type defaulting to the one of the mutation.
+	_, err = alerts.UpdateNrqlConditionStaticMutation(1, "1", NrqlConditionInput{NrqlConditionBase: NrqlConditionBase{Name: "renamed"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	// The aggregation window of the condition updated being unknown, any
+	// threshold duration is accepted.
+	threshold := 1.0
+	_, err = alerts.UpdateNrqlConditionStaticMutation(1, "1", NrqlConditionInput{NrqlConditionBase: NrqlConditionBase{
+		Terms: []NrqlConditionTerm{{Operator: AlertsNRQLConditionTermsOperatorTypes.ABOVE, Threshold: &threshold, ThresholdDuration: 90}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+
+	// Conditions of types not enumerated are sent.
+	_, err = alerts.CreateCondition(1, Condition{
+		Type:   "apm_jvm_metric",
+		Name:   "heap",
+		Metric: "heap_memory_usage",
+		Terms:  []ConditionTerm{{Duration: 5, Operator: OperatorTypes.Above, Threshold: 80}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	alerts.config.SkipInputValidation = true
+
+	_, err = alerts.CreateCondition(1, Condition{})
+	require.NoError(t, err)
+	_, err = alerts.CreateNrqlConditionStaticMutation(1, "10", NrqlConditionInput{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, requests)
+}
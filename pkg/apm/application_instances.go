@@ -1,6 +1,12 @@
 package apm
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
+)
 
 // ApplicationInstanceLinks represents all the links for a New Relic application instance.
 type ApplicationInstanceLinks struct {
@@ -31,26 +37,38 @@ type ListApplicationInstancesParams struct {
 // ListApplicationInstances is used to retrieve New Relic application instances.
 func (a *APM) ListApplicationInstances(applicationID int, params *ListApplicationInstancesParams) ([]*ApplicationInstance, error) {
 	instances := []*ApplicationInstance{}
-	url := fmt.Sprintf("/applications/%d/instances.json", applicationID)
-	nextURL := a.config.Region().RestURL(url)
-
-	for nextURL != "" {
-		response := applicationInstancesResponse{}
-		resp, err := a.client.Get(nextURL, &params, &response)
-
-		if err != nil {
-			return nil, err
-		}
 
-		instances = append(instances, response.ApplicationInstances...)
+	it := a.ListApplicationInstancesIterator(applicationID, params)
+	for it.Next() {
+		instances = append(instances, it.Value().(*ApplicationInstance))
+	}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return instances, nil
 }
 
+// ListApplicationInstancesIterator returns an iterator over the New Relic application instances.
+func (a *APM) ListApplicationInstancesIterator(applicationID int, params *ListApplicationInstancesParams, opts ...paging.Option) *paging.Iterator {
+	return a.ListApplicationInstancesIteratorWithContext(context.Background(), applicationID, params, opts...)
+}
+
+// ListApplicationInstancesIteratorWithContext returns an iterator over the New Relic application instances.
+func (a *APM) ListApplicationInstancesIteratorWithContext(ctx context.Context, applicationID int, params *ListApplicationInstancesParams, opts ...paging.Option) *paging.Iterator {
+	url := fmt.Sprintf("/applications/%d/instances.json", applicationID)
+
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL(url), &params,
+		func() interface{} { return &applicationInstancesResponse{} },
+		func(page interface{}) []interface{} {
+			return http.Items(page.(*applicationInstancesResponse).ApplicationInstances)
+		},
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 // GetApplicationInstance is used to retrieve a specific New Relic application instance.
 func (a *APM) GetApplicationInstance(applicationID int, instanceID int) (*ApplicationInstance, error) {
 	response := applicationInstanceResponse{}
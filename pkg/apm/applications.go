@@ -1,5 +1,11 @@
 package apm
 
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
+)
+
 // ApplicationsInterface interface should be refactored to be a global interface for fetching NR type things
 type ApplicationsInterface interface {
 	find(accountID int, name string) (*Application, error)
@@ -88,6 +94,22 @@ func (a *APM) ListApplications(params *ListApplicationsParams) ([]*Application,
 	return method.list(accountID, params)
 }
 
+// ListApplicationsIterator returns an iterator over the New Relic applications.
+func (a *APM) ListApplicationsIterator(params *ListApplicationsParams, opts ...paging.Option) *paging.Iterator {
+	return a.ListApplicationsIteratorWithContext(context.Background(), params, opts...)
+}
+
+// ListApplicationsIteratorWithContext returns an iterator over the New Relic applications.
+func (a *APM) ListApplicationsIteratorWithContext(ctx context.Context, params *ListApplicationsParams, opts ...paging.Option) *paging.Iterator {
+	accountID := 0
+
+	method := applicationsREST{
+		parent: a,
+	}
+
+	return method.listIterator(ctx, accountID, params, opts...)
+}
+
 // GetApplication is used to retrieve a single New Relic application.
 func (a *APM) GetApplication(applicationID int) (*Application, error) {
 	accountID := 0
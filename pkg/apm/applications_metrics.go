@@ -1,9 +1,13 @@
 package apm
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // MetricNamesParams are the request parameters for the /metrics.json endpoint.
@@ -82,51 +86,68 @@ func (m *MetricTimesliceValues) UnmarshalJSON(b []byte) error {
 //
 // https://rpm.newrelic.com/api/explore/applications/metric_names
 func (a *APM) GetMetricNames(applicationID int, params MetricNamesParams) ([]*MetricName, error) {
-	nextURL := a.config.Region().RestURL("applications", strconv.Itoa(applicationID), "metrics.json")
-	response := metricNamesResponse{}
 	metrics := []*MetricName{}
 
-	for nextURL != "" {
-		resp, err := a.client.Get(nextURL, &params, &response)
-
-		if err != nil {
-			return nil, err
-		}
-
-		metrics = append(metrics, response.Metrics...)
+	it := a.GetMetricNamesIterator(applicationID, params)
+	for it.Next() {
+		metrics = append(metrics, it.Value().(*MetricName))
+	}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return metrics, nil
 }
 
+// GetMetricNamesIterator returns an iterator over the known metrics and their value names for the given resource.
+func (a *APM) GetMetricNamesIterator(applicationID int, params MetricNamesParams, opts ...paging.Option) *paging.Iterator {
+	return a.GetMetricNamesIteratorWithContext(context.Background(), applicationID, params, opts...)
+}
+
+// GetMetricNamesIteratorWithContext returns an iterator over the known metrics and their value names for the given resource.
+func (a *APM) GetMetricNamesIteratorWithContext(ctx context.Context, applicationID int, params MetricNamesParams, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("applications", strconv.Itoa(applicationID), "metrics.json"), &params,
+		func() interface{} { return &metricNamesResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*metricNamesResponse).Metrics) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 // GetMetricData is used to retrieve a list of values for each of the requested metrics.
 //
 // https://rpm.newrelic.com/api/explore/applications/metric_data
 func (a *APM) GetMetricData(applicationID int, params MetricDataParams) ([]*MetricData, error) {
-	nextURL := a.config.Region().RestURL("applications", strconv.Itoa(applicationID), "/metrics/data.json")
-
-	response := metricDataResponse{}
 	data := []*MetricData{}
 
-	for nextURL != "" {
-		resp, err := a.client.Get(nextURL, &params, &response)
-
-		if err != nil {
-			return nil, err
-		}
-
-		data = append(data, response.MetricData.Metrics...)
+	it := a.GetMetricDataIterator(applicationID, params)
+	for it.Next() {
+		data = append(data, it.Value().(*MetricData))
+	}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return data, nil
 }
 
+// GetMetricDataIterator returns an iterator over the values of each of the requested metrics.
+func (a *APM) GetMetricDataIterator(applicationID int, params MetricDataParams, opts ...paging.Option) *paging.Iterator {
+	return a.GetMetricDataIteratorWithContext(context.Background(), applicationID, params, opts...)
+}
+
+// GetMetricDataIteratorWithContext returns an iterator over the values of each of the requested metrics.
+func (a *APM) GetMetricDataIteratorWithContext(ctx context.Context, applicationID int, params MetricDataParams, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("applications", strconv.Itoa(applicationID), "/metrics/data.json"), &params,
+		func() interface{} { return &metricDataResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*metricDataResponse).MetricData.Metrics) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 type metricNamesResponse struct {
 	Metrics []*MetricName
 }
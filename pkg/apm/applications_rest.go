@@ -1,7 +1,11 @@
 package apm
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // applicationsREST implements fetching Applications from the RESTv2 API
@@ -12,25 +16,29 @@ type applicationsREST struct {
 // list is used to retrieve New Relic applications.
 func (a *applicationsREST) list(accountID int, params *ListApplicationsParams) ([]*Application, error) {
 	apps := []*Application{}
-	nextURL := a.parent.config.Region().RestURL("applications.json")
-
-	for nextURL != "" {
-		response := applicationsResponse{}
-		resp, err := a.parent.client.Get(nextURL, &params, &response)
 
-		if err != nil {
-			return nil, err
-		}
-
-		apps = append(apps, response.Applications...)
+	it := a.listIterator(context.Background(), accountID, params)
+	for it.Next() {
+		apps = append(apps, it.Value().(*Application))
+	}
 
-		paging := a.parent.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return apps, nil
 }
 
+// listIterator returns an iterator over the New Relic applications.
+func (a *applicationsREST) listIterator(ctx context.Context, accountID int, params *ListApplicationsParams, opts ...paging.Option) *paging.Iterator {
+	fetch := a.parent.client.LinkHeaderFetcher(a.parent.pager, a.parent.config.Region().RestURL("applications.json"), &params,
+		func() interface{} { return &applicationsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*applicationsResponse).Applications) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 // find looks for an account / application name combination and returns the result
 func (a *applicationsREST) find(accountID int, name string) (*Application, error) {
 	return nil, fmt.Errorf("find application is not implemented")
@@ -1,10 +1,12 @@
 package apm
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
 	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // Deployment represents information about a New Relic application deployment.
@@ -26,30 +28,32 @@ type DeploymentLinks struct {
 // ListDeployments returns deployments for an application.
 func (a *APM) ListDeployments(applicationID int) ([]*Deployment, error) {
 	deployments := []*Deployment{}
-	nextURL := a.config.Region().RestURL("applications", strconv.Itoa(applicationID), "deployments.json")
 
-	for nextURL != "" {
-		response := deploymentsResponse{}
-		req, err := a.client.NewRequest("GET", nextURL, nil, nil, &response)
-		if err != nil {
-			return nil, err
-		}
-
-		req.SetAuthStrategy(&http.PersonalAPIKeyCapableV2Authorizer{})
+	it := a.ListDeploymentsIterator(applicationID)
+	for it.Next() {
+		deployments = append(deployments, it.Value().(*Deployment))
+	}
 
-		resp, err := a.client.Do(req)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	return deployments, nil
+}
 
-		deployments = append(deployments, response.Deployments...)
+// ListDeploymentsIterator returns an iterator over the deployments for an application.
+func (a *APM) ListDeploymentsIterator(applicationID int, opts ...paging.Option) *paging.Iterator {
+	return a.ListDeploymentsIteratorWithContext(context.Background(), applicationID, opts...)
+}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
-	}
+// ListDeploymentsIteratorWithContext returns an iterator over the deployments for an application.
+func (a *APM) ListDeploymentsIteratorWithContext(ctx context.Context, applicationID int, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("applications", strconv.Itoa(applicationID), "deployments.json"), nil,
+		func() interface{} { return &deploymentsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*deploymentsResponse).Deployments) },
+	)
 
-	return deployments, nil
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // CreateDeployment creates a deployment marker for an application.
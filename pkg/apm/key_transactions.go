@@ -1,7 +1,11 @@
 package apm
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // KeyTransaction represents information about a New Relic key transaction.
@@ -32,25 +36,36 @@ type ListKeyTransactionsParams struct {
 // ListKeyTransactions returns all key transactions for an account.
 func (a *APM) ListKeyTransactions(params *ListKeyTransactionsParams) ([]*KeyTransaction, error) {
 	results := []*KeyTransaction{}
-	nextURL := a.config.Region().RestURL("key_transactions.json")
-
-	for nextURL != "" {
-		response := keyTransactionsResponse{}
-		resp, err := a.client.Get(nextURL, &params, &response)
-
-		if err != nil {
-			return nil, err
-		}
 
-		results = append(results, response.KeyTransactions...)
+	it := a.ListKeyTransactionsIterator(params)
+	for it.Next() {
+		results = append(results, it.Value().(*KeyTransaction))
+	}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return results, nil
 }
 
+// ListKeyTransactionsIterator returns an iterator over the key transactions for an account.
+func (a *APM) ListKeyTransactionsIterator(params *ListKeyTransactionsParams, opts ...paging.Option) *paging.Iterator {
+	return a.ListKeyTransactionsIteratorWithContext(context.Background(), params, opts...)
+}
+
+// ListKeyTransactionsIteratorWithContext returns an iterator over the key transactions for an account.
+func (a *APM) ListKeyTransactionsIteratorWithContext(ctx context.Context, params *ListKeyTransactionsParams, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("key_transactions.json"), &params,
+		func() interface{} { return &keyTransactionsResponse{} },
+		func(page interface{}) []interface{} {
+			return http.Items(page.(*keyTransactionsResponse).KeyTransactions)
+		},
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 // GetKeyTransaction returns a specific key transaction by ID.
 func (a *APM) GetKeyTransaction(id int) (*KeyTransaction, error) {
 	response := keyTransactionResponse{}
@@ -1,7 +1,11 @@
 package apm
 
 import (
+	"context"
+
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // Label represents a New Relic label.
@@ -21,25 +25,34 @@ type LabelLinks struct {
 // ListLabels returns the labels within an account.
 func (a *APM) ListLabels() ([]*Label, error) {
 	labels := []*Label{}
-	nextURL := a.config.Region().RestURL("labels.json")
-
-	for nextURL != "" {
-		response := labelsResponse{}
-		resp, err := a.client.Get(nextURL, nil, &response)
-
-		if err != nil {
-			return nil, err
-		}
 
-		labels = append(labels, response.Labels...)
+	it := a.ListLabelsIterator()
+	for it.Next() {
+		labels = append(labels, it.Value().(*Label))
+	}
 
-		paging := a.pager.Parse(resp)
-		nextURL = paging.Next
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
 	return labels, nil
 }
 
+// ListLabelsIterator returns an iterator over the labels within an account.
+func (a *APM) ListLabelsIterator(opts ...paging.Option) *paging.Iterator {
+	return a.ListLabelsIteratorWithContext(context.Background(), opts...)
+}
+
+// ListLabelsIteratorWithContext returns an iterator over the labels within an account.
+func (a *APM) ListLabelsIteratorWithContext(ctx context.Context, opts ...paging.Option) *paging.Iterator {
+	fetch := a.client.LinkHeaderFetcher(a.pager, a.config.Region().RestURL("labels.json"), nil,
+		func() interface{} { return &labelsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*labelsResponse).Labels) },
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
 // GetLabel gets a label by key. A label's key
 // is a string hash formatted as <Category>:<Name>.
 func (a *APM) GetLabel(key string) (*Label, error) {
@@ -1,7 +1,11 @@
 // Code generated by tutone: DO NOT EDIT
 package cloud
 
-import "github.com/newrelic/newrelic-client-go/pkg/errors"
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+)
 
 // Create or modify a cloud integration.
 //
@@ -11,6 +15,18 @@ func (a *Cloud) CloudConfigureIntegration(
 	accountID int,
 	integrations CloudIntegrationsInput,
 ) (*CloudConfigureIntegrationPayload, error) {
+	return a.CloudConfigureIntegrationWithContext(context.Background(), accountID, integrations)
+}
+
+// Create or modify a cloud integration.
+//
+// For details and mutation examples visit
+// [our docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/manage-your-aws-azure-google-cloud-integrations-graphql-api).
+func (a *Cloud) CloudConfigureIntegrationWithContext(
+	ctx context.Context,
+	accountID int,
+	integrations CloudIntegrationsInput,
+) (*CloudConfigureIntegrationPayload, error) {
 
 	resp := CloudConfigureIntegrationQueryResponse{}
 	vars := map[string]interface{}{
@@ -18,7 +34,7 @@ func (a *Cloud) CloudConfigureIntegration(
 		"integrations": integrations,
 	}
 
-	if err := a.client.NerdGraphQuery(CloudConfigureIntegrationMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, CloudConfigureIntegrationMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -730,6 +746,18 @@ func (a *Cloud) CloudDisableIntegration(
 	accountID int,
 	integrations CloudDisableIntegrationsInput,
 ) (*CloudDisableIntegrationPayload, error) {
+	return a.CloudDisableIntegrationWithContext(context.Background(), accountID, integrations)
+}
+
+// Disable a cloud integration. Stops collecting data for the specified integration.
+//
+// For details and mutation examples visit
+// [our docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/manage-your-aws-azure-google-cloud-integrations-graphql-api).
+func (a *Cloud) CloudDisableIntegrationWithContext(
+	ctx context.Context,
+	accountID int,
+	integrations CloudDisableIntegrationsInput,
+) (*CloudDisableIntegrationPayload, error) {
 
 	resp := CloudDisableIntegrationQueryResponse{}
 	vars := map[string]interface{}{
@@ -737,7 +765,7 @@ func (a *Cloud) CloudDisableIntegration(
 		"integrations": integrations,
 	}
 
-	if err := a.client.NerdGraphQuery(CloudDisableIntegrationMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, CloudDisableIntegrationMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -1449,6 +1477,18 @@ func (a *Cloud) CloudLinkAccount(
 	accountID int,
 	accounts CloudLinkCloudAccountsInput,
 ) (*CloudLinkAccountPayload, error) {
+	return a.CloudLinkAccountWithContext(context.Background(), accountID, accounts)
+}
+
+// Link a cloud provider account to a New Relic Account.
+//
+// For details and mutation examples visit
+// [our docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/manage-your-aws-azure-google-cloud-integrations-graphql-api).
+func (a *Cloud) CloudLinkAccountWithContext(
+	ctx context.Context,
+	accountID int,
+	accounts CloudLinkCloudAccountsInput,
+) (*CloudLinkAccountPayload, error) {
 
 	resp := CloudLinkAccountQueryResponse{}
 	vars := map[string]interface{}{
@@ -1456,7 +1496,7 @@ func (a *Cloud) CloudLinkAccount(
 		"accounts":  accounts,
 	}
 
-	if err := a.client.NerdGraphQuery(CloudLinkAccountMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, CloudLinkAccountMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -1501,6 +1541,18 @@ func (a *Cloud) CloudRenameAccount(
 	accountID int,
 	accounts []CloudRenameAccountsInput,
 ) (*CloudRenameAccountPayload, error) {
+	return a.CloudRenameAccountWithContext(context.Background(), accountID, accounts)
+}
+
+// Rename one or more linked cloud provider accounts.
+//
+// For details and mutation examples visit
+// [our docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/manage-your-aws-azure-google-cloud-integrations-graphql-api).
+func (a *Cloud) CloudRenameAccountWithContext(
+	ctx context.Context,
+	accountID int,
+	accounts []CloudRenameAccountsInput,
+) (*CloudRenameAccountPayload, error) {
 
 	resp := CloudRenameAccountQueryResponse{}
 	vars := map[string]interface{}{
@@ -1508,7 +1560,7 @@ func (a *Cloud) CloudRenameAccount(
 		"accounts":  accounts,
 	}
 
-	if err := a.client.NerdGraphQuery(CloudRenameAccountMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, CloudRenameAccountMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -1554,6 +1606,19 @@ func (a *Cloud) CloudUnlinkAccount(
 	accountID int,
 	accounts []CloudUnlinkAccountsInput,
 ) (*CloudUnlinkAccountPayload, error) {
+	return a.CloudUnlinkAccountWithContext(context.Background(), accountID, accounts)
+}
+
+// Unlink one or more cloud provider accounts.
+// Stops collecting data for all the associated integrations.
+//
+// For details and mutation examples visit
+// [our docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/manage-your-aws-azure-google-cloud-integrations-graphql-api).
+func (a *Cloud) CloudUnlinkAccountWithContext(
+	ctx context.Context,
+	accountID int,
+	accounts []CloudUnlinkAccountsInput,
+) (*CloudUnlinkAccountPayload, error) {
 
 	resp := CloudUnlinkAccountQueryResponse{}
 	vars := map[string]interface{}{
@@ -1561,7 +1626,7 @@ func (a *Cloud) CloudUnlinkAccount(
 		"accounts":  accounts,
 	}
 
-	if err := a.client.NerdGraphQuery(CloudUnlinkAccountMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, CloudUnlinkAccountMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -1602,13 +1667,21 @@ const CloudUnlinkAccountMutation = `mutation(
 func (a *Cloud) GetLinkedAccounts(
 	provider string,
 ) (*[]CloudLinkedAccount, error) {
+	return a.GetLinkedAccountsWithContext(context.Background(), provider)
+}
+
+// Get all linked cloud provider accounts scoped to the Actor.
+func (a *Cloud) GetLinkedAccountsWithContext(
+	ctx context.Context,
+	provider string,
+) (*[]CloudLinkedAccount, error) {
 
 	resp := linkedAccountsResponse{}
 	vars := map[string]interface{}{
 		"provider": provider,
 	}
 
-	if err := a.client.NerdGraphQuery(getLinkedAccountsQuery, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, getLinkedAccountsQuery, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -2,6 +2,7 @@
 package config
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -28,12 +29,50 @@ type Config struct {
 	// InsightsInsertKey to send custom events to Insights
 	InsightsInsertKey string
 
+	// AccountID is the default account of account scoped operations, as
+	// loaded from the environment or a profile.
+	AccountID int
+
 	// region of the New Relic platform to use
 	region *region.Region
 
 	// Timeout is the client timeout for HTTP requests.
 	Timeout *time.Duration
 
+	// RetryMax is the maximum number of times a failed request is retried.
+	RetryMax *int
+
+	// RetryWaitMin is the minimum wait between retries.
+	RetryWaitMin *time.Duration
+
+	// RetryWaitMax is the maximum wait between retries, including the
+	// waits asked for by rate limited responses.
+	RetryWaitMax *time.Duration
+
+	// RetryJitter is the fraction, between 0 and 1, by which the wait
+	// between retries is randomly reduced.
+	RetryJitter float64
+
+	// RetryNonIdempotent allows retrying requests that may create resources,
+	// such as POST requests, even when the server may have processed them.
+	RetryNonIdempotent bool
+
+	// RetryPolicy allows customization of the decision to retry a request.
+	RetryPolicy RetryPolicy
+
+	// NerdGraphPartialData keeps the data of NerdGraph responses holding
+	// errors, returning it along with an *errors.PartialResult instead of
+	// failing the whole request.
+	NerdGraphPartialData bool
+
+	// SkipInputValidation sends the inputs of mutations, such as alert
+	// conditions, without checking them first, leaving their validation to
+	// the APIs.
+	SkipInputValidation bool
+
+	// Middleware intercepts every request made by the API clients.
+	Middleware []Middleware
+
 	// HTTPTransport allows customization of the client's underlying transport.
 	HTTPTransport http.RoundTripper
 
@@ -57,6 +96,11 @@ type Config struct {
 	Logger logging.Logger
 }
 
+// RetryPolicy decides whether a failed request is retried.  It is given
+// either the response or the error of the last attempt, along with the
+// decision of the default policy, which it may override.
+type RetryPolicy func(ctx context.Context, resp *http.Response, err error, retry bool) (bool, error)
+
 // New creates a default configuration and returns it
 func New() Config {
 	reg, _ := region.Get(region.Default)
@@ -79,17 +123,31 @@ func (c *Config) Region() *region.Region {
 	return c.region
 }
 
-// SetRegion configures the region
+// SetRegion configures the region, keeping a copy of it so that changes to
+// the region given do not affect the configuration.
 func (c *Config) SetRegion(reg *region.Region) error {
 	if reg == nil {
 		return region.ErrorNil()
 	}
 
-	c.region = reg
+	r := *reg
+	c.region = &r
 
 	return nil
 }
 
+// SetEndpoints overrides the base URLs of the region with the ones given
+// which are not empty.  The region is replaced with an updated copy, leaving
+// the clients already configured with it untouched.
+func (c *Config) SetEndpoints(endpoints region.Endpoints) error {
+	reg, err := c.Region().WithEndpoints(endpoints)
+	if err != nil {
+		return err
+	}
+
+	return c.SetRegion(reg)
+}
+
 // GetLogger returns a logger instance based on the config values.
 func (c *Config) GetLogger() logging.Logger {
 	if c.Logger != nil {
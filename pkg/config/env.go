@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by LoadEnv.
+const (
+	EnvAPIKey                = "NEW_RELIC_API_KEY"
+	EnvAdminAPIKey           = "NEW_RELIC_ADMIN_API_KEY"
+	EnvInsightsInsertKey     = "NEW_RELIC_INSIGHTS_INSERT_KEY"
+	EnvLicenseKey            = "NEW_RELIC_LICENSE_KEY"
+	EnvAccountID             = "NEW_RELIC_ACCOUNT_ID"
+	EnvRegion                = "NEW_RELIC_REGION"
+	EnvLogLevel              = "NEW_RELIC_LOG_LEVEL"
+	EnvNerdGraphBaseURL      = "NEW_RELIC_NERDGRAPH_URL"
+	EnvRestBaseURL           = "NEW_RELIC_REST_URL"
+	EnvInfrastructureBaseURL = "NEW_RELIC_INFRASTRUCTURE_URL"
+	EnvSyntheticsBaseURL     = "NEW_RELIC_SYNTHETICS_URL"
+	EnvInsightsBaseURL       = "NEW_RELIC_INSIGHTS_URL"
+	EnvLogsBaseURL           = "NEW_RELIC_LOGS_URL"
+	EnvMetricsBaseURL        = "NEW_RELIC_METRICS_URL"
+	EnvTracesBaseURL         = "NEW_RELIC_TRACES_URL"
+	EnvProfile               = "NEW_RELIC_PROFILE"
+)
+
+// LoadEnv sets the values of the configuration found in the environment,
+// leaving the others as is.  The region is set before the base URLs, which
+// override the ones of the region.
+func (c *Config) LoadEnv() error {
+	return c.loadEnv(os.LookupEnv)
+}
+
+func (c *Config) loadEnv(lookup func(string) (string, bool)) error {
+	get := func(name string) string {
+		v, _ := lookup(name)
+		return v
+	}
+
+	p := Profile{
+		APIKey:            get(EnvAPIKey),
+		AdminAPIKey:       get(EnvAdminAPIKey),
+		InsightsInsertKey: get(EnvInsightsInsertKey),
+		LicenseKey:        get(EnvLicenseKey),
+		Region:            get(EnvRegion),
+		LogLevel:          get(EnvLogLevel),
+		BaseURLs: BaseURLs{
+			NerdGraph:      get(EnvNerdGraphBaseURL),
+			Rest:           get(EnvRestBaseURL),
+			Infrastructure: get(EnvInfrastructureBaseURL),
+			Synthetics:     get(EnvSyntheticsBaseURL),
+			Insights:       get(EnvInsightsBaseURL),
+			Logs:           get(EnvLogsBaseURL),
+			Metrics:        get(EnvMetricsBaseURL),
+			Traces:         get(EnvTracesBaseURL),
+		},
+	}
+
+	if v := get(EnvAccountID); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %s", EnvAccountID, err)
+		}
+
+		p.AccountID = id
+	}
+
+	return p.Apply(c)
+}
+
+// BaseURLs overrides the base URLs of the New Relic APIs, the empty ones
+// being left as is.  It converts to region.Endpoints.
+type BaseURLs struct {
+	Infrastructure string `json:"infrastructureBaseURL,omitempty"`
+	Insights       string `json:"insightsBaseURL,omitempty"`
+	Logs           string `json:"logsBaseURL,omitempty"`
+	Metrics        string `json:"metricsBaseURL,omitempty"`
+	NerdGraph      string `json:"nerdGraphBaseURL,omitempty"`
+	Rest           string `json:"restBaseURL,omitempty"`
+	Synthetics     string `json:"syntheticsBaseURL,omitempty"`
+	Traces         string `json:"tracesBaseURL,omitempty"`
+}
@@ -0,0 +1,55 @@
+// +build unit
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+func testLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Parallel()
+
+	cfg := New()
+	cfg.LicenseKey = "licenseKey"
+
+	err := cfg.loadEnv(testLookup(map[string]string{
+		EnvAPIKey:           "apiKey",
+		EnvAccountID:        "12345",
+		EnvRegion:           "eu",
+		EnvLogLevel:         "debug",
+		EnvNerdGraphBaseURL: "http://localhost/graphql",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "apiKey", cfg.PersonalAPIKey)
+	assert.Equal(t, 12345, cfg.AccountID)
+	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, "EU", cfg.Region().String())
+	assert.Equal(t, "http://localhost/graphql", cfg.Region().NerdGraphURL())
+
+	// Values absent from the environment are left as is
+	assert.Equal(t, "licenseKey", cfg.LicenseKey)
+
+	reg, _ := region.Get(region.EU)
+	assert.Equal(t, reg.RestURL(), cfg.Region().RestURL())
+}
+
+func TestLoadEnv_invalid(t *testing.T) {
+	t.Parallel()
+
+	cfg := New()
+	assert.Error(t, cfg.loadEnv(testLookup(map[string]string{EnvAccountID: "invalid"})))
+	assert.Error(t, cfg.loadEnv(testLookup(map[string]string{EnvRegion: "invalid"})))
+}
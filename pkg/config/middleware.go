@@ -0,0 +1,76 @@
+package config
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+// Middleware intercepts every request made by the API clients, for instance
+// to add tracing headers or to record metrics.  When several are configured,
+// BeforeRequest is called in the order they were configured, AfterResponse
+// and OnError in the reverse order.
+type Middleware interface {
+	// BeforeRequest is called once per request, before it is first sent.
+	// Changes made to the request apply to every retry.  Returning an
+	// error aborts the request.
+	BeforeRequest(req *http.Request) error
+
+	// AfterResponse is called with the last response received for a
+	// request, whether or not the request succeeded.
+	AfterResponse(resp *Response)
+
+	// OnError is called with the error returned for a failed request.
+	OnError(req *http.Request, err error)
+}
+
+// Response is the outcome of a request, as seen by a Middleware.
+type Response struct {
+	// Request is the request that was sent.
+	Request *http.Request
+
+	// Response is the last response received.  Its body has already
+	// been read and closed.
+	Response *http.Response
+
+	// Body is the body of the response.
+	Body []byte
+
+	// GraphQLErrors are the errors of a NerdGraph response, if any.
+	GraphQLErrors []errors.GraphQLError
+
+	// Duration is the time taken by the request, including retries.
+	Duration time.Duration
+}
+
+// MiddlewareFuncs adapts ordinary functions to the Middleware interface.
+// Functions left nil are skipped.
+type MiddlewareFuncs struct {
+	Before func(req *http.Request) error
+	After  func(resp *Response)
+	Error  func(req *http.Request, err error)
+}
+
+// BeforeRequest calls m.Before.
+func (m MiddlewareFuncs) BeforeRequest(req *http.Request) error {
+	if m.Before == nil {
+		return nil
+	}
+
+	return m.Before(req)
+}
+
+// AfterResponse calls m.After.
+func (m MiddlewareFuncs) AfterResponse(resp *Response) {
+	if m.After != nil {
+		m.After(resp)
+	}
+}
+
+// OnError calls m.Error.
+func (m MiddlewareFuncs) OnError(req *http.Request, err error) {
+	if m.Error != nil {
+		m.Error(req, err)
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+const (
+	// DefaultProfileName is the name of the profile used when none is given
+	// nor set as default.
+	DefaultProfileName = "default"
+
+	credentialsFile    = "credentials.json"
+	defaultProfileFile = "default-profile.json"
+	configFile         = "config.json"
+	globalScope        = "*"
+)
+
+// Profile holds the settings of a profile of the New Relic CLI, as stored in
+// its credentials.json file.  The base URLs are an extension to the format.
+type Profile struct {
+	APIKey            string `json:"apiKey,omitempty"`
+	AdminAPIKey       string `json:"adminApiKey,omitempty"`
+	InsightsInsertKey string `json:"insightsInsertKey,omitempty"`
+	LicenseKey        string `json:"licenseKey,omitempty"`
+	AccountID         int    `json:"accountID,omitempty"`
+	Region            string `json:"region,omitempty"`
+	LogLevel          string `json:"logLevel,omitempty"`
+
+	BaseURLs
+}
+
+// Apply sets the values of the profile which are not empty in the given
+// configuration.  The region is set before the base URLs, which override the
+// ones of the region.
+func (p *Profile) Apply(cfg *Config) error {
+	if p.APIKey != "" {
+		cfg.PersonalAPIKey = p.APIKey
+	}
+
+	if p.AdminAPIKey != "" {
+		cfg.AdminAPIKey = p.AdminAPIKey
+	}
+
+	if p.InsightsInsertKey != "" {
+		cfg.InsightsInsertKey = p.InsightsInsertKey
+	}
+
+	if p.LicenseKey != "" {
+		cfg.LicenseKey = p.LicenseKey
+	}
+
+	if p.AccountID != 0 {
+		cfg.AccountID = p.AccountID
+	}
+
+	if p.LogLevel != "" {
+		cfg.LogLevel = p.LogLevel
+	}
+
+	if p.Region != "" {
+		regName, err := region.Parse(p.Region)
+		if err != nil {
+			return err
+		}
+
+		reg, err := region.Get(regName)
+		if err != nil {
+			return err
+		}
+
+		if err := cfg.SetRegion(reg); err != nil {
+			return err
+		}
+	}
+
+	return cfg.SetEndpoints(region.Endpoints(p.BaseURLs))
+}
+
+// DefaultProfileDir returns the directory holding the profiles of the New
+// Relic CLI, .newrelic in the home directory of the user.
+func DefaultProfileDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".newrelic"), nil
+}
+
+// LoadProfile reads the profile of the given name from the credentials.json
+// file of the given directory, laid out as by the New Relic CLI.  The name
+// defaults to the NEW_RELIC_PROFILE environment variable, then to the
+// profile named in default-profile.json, then to "default".  The log level
+// is read from config.json, where the settings of the profile take
+// precedence over the global ones.
+func LoadProfile(dir string, name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+
+	if name == "" {
+		name = DefaultProfileName
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, defaultProfileFile))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		if err == nil {
+			var defaultName string
+			if err := json.Unmarshal(data, &defaultName); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %s", defaultProfileFile, err)
+			}
+
+			if defaultName != "" {
+				name = defaultName
+			}
+		}
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, credentialsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := map[string]*Profile{}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", credentialsFile, err)
+	}
+
+	p, ok := profiles[name]
+	if !ok || p == nil {
+		return nil, fmt.Errorf("profile %q not found in %s", name, filepath.Join(dir, credentialsFile))
+	}
+
+	if p.LogLevel == "" {
+		logLevel, err := readLogLevel(dir, name)
+		if err != nil {
+			return nil, err
+		}
+
+		p.LogLevel = logLevel
+	}
+
+	return p, nil
+}
+
+// readLogLevel reads the log level of a profile from config.json, if any.
+func readLogLevel(dir string, name string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, configFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	scopes := map[string]map[string]interface{}{}
+	if err := json.Unmarshal(data, &scopes); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %s", configFile, err)
+	}
+
+	logLevel := ""
+	for _, scope := range []string{globalScope, name} {
+		for k, v := range scopes[scope] {
+			if s, ok := v.(string); ok && s != "" && strings.EqualFold(k, "loglevel") {
+				logLevel = s
+			}
+		}
+	}
+
+	return logLevel, nil
+}
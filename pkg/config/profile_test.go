@@ -0,0 +1,98 @@
+// +build unit
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCredentials = `{
+	"default": {
+		"apiKey": "defaultAPIKey",
+		"region": "US",
+		"accountID": 1
+	},
+	"eu": {
+		"apiKey": "euAPIKey",
+		"region": "EU",
+		"accountID": 2,
+		"licenseKey": "euLicenseKey",
+		"nerdGraphBaseURL": "http://localhost/graphql"
+	}
+}`
+
+func writeTestProfiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	return dir
+}
+
+func TestLoadProfile(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestProfiles(t, map[string]string{
+		credentialsFile: testCredentials,
+		configFile:      `{"*":{"loglevel":"info"},"eu":{"loglevel":"trace"}}`,
+	})
+
+	p, err := LoadProfile(dir, "eu")
+	require.NoError(t, err)
+	assert.Equal(t, "euAPIKey", p.APIKey)
+	assert.Equal(t, 2, p.AccountID)
+	assert.Equal(t, "trace", p.LogLevel)
+
+	cfg := New()
+	cfg.InsightsInsertKey = "insightsInsertKey"
+	require.NoError(t, p.Apply(&cfg))
+
+	assert.Equal(t, "euAPIKey", cfg.PersonalAPIKey)
+	assert.Equal(t, "euLicenseKey", cfg.LicenseKey)
+	assert.Equal(t, "insightsInsertKey", cfg.InsightsInsertKey)
+	assert.Equal(t, 2, cfg.AccountID)
+	assert.Equal(t, "trace", cfg.LogLevel)
+	assert.Equal(t, "EU", cfg.Region().String())
+	assert.Equal(t, "http://localhost/graphql", cfg.Region().NerdGraphURL())
+
+	_, err = LoadProfile(dir, "missing")
+	assert.Error(t, err)
+}
+
+func TestLoadProfile_default(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestProfiles(t, map[string]string{
+		credentialsFile: testCredentials,
+		configFile:      `{"*":{"loglevel":"debug"}}`,
+	})
+
+	p, err := LoadProfile(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, "defaultAPIKey", p.APIKey)
+	assert.Equal(t, "debug", p.LogLevel)
+
+	dir = writeTestProfiles(t, map[string]string{
+		credentialsFile:    testCredentials,
+		defaultProfileFile: `"eu"`,
+	})
+
+	p, err = LoadProfile(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, "euAPIKey", p.APIKey)
+	assert.Equal(t, "", p.LogLevel)
+}
+
+func TestLoadProfile_missingCredentials(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadProfile(t.TempDir(), "default")
+	assert.Error(t, err)
+}
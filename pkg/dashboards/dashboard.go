@@ -1,11 +1,14 @@
 package dashboards
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/pkg/entities"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 // ListDashboardsParams represents a set of filters to be
@@ -25,23 +28,47 @@ type ListDashboardsParams struct {
 // ListDashboards is used to retrieve New Relic dashboards.
 func (d *Dashboards) ListDashboards(params *ListDashboardsParams) ([]*Dashboard, error) {
 	dashboard := []*Dashboard{}
-	nextURL := d.config.Region().RestURL("dashboards.json")
 
-	for nextURL != "" {
-		response := dashboardsResponse{}
-		resp, err := d.client.Get(nextURL, &params, &response)
+	it := d.ListDashboardsIterator(params)
+	for it.Next() {
+		dashboard = append(dashboard, it.Value().(*Dashboard))
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
 
-		dashboard = append(dashboard, response.Dashboards...)
+// ListDashboardsIterator returns an iterator over the New Relic dashboards.
+// The page size requested takes precedence over params.PerPage.
+func (d *Dashboards) ListDashboardsIterator(params *ListDashboardsParams, opts ...paging.Option) *paging.Iterator {
+	return d.ListDashboardsIteratorWithContext(context.Background(), params, opts...)
+}
 
-		paging := d.pager.Parse(resp)
-		nextURL = paging.Next
+// ListDashboardsIteratorWithContext returns an iterator over the New Relic dashboards.
+// The page size requested takes precedence over params.PerPage.
+func (d *Dashboards) ListDashboardsIteratorWithContext(ctx context.Context, params *ListDashboardsParams, opts ...paging.Option) *paging.Iterator {
+	queryParams := ListDashboardsParams{}
+	if params != nil {
+		queryParams = *params
 	}
 
-	return dashboard, nil
+	fetchPage := d.client.LinkHeaderFetcher(d.pager, d.config.Region().RestURL("dashboards.json"), &queryParams,
+		func() interface{} { return &dashboardsResponse{} },
+		func(page interface{}) []interface{} { return http.Items(page.(*dashboardsResponse).Dashboards) },
+	)
+
+	fetch := func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error) {
+		if pageSize > 0 {
+			queryParams.PerPage = pageSize
+		}
+
+		return fetchPage(ctx, cursor, pageSize)
+	}
+
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // GetDashboardEntity is used to retrieve a single New Relic One Dashboard
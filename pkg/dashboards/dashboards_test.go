@@ -3,6 +3,8 @@
 package dashboards
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -394,3 +396,20 @@ func TestDeleteDashboard(t *testing.T) {
 	assert.NotNil(t, actual)
 	assert.Equal(t, &testDashboard, actual)
 }
+
+func TestDashboardDeleteWithContext_canceled(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	dashboards := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := dashboards.DashboardDeleteWithContext(ctx, "MTIzNDU2fFZJWnxEQVNIQk9BUkR8MTIzNDU2Nzg")
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, called)
+}
@@ -1,7 +1,11 @@
 // Code generated by tutone: DO NOT EDIT
 package entities
 
-import "github.com/newrelic/newrelic-client-go/pkg/errors"
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+)
 
 // Adds the provided tags to your specified entity, without deleting existing ones.
 //  The maximum number of tag-values per entity is 100; if the sum of existing and new tag-values if over the limit this mutation will fail.
@@ -11,6 +15,18 @@ func (a *Entities) TaggingAddTagsToEntity(
 	gUID EntityGUID,
 	tags []TaggingTagInput,
 ) (*TaggingMutationResult, error) {
+	return a.TaggingAddTagsToEntityWithContext(context.Background(), gUID, tags)
+}
+
+// Adds the provided tags to your specified entity, without deleting existing ones.
+//  The maximum number of tag-values per entity is 100; if the sum of existing and new tag-values if over the limit this mutation will fail.
+//
+//  For details and mutation examples, visit [our docs](https://docs.newrelic.com/docs/apis/nerdgraph/examples/nerdgraph-tagging-api-tutorial).
+func (a *Entities) TaggingAddTagsToEntityWithContext(
+	ctx context.Context,
+	gUID EntityGUID,
+	tags []TaggingTagInput,
+) (*TaggingMutationResult, error) {
 
 	resp := TaggingAddTagsToEntityQueryResponse{}
 	vars := map[string]interface{}{
@@ -18,7 +34,7 @@ func (a *Entities) TaggingAddTagsToEntity(
 		"tags": tags,
 	}
 
-	if err := a.client.NerdGraphQuery(TaggingAddTagsToEntityMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, TaggingAddTagsToEntityMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -49,6 +65,17 @@ func (a *Entities) TaggingDeleteTagFromEntity(
 	gUID EntityGUID,
 	tagKeys []string,
 ) (*TaggingMutationResult, error) {
+	return a.TaggingDeleteTagFromEntityWithContext(context.Background(), gUID, tagKeys)
+}
+
+// Delete specific tag keys from the entity.
+//
+//  For details and mutation examples, visit [our docs](https://docs.newrelic.com/docs/apis/nerdgraph/examples/nerdgraph-tagging-api-tutorial).
+func (a *Entities) TaggingDeleteTagFromEntityWithContext(
+	ctx context.Context,
+	gUID EntityGUID,
+	tagKeys []string,
+) (*TaggingMutationResult, error) {
 
 	resp := TaggingDeleteTagFromEntityQueryResponse{}
 	vars := map[string]interface{}{
@@ -56,7 +83,7 @@ func (a *Entities) TaggingDeleteTagFromEntity(
 		"tagKeys": tagKeys,
 	}
 
-	if err := a.client.NerdGraphQuery(TaggingDeleteTagFromEntityMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, TaggingDeleteTagFromEntityMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -87,6 +114,17 @@ func (a *Entities) TaggingDeleteTagValuesFromEntity(
 	gUID EntityGUID,
 	tagValues []TaggingTagValueInput,
 ) (*TaggingMutationResult, error) {
+	return a.TaggingDeleteTagValuesFromEntityWithContext(context.Background(), gUID, tagValues)
+}
+
+// Delete specific tag key-values from the entity.
+//
+//  For details and mutation examples, visit [our docs](https://docs.newrelic.com/docs/apis/nerdgraph/examples/nerdgraph-tagging-api-tutorial).
+func (a *Entities) TaggingDeleteTagValuesFromEntityWithContext(
+	ctx context.Context,
+	gUID EntityGUID,
+	tagValues []TaggingTagValueInput,
+) (*TaggingMutationResult, error) {
 
 	resp := TaggingDeleteTagValuesFromEntityQueryResponse{}
 	vars := map[string]interface{}{
@@ -94,7 +132,7 @@ func (a *Entities) TaggingDeleteTagValuesFromEntity(
 		"tagValues": tagValues,
 	}
 
-	if err := a.client.NerdGraphQuery(TaggingDeleteTagValuesFromEntityMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, TaggingDeleteTagValuesFromEntityMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -126,6 +164,18 @@ func (a *Entities) TaggingReplaceTagsOnEntity(
 	gUID EntityGUID,
 	tags []TaggingTagInput,
 ) (*TaggingMutationResult, error) {
+	return a.TaggingReplaceTagsOnEntityWithContext(context.Background(), gUID, tags)
+}
+
+// Replaces the entity's entire set of tags with the provided tag set.
+//  The maximum number of tag-values per entity is 100; if more than 100 tag-values are provided this mutation will fail.
+//
+//  For details and mutation examples, visit [our docs](https://docs.newrelic.com/docs/apis/nerdgraph/examples/nerdgraph-tagging-api-tutorial).
+func (a *Entities) TaggingReplaceTagsOnEntityWithContext(
+	ctx context.Context,
+	gUID EntityGUID,
+	tags []TaggingTagInput,
+) (*TaggingMutationResult, error) {
 
 	resp := TaggingReplaceTagsOnEntityQueryResponse{}
 	vars := map[string]interface{}{
@@ -133,7 +183,7 @@ func (a *Entities) TaggingReplaceTagsOnEntity(
 		"tags": tags,
 	}
 
-	if err := a.client.NerdGraphQuery(TaggingReplaceTagsOnEntityMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, TaggingReplaceTagsOnEntityMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -165,13 +215,25 @@ const TaggingReplaceTagsOnEntityMutation = `mutation(
 func (a *Entities) GetEntities(
 	gUIDs []EntityGUID,
 ) (*[]EntityInterface, error) {
+	return a.GetEntitiesWithContext(context.Background(), gUIDs)
+}
+
+// Fetch a list of entities.
+//
+// You can fetch a max of 25 entities in one query.
+//
+// For more details on entities, visit our [entity docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/use-new-relic-graphql-api-query-entities).
+func (a *Entities) GetEntitiesWithContext(
+	ctx context.Context,
+	gUIDs []EntityGUID,
+) (*[]EntityInterface, error) {
 
 	resp := entitiesResponse{}
 	vars := map[string]interface{}{
 		"guids": gUIDs,
 	}
 
-	if err := a.client.NerdGraphQuery(getEntitiesQuery, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, getEntitiesQuery, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -749,13 +811,23 @@ const getEntitiesQuery = `query(
 func (a *Entities) GetEntity(
 	gUID EntityGUID,
 ) (*EntityInterface, error) {
+	return a.GetEntityWithContext(context.Background(), gUID)
+}
+
+// Fetch a single entity.
+//
+// For more details on entities, visit our [entity docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/use-new-relic-graphql-api-query-entities).
+func (a *Entities) GetEntityWithContext(
+	ctx context.Context,
+	gUID EntityGUID,
+) (*EntityInterface, error) {
 
 	resp := entityResponse{}
 	vars := map[string]interface{}{
 		"guid": gUID,
 	}
 
-	if err := a.client.NerdGraphQuery(getEntityQuery, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, getEntityQuery, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -1336,6 +1408,23 @@ func (a *Entities) GetEntitySearch(
 	queryBuilder EntitySearchQueryBuilder,
 	sortBy []EntitySearchSortCriteria,
 ) (*EntitySearch, error) {
+	return a.GetEntitySearchWithContext(context.Background(), options, query, queryBuilder, sortBy)
+}
+
+// Search for entities using a custom query.
+//
+// For more details on how to create a custom query
+// and what entity data you can request, visit our
+// [entity docs](https://docs.newrelic.com/docs/apis/graphql-api/tutorials/use-new-relic-graphql-api-query-entities).
+//
+// Note: you must supply either a `query` OR a `queryBuilder` argument, not both.
+func (a *Entities) GetEntitySearchWithContext(
+	ctx context.Context,
+	options EntitySearchOptions,
+	query string,
+	queryBuilder EntitySearchQueryBuilder,
+	sortBy []EntitySearchSortCriteria,
+) (*EntitySearch, error) {
 
 	resp := entitySearchResponse{}
 	vars := map[string]interface{}{
@@ -1345,7 +1434,7 @@ func (a *Entities) GetEntitySearch(
 		"sortBy":       sortBy,
 	}
 
-	if err := a.client.NerdGraphQuery(getEntitySearchQuery, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, getEntitySearchQuery, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -75,6 +75,30 @@ func TestIntegrationSearchEntities_domain(t *testing.T) {
 	}
 }
 
+func TestIntegrationSearchEntities_paginated(t *testing.T) {
+	t.Parallel()
+
+	client := newIntegrationTestClient(t)
+
+	query := "domain = 'APM' AND type = 'APPLICATION'"
+	sortBy := []EntitySearchSortCriteria{EntitySearchSortCriteriaTypes.NAME}
+
+	entities, err := client.SearchEntities(EntitySearchOptions{}, query, EntitySearchQueryBuilder{}, sortBy)
+
+	require.NoError(t, err)
+	require.Greater(t, len(entities), 0)
+
+	// Streaming the results page by page returns the same entities
+	count := 0
+	it := client.SearchEntitiesIterator(EntitySearchOptions{}, query, EntitySearchQueryBuilder{}, sortBy)
+	for it.NextPage() {
+		count += len(it.Page())
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, len(entities), count)
+}
+
 func TestIntegrationSearchEntitiesByTags(t *testing.T) {
 	t.Parallel()
 
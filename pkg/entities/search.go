@@ -0,0 +1,220 @@
+package entities
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
+)
+
+// SearchEntities searches for entities using either a custom query or a
+// query builder, following the cursor of the results until every matching
+// entity has been fetched.
+//
+// Unlike GetEntitySearch, the query, sort criteria and options given are all
+// sent.  Note: you must supply either a `query` OR a `queryBuilder` argument,
+// not both, and results sorted by relevance can't be paginated.
+func (a *Entities) SearchEntities(
+	options EntitySearchOptions,
+	query string,
+	queryBuilder EntitySearchQueryBuilder,
+	sortBy []EntitySearchSortCriteria,
+) ([]EntityOutlineInterface, error) {
+	return a.SearchEntitiesWithContext(context.Background(), options, query, queryBuilder, sortBy)
+}
+
+// SearchEntitiesWithContext searches for entities using either a custom query
+// or a query builder, following the cursor of the results until every matching
+// entity has been fetched.
+func (a *Entities) SearchEntitiesWithContext(
+	ctx context.Context,
+	options EntitySearchOptions,
+	query string,
+	queryBuilder EntitySearchQueryBuilder,
+	sortBy []EntitySearchSortCriteria,
+) ([]EntityOutlineInterface, error) {
+	entities := []EntityOutlineInterface{}
+
+	it := a.SearchEntitiesIteratorWithContext(ctx, options, query, queryBuilder, sortBy)
+	for it.Next() {
+		entities = append(entities, it.Value().(EntityOutlineInterface))
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
+}
+
+// SearchEntitiesIterator returns an iterator over the entities matching either
+// a custom query or a query builder, fetching a page of results at a time.
+// The size of the pages is decided by the API.
+func (a *Entities) SearchEntitiesIterator(
+	options EntitySearchOptions,
+	query string,
+	queryBuilder EntitySearchQueryBuilder,
+	sortBy []EntitySearchSortCriteria,
+	opts ...paging.Option,
+) *paging.Iterator {
+	return a.SearchEntitiesIteratorWithContext(context.Background(), options, query, queryBuilder, sortBy, opts...)
+}
+
+// SearchEntitiesIteratorWithContext returns an iterator over the entities
+// matching either a custom query or a query builder, fetching a page of
+// results at a time.  The size of the pages is decided by the API.
+func (a *Entities) SearchEntitiesIteratorWithContext(
+	ctx context.Context,
+	options EntitySearchOptions,
+	query string,
+	queryBuilder EntitySearchQueryBuilder,
+	sortBy []EntitySearchSortCriteria,
+	opts ...paging.Option,
+) *paging.Iterator {
+	vars := map[string]interface{}{
+		"options": options,
+	}
+
+	// Only send the arguments given, as the API refuses a search with both
+	// a query and a query builder.
+	if query != "" {
+		vars["query"] = query
+	}
+
+	if !reflect.DeepEqual(queryBuilder, EntitySearchQueryBuilder{}) {
+		vars["queryBuilder"] = queryBuilder
+	}
+
+	if len(sortBy) > 0 {
+		vars["sortBy"] = sortBy
+	}
+
+	fetch := a.client.NerdGraphCursorFetcher(searchEntitiesQuery, vars,
+		func() interface{} { return &entitySearchResponse{} },
+		func(resp interface{}) ([]interface{}, *string) {
+			results := resp.(*entitySearchResponse).Actor.EntitySearch.Results
+			if results.NextCursor == "" {
+				return http.Items(results.Entities), nil
+			}
+
+			return http.Items(results.Entities), &results.NextCursor
+		},
+	)
+
+	return http.NewIterator(ctx, fetch, opts...)
+}
+
+const searchEntitiesQuery = `query(
+	$cursor: String,
+	$options: EntitySearchOptions,
+	$query: String,
+	$queryBuilder: EntitySearchQueryBuilder,
+	$sortBy: [EntitySearchSortCriteria],
+) { actor { entitySearch(
+	options: $options,
+	query: $query,
+	queryBuilder: $queryBuilder,
+	sortBy: $sortBy,
+) {
+	count
+	query
+	results(cursor: $cursor) {
+		entities {
+			__typename
+			accountId
+			domain
+			entityType
+			guid
+			indexedAt
+			name
+			permalink
+			reporting
+			type
+			... on ApmApplicationEntityOutline {
+				__typename
+				alertSeverity
+				applicationId
+				language
+			}
+			... on ApmDatabaseInstanceEntityOutline {
+				__typename
+				host
+				portOrPath
+				vendor
+			}
+			... on ApmExternalServiceEntityOutline {
+				__typename
+				host
+			}
+			... on BrowserApplicationEntityOutline {
+				__typename
+				agentInstallType
+				alertSeverity
+				applicationId
+				servingApmApplicationId
+			}
+			... on DashboardEntityOutline {
+				__typename
+				dashboardParentGuid
+			}
+			... on GenericEntityOutline {
+				__typename
+			}
+			... on GenericInfrastructureEntityOutline {
+				__typename
+				alertSeverity
+				integrationTypeCode
+			}
+			... on InfrastructureAwsLambdaFunctionEntityOutline {
+				__typename
+				alertSeverity
+				integrationTypeCode
+				runtime
+			}
+			... on InfrastructureHostEntityOutline {
+				__typename
+				alertSeverity
+			}
+			... on MobileApplicationEntityOutline {
+				__typename
+				alertSeverity
+				applicationId
+			}
+			... on SecureCredentialEntityOutline {
+				__typename
+				description
+				secureCredentialId
+				updatedAt
+			}
+			... on SyntheticMonitorEntityOutline {
+				__typename
+				alertSeverity
+				monitorId
+				monitorType
+				monitoredUrl
+				period
+			}
+			... on ThirdPartyServiceEntityOutline {
+				__typename
+				alertSeverity
+			}
+			... on UnavailableEntityOutline {
+				__typename
+			}
+			... on WorkloadEntityOutline {
+				__typename
+				alertSeverity
+				createdAt
+				updatedAt
+			}
+		}
+		nextCursor
+	}
+	types {
+		count
+		domain
+		entityType
+		type
+	}
+} } }`
@@ -0,0 +1,80 @@
+// +build unit
+
+package entities
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mock "github.com/newrelic/newrelic-client-go/pkg/testhelpers"
+)
+
+func newTestClient(t *testing.T, handler http.Handler) Entities {
+	ts := httptest.NewServer(handler)
+	tc := mock.NewTestConfig(t, ts)
+
+	return New(tc)
+}
+
+func TestSearchEntities_paginated(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"": `{"data": {"actor": {"entitySearch": {"count": 2, "results": {
+			"nextCursor": "page2",
+			"entities": [{"__typename": "ApmApplicationEntityOutline", "guid": "MQ", "name": "one", "applicationId": 1}]
+		}}}}}`,
+		"page2": `{"data": {"actor": {"entitySearch": {"count": 2, "results": {
+			"nextCursor": null,
+			"entities": [{"__typename": "ApmApplicationEntityOutline", "guid": "Mg", "name": "two", "applicationId": 2}]
+		}}}}}`,
+	}
+
+	cursors := []string{}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// Every variable sent is declared and passed to the search.
+		for _, v := range []string{"cursor", "options", "query", "sortBy"} {
+			assert.Contains(t, req.Query, "$"+v+":")
+		}
+		assert.Contains(t, req.Query, "query: $query")
+		assert.Contains(t, req.Query, "sortBy: $sortBy")
+		assert.Contains(t, req.Query, "options: $options")
+		assert.Contains(t, req.Query, "results(cursor: $cursor)")
+
+		assert.Equal(t, "name LIKE 'app'", req.Variables["query"])
+		assert.Equal(t, []interface{}{"NAME"}, req.Variables["sortBy"])
+		assert.Equal(t, map[string]interface{}{"limit": float64(10)}, req.Variables["options"])
+		assert.NotContains(t, req.Variables, "queryBuilder")
+
+		cursor, _ := req.Variables["cursor"].(string)
+		cursors = append(cursors, cursor)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(pages[cursor]))
+		require.NoError(t, err)
+	}))
+
+	entities, err := client.SearchEntities(
+		EntitySearchOptions{Limit: 10},
+		"name LIKE 'app'",
+		EntitySearchQueryBuilder{},
+		[]EntitySearchSortCriteria{EntitySearchSortCriteriaTypes.NAME},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "page2"}, cursors)
+	require.Len(t, entities, 2)
+	assert.Equal(t, "one", entities[0].(*ApmApplicationEntityOutline).Name)
+	assert.Equal(t, "two", entities[1].(*ApmApplicationEntityOutline).Name)
+}
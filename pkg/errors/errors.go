@@ -2,8 +2,10 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // NewNotFound returns a new instance of NotFound with an optional custom error message.
@@ -23,7 +25,8 @@ func NewNotFoundf(format string, args ...interface{}) *NotFound {
 
 // NotFound is returned when the target resource cannot be located.
 type NotFound struct {
-	err string
+	err   string
+	cause error
 }
 
 func (e *NotFound) Error() string {
@@ -34,6 +37,11 @@ func (e *NotFound) Error() string {
 	return e.err
 }
 
+// Unwrap returns the error reported by the API, such as *GraphQLErrors, if any.
+func (e *NotFound) Unwrap() error {
+	return e.cause
+}
+
 // NewUnexpectedStatusCode returns a new instance of UnexpectedStatusCode
 // with an optional custom message.
 func NewUnexpectedStatusCode(statusCode int, err string) *UnexpectedStatusCode {
@@ -66,6 +74,11 @@ func (e *UnexpectedStatusCode) Error() string {
 	return msg
 }
 
+// StatusCode returns the HTTP status code of the response.
+func (e *UnexpectedStatusCode) StatusCode() int {
+	return e.statusCode
+}
+
 // NewUnauthorizedError returns a new instance of UnauthorizedError
 // with an optional custom message.
 func NewUnauthorizedError() *UnauthorizedError {
@@ -76,10 +89,11 @@ func NewUnauthorizedError() *UnauthorizedError {
 }
 
 // UnauthorizedError is returned when a 401 HTTP status code is returned
-// from New Relic's APIs.
+// from New Relic's APIs, or when NerdGraph denies access to a resource.
 type UnauthorizedError struct {
 	err        string
 	statusCode int
+	cause      error
 }
 
 func (e *UnauthorizedError) Error() string {
@@ -92,6 +106,11 @@ func (e *UnauthorizedError) Error() string {
 	return msg
 }
 
+// Unwrap returns the error reported by the API, such as *GraphQLErrors, if any.
+func (e *UnauthorizedError) Unwrap() error {
+	return e.cause
+}
+
 // NewMaxRetriesReached returns a new instance of MaxRetriesReached with an optional custom error message.
 func NewMaxRetriesReached(err string) *MaxRetriesReached {
 	e := MaxRetriesReached{
@@ -115,3 +134,250 @@ type MaxRetriesReached struct {
 func (e *MaxRetriesReached) Error() string {
 	return fmt.Sprintf("maximum retries reached: %s", e.err)
 }
+
+// NewPayloadTooLarge returns a new instance of PayloadTooLarge for an item
+// of the given encoded size and the limit it exceeds.
+func NewPayloadTooLarge(size int, limit int) *PayloadTooLarge {
+	return &PayloadTooLarge{
+		size:  size,
+		limit: limit,
+	}
+}
+
+// PayloadTooLarge is returned when a single item is too large to be sent
+// to one of New Relic's ingest APIs, even on its own.
+type PayloadTooLarge struct {
+	size  int
+	limit int
+}
+
+func (e *PayloadTooLarge) Error() string {
+	return fmt.Sprintf("payload too large: %d bytes exceeds the limit of %d bytes", e.size, e.limit)
+}
+
+// Size returns the encoded size of the item in bytes.
+func (e *PayloadTooLarge) Size() int {
+	return e.size
+}
+
+// Limit returns the limit that was exceeded in bytes.
+func (e *PayloadTooLarge) Limit() int {
+	return e.limit
+}
+
+// GraphQLError represents a single error returned by NerdGraph.
+type GraphQLError struct {
+	Message            string                      `json:"message,omitempty"`
+	Path               []interface{}               `json:"path,omitempty"`
+	Extensions         GraphQLErrorExtensions      `json:"extensions,omitempty"`
+	DownstreamResponse []GraphQLDownstreamResponse `json:"downstreamResponse,omitempty"`
+}
+
+func (e *GraphQLError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors returns the invalid fields reported by the downstream
+// services of the error.
+func (e *GraphQLError) ValidationErrors() []GraphQLValidationError {
+	errs := []GraphQLValidationError{}
+	for _, d := range e.DownstreamResponse {
+		errs = append(errs, d.Extensions.ValidationErrors...)
+	}
+
+	return errs
+}
+
+// GraphQLErrorExtensions holds the details New Relic adds to a NerdGraph error.
+type GraphQLErrorExtensions struct {
+	ErrorClass string `json:"errorClass,omitempty"`
+	ErrorCode  string `json:"error_code,omitempty"`
+}
+
+// GraphQLDownstreamResponse represents the response of a downstream service
+// that caused a NerdGraph error.
+type GraphQLDownstreamResponse struct {
+	Extensions GraphQLDownstreamExtensions `json:"extensions,omitempty"`
+	Message    string                      `json:"message,omitempty"`
+}
+
+// GraphQLDownstreamExtensions holds the details of a downstream response.
+type GraphQLDownstreamExtensions struct {
+	Code             string                   `json:"code,omitempty"`
+	ValidationErrors []GraphQLValidationError `json:"validationErrors,omitempty"`
+}
+
+// GraphQLValidationError describes an invalid field of a request.
+type GraphQLValidationError struct {
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Well known classes of NerdGraph errors.
+const (
+	GraphQLErrorClassForbidden  = "FORBIDDEN"
+	GraphQLErrorClassNotFound   = "NOT_FOUND"
+	GraphQLErrorClassValidation = "VALIDATION"
+)
+
+// NewGraphQLErrors returns the error for the errors of a NerdGraph response.
+// Errors of the NOT_FOUND class are returned as *NotFound and errors of the
+// FORBIDDEN class as *UnauthorizedError, wrapping the *GraphQLErrors.
+func NewGraphQLErrors(errs []GraphQLError) error {
+	e := &GraphQLErrors{
+		errors: errs,
+	}
+
+	switch {
+	case e.HasClass(GraphQLErrorClassNotFound):
+		return &NotFound{
+			err:   e.Error(),
+			cause: e,
+		}
+	case e.HasClass(GraphQLErrorClassForbidden):
+		return &UnauthorizedError{
+			err:        e.Error(),
+			statusCode: http.StatusForbidden,
+			cause:      e,
+		}
+	default:
+		return e
+	}
+}
+
+// NewGraphQLNotFound returns a new instance of NotFound wrapping the errors of
+// a NerdGraph response known to report a missing resource, regardless of
+// their class.
+func NewGraphQLNotFound(errs []GraphQLError) *NotFound {
+	return &NotFound{
+		err:   "resource not found",
+		cause: &GraphQLErrors{errors: errs},
+	}
+}
+
+// GraphQLErrors is returned when a NerdGraph response holds errors.  The
+// first of them can be retrieved with errors.As and a *GraphQLError target.
+type GraphQLErrors struct {
+	errors []GraphQLError
+}
+
+func (e *GraphQLErrors) Error() string {
+	messages := []string{}
+	for _, err := range e.errors {
+		if err.Message != "" {
+			messages = append(messages, err.Message)
+		}
+
+		if err.DownstreamResponse != nil {
+			f, _ := json.Marshal(err.DownstreamResponse)
+			messages = append(messages, string(f))
+		}
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// Errors returns the errors of the response.
+func (e *GraphQLErrors) Errors() []GraphQLError {
+	return e.errors
+}
+
+// HasClass returns whether any of the errors is of the given class, such as
+// GraphQLErrorClassValidation.
+func (e *GraphQLErrors) HasClass(class string) bool {
+	for _, err := range e.errors {
+		if err.Extensions.ErrorClass == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As sets a *GraphQLError target to the first error of the response.
+func (e *GraphQLErrors) As(target interface{}) bool {
+	t, ok := target.(**GraphQLError)
+	if !ok || len(e.errors) == 0 {
+		return false
+	}
+
+	*t = &e.errors[0]
+
+	return true
+}
+
+// NewPartialResult returns a new instance of PartialResult for the errors of
+// a NerdGraph response holding data.
+func NewPartialResult(errs []GraphQLError) *PartialResult {
+	return &PartialResult{
+		cause: &GraphQLErrors{errors: errs},
+	}
+}
+
+// PartialResult is returned when a NerdGraph response holds data along with
+// errors and partial data is enabled.  The data is unmarshalled into the
+// response as with successful requests, with the fields affected by the
+// errors usually left empty.
+type PartialResult struct {
+	cause *GraphQLErrors
+}
+
+func (e *PartialResult) Error() string {
+	return fmt.Sprintf("partial result: %s", e.cause.Error())
+}
+
+// Errors returns the errors of the response.
+func (e *PartialResult) Errors() []GraphQLError {
+	return e.cause.Errors()
+}
+
+// Unwrap returns the *GraphQLErrors of the response.
+func (e *PartialResult) Unwrap() error {
+	return e.cause
+}
+
+// FieldError describes an invalid field of an input, by its path in the
+// input as sent to the API.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// NewValidationError returns a new instance of ValidationError for the
+// invalid fields of the named input.
+func NewValidationError(input string, fields []FieldError) *ValidationError {
+	return &ValidationError{
+		input:  input,
+		fields: fields,
+	}
+}
+
+// ValidationError is returned when an input is found invalid before being
+// sent to New Relic's APIs, listing every invalid field.
+type ValidationError struct {
+	input  string
+	fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := []string{}
+	for _, f := range e.fields {
+		messages = append(messages, f.Error())
+	}
+
+	return fmt.Sprintf("invalid %s: %s", e.input, strings.Join(messages, "; "))
+}
+
+// Input returns the name of the invalid input.
+func (e *ValidationError) Input() string {
+	return e.input
+}
+
+// Fields returns the invalid fields of the input.
+func (e *ValidationError) Fields() []FieldError {
+	return e.fields
+}
@@ -3,10 +3,12 @@
 package errors
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestErrorNotFound(t *testing.T) {
@@ -23,6 +25,7 @@ func TestErrorUnexpectedStatusCode(t *testing.T) {
 	e := NewUnexpectedStatusCode(99, "wat")
 
 	assert.Equal(t, "99 response returned: wat", e.Error())
+	assert.Equal(t, 99, e.StatusCode())
 }
 
 func TestErrorUnauthorized(t *testing.T) {
@@ -33,3 +36,86 @@ func TestErrorUnauthorized(t *testing.T) {
 	assert.Equal(t, 401, e.statusCode)
 	assert.True(t, strings.Contains(e.Error(), "Invalid credentials provided"))
 }
+
+func TestErrorPayloadTooLarge(t *testing.T) {
+	t.Parallel()
+
+	e := NewPayloadTooLarge(2048, 1024)
+
+	assert.Equal(t, 2048, e.Size())
+	assert.Equal(t, 1024, e.Limit())
+	assert.Equal(t, "payload too large: 2048 bytes exceeds the limit of 1024 bytes", e.Error())
+}
+
+func TestNewGraphQLErrors(t *testing.T) {
+	t.Parallel()
+
+	errs := []GraphQLError{
+		{
+			Message:    "invalid input",
+			Path:       []interface{}{"alertsNrqlConditionCreate", 0},
+			Extensions: GraphQLErrorExtensions{ErrorClass: GraphQLErrorClassValidation},
+			DownstreamResponse: []GraphQLDownstreamResponse{
+				{
+					Message: "Validation failed",
+					Extensions: GraphQLDownstreamExtensions{
+						Code:             "BAD_USER_INPUT",
+						ValidationErrors: []GraphQLValidationError{{Name: "name", Reason: "is blank"}},
+					},
+				},
+			},
+		},
+		{Message: "other error"},
+	}
+
+	err := NewGraphQLErrors(errs)
+
+	var gqlErrors *GraphQLErrors
+	require.True(t, errors.As(err, &gqlErrors))
+	assert.True(t, gqlErrors.HasClass(GraphQLErrorClassValidation))
+	assert.False(t, gqlErrors.HasClass(GraphQLErrorClassNotFound))
+	assert.Equal(t, errs, gqlErrors.Errors())
+	assert.Equal(t, `invalid input, [{"extensions":{"code":"BAD_USER_INPUT","validationErrors":[{"name":"name","reason":"is blank"}]},"message":"Validation failed"}], other error`, err.Error())
+
+	var gqlErr *GraphQLError
+	require.True(t, errors.As(err, &gqlErr))
+	assert.Equal(t, "invalid input", gqlErr.Message)
+	assert.Equal(t, []GraphQLValidationError{{Name: "name", Reason: "is blank"}}, gqlErr.ValidationErrors())
+}
+
+func TestNewGraphQLErrors_wellKnownClasses(t *testing.T) {
+	t.Parallel()
+
+	err := NewGraphQLErrors([]GraphQLError{{Message: "no such policy", Extensions: GraphQLErrorExtensions{ErrorClass: "NOT_FOUND"}}})
+	require.IsType(t, &NotFound{}, err)
+	assert.Equal(t, "no such policy", err.Error())
+
+	var gqlErr *GraphQLError
+	require.True(t, errors.As(err, &gqlErr))
+	assert.Equal(t, "NOT_FOUND", gqlErr.Extensions.ErrorClass)
+
+	err = NewGraphQLErrors([]GraphQLError{{Message: "access denied", Extensions: GraphQLErrorExtensions{ErrorClass: "FORBIDDEN"}}})
+	require.IsType(t, &UnauthorizedError{}, err)
+	assert.Equal(t, "403 response returned: access denied", err.Error())
+	require.True(t, errors.As(err, &gqlErr))
+	assert.Equal(t, "access denied", gqlErr.Message)
+
+	notFound := NewGraphQLNotFound([]GraphQLError{{Message: "Not Found"}})
+	assert.Equal(t, "resource not found", notFound.Error())
+	require.True(t, errors.As(notFound, &gqlErr))
+	assert.Equal(t, "Not Found", gqlErr.Message)
+}
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	err := NewValidationError("NRQL condition \"cpu\"", []FieldError{
+		{Field: "name", Message: "is required"},
+		{Field: "terms[0].thresholdDuration", Message: "must be a multiple of the aggregation window of 60 seconds"},
+	})
+
+	assert.Equal(t, `invalid NRQL condition "cpu": name: is required; terms[0].thresholdDuration: must be a multiple of the aggregation window of 60 seconds`, err.Error())
+	assert.Equal(t, `NRQL condition "cpu"`, err.Input())
+	assert.Len(t, err.Fields(), 2)
+	assert.Equal(t, "name: is required", err.Fields()[0].Error())
+}
@@ -1,21 +1,33 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/newrelic/newrelic-client-go/internal/batch"
 	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/internal/logging"
 	"github.com/newrelic/newrelic-client-go/pkg/config"
 )
 
 const (
-	DefaultBatchWorkers = 1
-	DefaultBatchSize    = 900
-	DefaultBatchTimeout = 60 * time.Second
+	DefaultBatchWorkers     = 1
+	DefaultBatchSize        = 900
+	DefaultBatchTimeout     = 60 * time.Second
+	DefaultBatchMaxInFlight = batch.DefaultMaxInFlight
+	DefaultBatchRetries     = batch.DefaultRetries
+
+	// MaxPayloadSize is the maximum size in bytes of an uncompressed
+	// payload sent to the Insights API.
+	MaxPayloadSize = 10 * 1000 * 1000
+
+	// MaxCompressedPayloadSize is the maximum size in bytes of a gzipped
+	// payload sent to the Insights API.
+	MaxCompressedPayloadSize = 1000 * 1000
 )
 
 // Events is used to send custom events to NRDB.
@@ -25,15 +37,17 @@ type Events struct {
 	logger logging.Logger
 
 	// For queue based event handling
-	accountID  int
-	eventQueue chan []byte
-	eventTimer *time.Timer
-	flushQueue []chan bool
+	accountID          int
+	batcher            *batch.Batcher
+	failedBatchHandler FailedBatchHandler
 
 	// These have defaults
-	batchWorkers int
-	batchSize    int
-	batchTimeout time.Duration
+	batchWorkers     int
+	batchSize        int
+	batchTimeout     time.Duration
+	batchMaxInFlight int
+	batchRetries     int
+	payloadLimits    batch.SizeLimits
 }
 
 // New is used to create a new Events client instance.
@@ -44,12 +58,18 @@ func New(cfg config.Config) Events {
 	client.SetAuthStrategy(&http.InsightsInsertKeyAuthorizer{})
 
 	pkg := Events{
-		client:       client,
-		config:       cfg,
-		logger:       cfg.GetLogger(),
-		batchWorkers: DefaultBatchWorkers,
-		batchSize:    DefaultBatchSize,
-		batchTimeout: DefaultBatchTimeout,
+		client:           client,
+		config:           cfg,
+		logger:           cfg.GetLogger(),
+		batchWorkers:     DefaultBatchWorkers,
+		batchSize:        DefaultBatchSize,
+		batchTimeout:     DefaultBatchTimeout,
+		batchMaxInFlight: DefaultBatchMaxInFlight,
+		batchRetries:     DefaultBatchRetries,
+		payloadLimits: batch.SizeLimits{
+			MaxPayloadSize:           MaxPayloadSize,
+			MaxCompressedPayloadSize: MaxCompressedPayloadSize,
+		},
 	}
 
 	return pkg
@@ -57,6 +77,11 @@ func New(cfg config.Config) Events {
 
 // CreateEvent reports a custom event to New Relic.
 func (e *Events) CreateEvent(accountID int, event interface{}) error {
+	return e.CreateEventWithContext(context.Background(), accountID, event)
+}
+
+// CreateEventWithContext reports a custom event to New Relic.
+func (e *Events) CreateEventWithContext(ctx context.Context, accountID int, event interface{}) error {
 	jsonData, err := e.marshalEvent(event)
 	if err != nil {
 		return err
@@ -67,7 +92,7 @@ func (e *Events) CreateEvent(accountID int, event interface{}) error {
 
 	resp := &createEventResponse{}
 
-	_, err = e.client.Post(e.config.Region().InsightsURL(accountID), nil, *jsonData, resp)
+	_, err = e.client.PostWithContext(ctx, e.config.Region().InsightsURL(accountID), nil, *jsonData, resp)
 	if err != nil {
 		return err
 	}
@@ -1,16 +1,25 @@
 package events
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/batch"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
+// BatchStats holds the counters of an Events client in batch mode.
+type BatchStats = batch.Stats
+
+// FailedBatchHandler is called with the events of a batch that could not be
+// sent to New Relic, along with the error returned by the final attempt.
+type FailedBatchHandler func(events [][]byte, err error)
+
 // BatchMode enables the Events client to accept, queue, and post
 // Events on behalf of the consuming application
 func (e *Events) BatchMode(ctx context.Context, accountID int, opts ...BatchConfigOption) (err error) {
-	if e.eventQueue != nil {
+	if e.batcher != nil {
 		return errors.New("the Events client is already in batch mode")
 	}
 
@@ -24,30 +33,25 @@ func (e *Events) BatchMode(ctx context.Context, accountID int, opts ...BatchConf
 	}
 
 	e.accountID = accountID
-	e.eventQueue = make(chan []byte, e.batchSize)
-	e.flushQueue = make([]chan bool, e.batchWorkers)
-	e.eventTimer = time.NewTimer(e.batchTimeout)
-
-	// Handle timer based flushing
-	go func() {
-		err := e.watchdog(ctx)
-		if err != nil {
-			e.logger.Error("watchdog returned error", "error", err)
-		}
-	}()
 
-	// Spin up some workers
-	for x := range e.flushQueue {
-		e.flushQueue[x] = make(chan bool, 1)
+	cfg := batch.Config{
+		Workers:     e.batchWorkers,
+		Size:        e.batchSize,
+		Timeout:     e.batchTimeout,
+		MaxInFlight: e.batchMaxInFlight,
+		Retries:     e.batchRetries,
+	}
 
-		go func(id int) {
-			err := e.batchWorker(ctx, id)
-			if err != nil {
-				e.logger.Error("batch worker returned error", "error", err)
-			}
-		}(x)
+	if e.failedBatchHandler != nil {
+		handler := e.failedBatchHandler
+		cfg.OnFailure = func(items []interface{}, err error) {
+			handler(toEvents(items), err)
+		}
 	}
 
+	e.batcher = batch.New(e.sendBatch, cfg, e.logger)
+	e.batcher.Start(ctx)
+
 	return nil
 }
 
@@ -95,11 +99,51 @@ func BatchConfigTimeout(seconds int) BatchConfigOption {
 	}
 }
 
+// BatchConfigMaxInFlight is the maximum number of batches being sent
+// to New Relic at once.  Once reached, queueing blocks until a send
+// completes.
+func BatchConfigMaxInFlight(count int) BatchConfigOption {
+	return func(e *Events) error {
+		if count <= 0 {
+			return errors.New("events: invalid max in flight count specified")
+		}
+
+		e.batchMaxInFlight = count
+		return nil
+	}
+}
+
+// BatchConfigRetries is the number of times a batch that failed to
+// send is retried before it is dropped.
+func BatchConfigRetries(count int) BatchConfigOption {
+	return func(e *Events) error {
+		if count < 0 {
+			return errors.New("events: invalid retry count specified")
+		}
+
+		e.batchRetries = count
+		return nil
+	}
+}
+
+// BatchConfigFailedBatchHandler sets a handler that is called with every
+// batch of events that is dropped after failing to send.
+func BatchConfigFailedBatchHandler(handler FailedBatchHandler) BatchConfigOption {
+	return func(e *Events) error {
+		if handler == nil {
+			return errors.New("events: invalid failed batch handler specified")
+		}
+
+		e.failedBatchHandler = handler
+		return nil
+	}
+}
+
 // EnqueueEventContext handles the queueing. Only works in batch mode. If you wish to be able to avoid blocking
 // forever until the event can be queued, provide a ctx with a deadline or timeout as this function will
 // bail when ctx.Done() is closed and return and error.
 func (e *Events) EnqueueEvent(ctx context.Context, event interface{}) (err error) {
-	if e.eventQueue == nil {
+	if e.batcher == nil {
 		return errors.New("queueing not enabled for this client")
 	}
 
@@ -111,126 +155,55 @@ func (e *Events) EnqueueEvent(ctx context.Context, event interface{}) (err error
 		return errors.New("events: EnqueueEvent marhal returned nil data")
 	}
 
-	select {
-	case e.eventQueue <- *jsonData:
-		return nil
-	case <-ctx.Done():
-		e.logger.Trace("EnqueueEvent: exiting per context Done")
-		return ctx.Err()
+	// Payloads are JSON arrays, so an event must leave room for the brackets
+	if len(*jsonData)+2 > e.payloadLimits.MaxPayloadSize {
+		return nrErrors.NewPayloadTooLarge(len(*jsonData), e.payloadLimits.MaxPayloadSize)
 	}
+
+	return e.batcher.Enqueue(ctx, *jsonData)
 }
 
 // Flush gives the user a way to manually flush the queue in the foreground.
 // This is also used by watchdog when the timer expires.
 func (e *Events) Flush() error {
-	if e.flushQueue == nil {
+	if e.batcher == nil {
 		return errors.New("queueing not enabled for this client")
 	}
 
 	e.logger.Debug("flushing events")
-
-	for x := range e.flushQueue {
-		e.flushQueue[x] <- true
-	}
+	e.batcher.Flush()
 
 	return nil
 }
 
-//
-// batchWorker reads []byte from the queue until a threshold is passed,
-// then copies the []byte it has read and sends that batch along to Insights
-// in its own goroutine.
-//
-func (e *Events) batchWorker(ctx context.Context, id int) (err error) {
-	if e == nil {
-		return errors.New("batchWorker: invalid Events, unable to start worker")
-	}
-	if id < 0 || len(e.flushQueue) < id {
-		return errors.New("batchWorker: invalid worker id specified")
-	}
-
-	eventBuf := make([][]byte, e.batchSize)
-	count := 0
-
-	for {
-		select {
-		case item := <-e.eventQueue:
-			eventBuf[count] = item
-			count++
-			if count >= e.batchSize {
-				e.grabAndConsumeEvents(count, eventBuf)
-				count = 0
-			}
-		case <-e.flushQueue[id]:
-			if count > 0 {
-				e.grabAndConsumeEvents(count, eventBuf)
-				count = 0
-			}
-		case <-ctx.Done():
-			e.logger.Trace("batchWorker[", id, "]: exiting per context Done")
-			return ctx.Err()
-		}
+// Shutdown stops accepting events, sends all queued events to New Relic and
+// waits for the sends to complete.  If ctx is done first, the sends still in
+// flight are canceled and the context's error is returned.
+func (e *Events) Shutdown(ctx context.Context) error {
+	if e.batcher == nil {
+		return errors.New("queueing not enabled for this client")
 	}
-}
 
-//
-// watchdog has a Timer that will send the results once the
-// it has expired.
-//
-func (e *Events) watchdog(ctx context.Context) (err error) {
-	if e.eventTimer == nil {
-		return errors.New("invalid timer for watchdog()")
-	}
-
-	for {
-		select {
-		case <-e.eventTimer.C:
-			e.logger.Debug("Timeout expired, flushing queued events")
-			if err = e.Flush(); err != nil {
-				return
-			}
-			e.eventTimer.Reset(e.batchTimeout)
-		case <-ctx.Done():
-			e.logger.Trace("watchdog exiting: context finished")
-			return ctx.Err()
-		}
-	}
+	return e.batcher.Shutdown(ctx)
 }
 
-// grabAndConsumeEvents makes a copy of the event handles,
-// and asynchronously writes those events in its own goroutine.
-func (e *Events) grabAndConsumeEvents(count int, eventBuf [][]byte) {
-	saved := make([][]byte, count)
-	for i := 0; i < count; i++ {
-		saved[i] = eventBuf[i]
-		eventBuf[i] = nil
+// BatchStats returns the counters of the events handled in batch mode.
+func (e *Events) BatchStats() BatchStats {
+	if e.batcher == nil {
+		return BatchStats{}
 	}
 
-	go func(count int, saved [][]byte) {
-		if sendErr := e.sendEvents(saved[0:count]); sendErr != nil {
-			e.logger.Error("failed to send events")
-		}
-	}(count, saved)
+	return e.batcher.Stats()
 }
 
-func (e *Events) sendEvents(events [][]byte) error {
-	var buf bytes.Buffer
-
-	// Since we already marshalled all of the data into JSON, let's make a
-	// hand-crafted, artisanal JSON array
-	buf.WriteString("[")
-	eventCount := len(events) - 1
-	for e := range events {
-		buf.Write(events[e])
-		if e < eventCount {
-			buf.WriteString(",")
-		}
-	}
-	buf.WriteString("]")
+func (e *Events) sendBatch(ctx context.Context, items []interface{}) error {
+	return batch.SendSized(ctx, items, toEvents(items), e.payloadLimits, e.postEvents)
+}
 
+func (e *Events) postEvents(ctx context.Context, payload []byte) error {
 	resp := &createEventResponse{}
 
-	_, err := e.client.Post(e.config.Region().InsightsURL(e.accountID), nil, buf.Bytes(), resp)
+	_, err := e.client.PostWithContext(ctx, e.config.Region().InsightsURL(e.accountID), nil, payload, resp)
 
 	if err != nil {
 		return err
@@ -242,3 +215,13 @@ func (e *Events) sendEvents(events [][]byte) error {
 
 	return nil
 }
+
+// toEvents converts the items of a batch back to marshaled events.
+func toEvents(items []interface{}) [][]byte {
+	events := make([][]byte, len(items))
+	for i, item := range items {
+		events[i] = item.([]byte)
+	}
+
+	return events
+}
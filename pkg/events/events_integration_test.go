@@ -91,7 +91,7 @@ func TestIntegrationEvents_BatchMode_Timeout(t *testing.T) {
 
 	// Should of flushed
 	time.Sleep(time.Duration(2*testBatchTimeout) * time.Second)
-	assert.Equal(t, 0, len(client.eventQueue))
+	assert.Equal(t, int64(len(testEvents)), client.BatchStats().Sent)
 }
 
 func TestIntegrationEvents_BatchMode_Size(t *testing.T) {
@@ -114,7 +114,7 @@ func TestIntegrationEvents_BatchMode_Size(t *testing.T) {
 
 	// Should of flushed
 	time.Sleep(time.Duration(2*testBatchTimeout) * time.Second)
-	assert.Equal(t, 0, len(client.eventQueue))
+	assert.Equal(t, int64(len(testEvents)), client.BatchStats().Sent)
 }
 
 func TestIntegrationEvents_marshalEvent(t *testing.T) {
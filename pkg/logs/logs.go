@@ -1,9 +1,11 @@
 package logs
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"github.com/newrelic/newrelic-client-go/internal/batch"
 	"github.com/newrelic/newrelic-client-go/internal/http"
 	"github.com/newrelic/newrelic-client-go/internal/logging"
 	"github.com/newrelic/newrelic-client-go/pkg/config"
@@ -12,9 +14,19 @@ import (
 // Logs is used to send log data to the New Relic Log API
 
 const (
-	DefaultBatchWorkers = 1
-	DefaultBatchSize    = 900
-	DefaultBatchTimeout = 60 * time.Second
+	DefaultBatchWorkers     = 1
+	DefaultBatchSize        = 900
+	DefaultBatchTimeout     = 60 * time.Second
+	DefaultBatchMaxInFlight = batch.DefaultMaxInFlight
+	DefaultBatchRetries     = batch.DefaultRetries
+
+	// MaxPayloadSize is the maximum size in bytes of an uncompressed
+	// payload sent to the Log API.
+	MaxPayloadSize = 10 * 1000 * 1000
+
+	// MaxCompressedPayloadSize is the maximum size in bytes of a gzipped
+	// payload sent to the Log API.
+	MaxCompressedPayloadSize = 1000 * 1000
 )
 
 type Logs struct {
@@ -23,15 +35,18 @@ type Logs struct {
 	logger logging.Logger
 
 	// For queue based log handling
-	accountID  int
-	logQueue   chan interface{}
-	logTimer   *time.Timer
-	flushQueue []chan bool
+	accountID          int
+	common             *Common
+	batcher            *batch.Batcher
+	failedBatchHandler FailedBatchHandler
 
 	// These have defaults
-	batchWorkers int
-	batchSize    int
-	batchTimeout time.Duration
+	batchWorkers     int
+	batchSize        int
+	batchTimeout     time.Duration
+	batchMaxInFlight int
+	batchRetries     int
+	payloadLimits    batch.SizeLimits
 }
 
 // New is used to create a new Logs client instance.
@@ -46,24 +61,48 @@ func New(cfg config.Config) Logs {
 	}
 
 	pkg := Logs{
-		client:       client,
-		config:       cfg,
-		logger:       cfg.GetLogger(),
-		batchWorkers: DefaultBatchWorkers,
-		batchSize:    DefaultBatchSize,
-		batchTimeout: DefaultBatchTimeout,
+		client:           client,
+		config:           cfg,
+		logger:           cfg.GetLogger(),
+		batchWorkers:     DefaultBatchWorkers,
+		batchSize:        DefaultBatchSize,
+		batchTimeout:     DefaultBatchTimeout,
+		batchMaxInFlight: DefaultBatchMaxInFlight,
+		batchRetries:     DefaultBatchRetries,
+		payloadLimits: batch.SizeLimits{
+			MaxPayloadSize:           MaxPayloadSize,
+			MaxCompressedPayloadSize: MaxCompressedPayloadSize,
+		},
 	}
 
 	return pkg
 }
 
 // CreateLogEntry reports a log entry to New Relic.
-// It's up to the caller to send a valid Log API payload, no checking done here
+// Entries of type LogEntry are validated, it's up to the caller to send a valid
+// Log API payload for any other value.
 func (l *Logs) CreateLogEntry(logEntry interface{}) error {
+	return l.CreateLogEntryWithContext(context.Background(), logEntry)
+}
+
+// CreateLogEntryWithContext reports a log entry to New Relic.
+// Entries of type LogEntry are validated, it's up to the caller to send a valid
+// Log API payload for any other value.
+func (l *Logs) CreateLogEntryWithContext(ctx context.Context, logEntry interface{}) error {
 	if logEntry == nil {
 		return errors.New("logs: CreateLogEntry: logEntry is nil, nothing to do")
 	}
-	_, err := l.client.Post(l.config.Region().LogsURL(), nil, logEntry, nil)
+
+	switch entry := logEntry.(type) {
+	case LogEntry:
+		return l.CreateLogsWithContext(ctx, []LogBlock{{Logs: []LogEntry{entry}}})
+	case *LogEntry:
+		if entry != nil {
+			return l.CreateLogsWithContext(ctx, []LogBlock{{Logs: []LogEntry{*entry}}})
+		}
+	}
+
+	_, err := l.client.PostWithContext(ctx, l.config.Region().LogsURL(), nil, logEntry, nil)
 
 	// If no error is returned then the call succeeded
 	if err != nil {
@@ -72,3 +111,28 @@ func (l *Logs) CreateLogEntry(logEntry interface{}) error {
 
 	return nil
 }
+
+// CreateLogs reports one or more blocks of log entries to New Relic.
+func (l *Logs) CreateLogs(blocks []LogBlock) error {
+	return l.CreateLogsWithContext(context.Background(), blocks)
+}
+
+// CreateLogsWithContext reports one or more blocks of log entries to New Relic.
+func (l *Logs) CreateLogsWithContext(ctx context.Context, blocks []LogBlock) error {
+	if len(blocks) == 0 {
+		return errors.New("logs: CreateLogs: no log blocks provided, nothing to do")
+	}
+
+	for _, b := range blocks {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+	}
+
+	_, err := l.client.PostWithContext(ctx, l.config.Region().LogsURL(), nil, blocks, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
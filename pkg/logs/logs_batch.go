@@ -2,15 +2,26 @@ package logs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/batch"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
+// BatchStats holds the counters of a Logs client in batch mode.
+type BatchStats = batch.Stats
+
+// FailedBatchHandler is called with the log entries of a batch that could not
+// be sent to New Relic, along with the error returned by the final attempt.
+type FailedBatchHandler func(logs []interface{}, err error)
+
 // BatchMode enables the Logs client to accept, queue, and post
 // Logs on behalf of the consuming application
 func (e *Logs) BatchMode(ctx context.Context, accountID int, opts ...BatchConfigOption) (err error) {
-	if e.logQueue != nil {
+	if e.batcher != nil {
 		return errors.New("the Logs client is already in batch mode")
 	}
 
@@ -24,36 +35,43 @@ func (e *Logs) BatchMode(ctx context.Context, accountID int, opts ...BatchConfig
 	}
 
 	e.accountID = accountID
-	e.logQueue = make(chan interface{}, e.batchSize)
-	e.flushQueue = make([]chan bool, e.batchWorkers)
-	e.logTimer = time.NewTimer(e.batchTimeout)
-
-	// Handle timer based flushing
-	go func() {
-		err := e.watchdog(ctx)
-		if err != nil {
-			e.logger.Error(fmt.Sprintf("watchdog returned error: %v", err))
-		}
-	}()
 
-	// Spin up some workers
-	for x := range e.flushQueue {
-		e.flushQueue[x] = make(chan bool, 1)
+	cfg := batch.Config{
+		Workers:     e.batchWorkers,
+		Size:        e.batchSize,
+		Timeout:     e.batchTimeout,
+		MaxInFlight: e.batchMaxInFlight,
+		Retries:     e.batchRetries,
+	}
 
-		go func(id int) {
-			e.logger.Trace("inside anonymous function")
-			err := e.batchWorker(ctx, id)
-			if err != nil {
-				e.logger.Error(fmt.Sprintf("batch worker returned error: %v", err))
-			}
-		}(x)
+	if e.failedBatchHandler != nil {
+		handler := e.failedBatchHandler
+		cfg.OnFailure = func(items []interface{}, err error) {
+			handler(toLogs(items), err)
+		}
 	}
 
+	e.batcher = batch.New(e.sendBatch, cfg, e.logger)
+	e.batcher.Start(ctx)
+
 	return nil
 }
 
 type BatchConfigOption func(*Logs) error
 
+// BatchConfigCommon sets the common attributes sent along with every
+// batch of LogEntry values.
+func BatchConfigCommon(common Common) BatchConfigOption {
+	return func(e *Logs) error {
+		if err := validateAttributes(common.Attributes); err != nil {
+			return err
+		}
+
+		e.common = &common
+		return nil
+	}
+}
+
 // BatchConfigWorkers sets how many background workers will process
 // logs as they are queued
 func BatchConfigWorkers(count int) BatchConfigOption {
@@ -96,121 +114,203 @@ func BatchConfigTimeout(seconds int) BatchConfigOption {
 	}
 }
 
+// BatchConfigMaxInFlight is the maximum number of batches being sent
+// to New Relic at once.  Once reached, queueing blocks until a send
+// completes.
+func BatchConfigMaxInFlight(count int) BatchConfigOption {
+	return func(e *Logs) error {
+		if count <= 0 {
+			return errors.New("logs: invalid max in flight count specified")
+		}
+
+		e.batchMaxInFlight = count
+		return nil
+	}
+}
+
+// BatchConfigRetries is the number of times a batch that failed to
+// send is retried before it is dropped.
+func BatchConfigRetries(count int) BatchConfigOption {
+	return func(e *Logs) error {
+		if count < 0 {
+			return errors.New("logs: invalid retry count specified")
+		}
+
+		e.batchRetries = count
+		return nil
+	}
+}
+
+// BatchConfigFailedBatchHandler sets a handler that is called with every
+// batch of logs that is dropped after failing to send.
+func BatchConfigFailedBatchHandler(handler FailedBatchHandler) BatchConfigOption {
+	return func(e *Logs) error {
+		if handler == nil {
+			return errors.New("logs: invalid failed batch handler specified")
+		}
+
+		e.failedBatchHandler = handler
+		return nil
+	}
+}
+
 // EnqueueLogEntry handles the queueing. Only works in batch mode. If you wish to be able to avoid blocking
 // forever until the log can be queued, provide a ctx with a deadline or timeout as this function will
 // bail when ctx.Done() is closed and return and error.
+//
+// Entries of type LogEntry are validated, then sent in the detailed format along with the common
+// attributes set by BatchConfigCommon.  Any other value is sent as is.
 func (e *Logs) EnqueueLogEntry(ctx context.Context, msg interface{}) (err error) {
-	if e.logQueue == nil {
+	if e.batcher == nil {
 		return errors.New("queueing not enabled for this client")
 	}
 
-	select {
-	case e.logQueue <- msg:
-		e.logger.Trace("EnqueueLogEntry: log entry queued ")
-		return nil
-	case <-ctx.Done():
-		e.logger.Trace("EnqueueLogEntry: exiting per context Done")
-		return ctx.Err()
+	if entry, ok := msg.(*LogEntry); ok && entry != nil {
+		msg = *entry
 	}
+
+	if entry, ok := msg.(LogEntry); ok {
+		block := LogBlock{Common: e.common, Logs: []LogEntry{entry}}
+		if err := block.Validate(); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error marshaling log entry: %s", err.Error())
+	}
+
+	// Payloads are JSON arrays, so an entry must leave room for the brackets
+	if len(encoded)+2 > e.payloadLimits.MaxPayloadSize {
+		return nrErrors.NewPayloadTooLarge(len(encoded), e.payloadLimits.MaxPayloadSize)
+	}
+
+	if err := e.batcher.Enqueue(ctx, queuedLog{entry: msg, encoded: encoded}); err != nil {
+		return err
+	}
+
+	e.logger.Trace("EnqueueLogEntry: log entry queued ")
+	return nil
 }
 
 // Flush gives the user a way to manually flush the queue in the foreground.
 // This is also used by watchdog when the timer expires.
 func (e *Logs) Flush() error {
-	if e.flushQueue == nil {
+	if e.batcher == nil {
 		return errors.New("queueing not enabled for this client")
 	}
 
 	e.logger.Debug("flushing queues")
-	for x := range e.flushQueue {
-		e.logger.Trace(fmt.Sprintf("flushing logs queue: %d", x))
-		e.flushQueue[x] <- true
-	}
+	e.batcher.Flush()
 
 	return nil
 }
 
-//
-// batchWorker reads []byte from the queue until a threshold is passed,
-// then copies the []byte it has read and sends that batch along to Logs
-// in its own goroutine.
-//
-func (e *Logs) batchWorker(ctx context.Context, id int) (err error) {
-	e.logger.Trace("batchWorker")
+// Shutdown stops accepting logs, sends all queued logs to New Relic and
+// waits for the sends to complete.  If ctx is done first, the sends still in
+// flight are canceled and the context's error is returned.
+func (e *Logs) Shutdown(ctx context.Context) error {
+	if e.batcher == nil {
+		return errors.New("queueing not enabled for this client")
+	}
+
+	return e.batcher.Shutdown(ctx)
+}
 
-	if id < 0 || len(e.flushQueue) < id {
-		return errors.New("batchWorker: invalid worker id specified")
+// BatchStats returns the counters of the logs handled in batch mode.
+func (e *Logs) BatchStats() BatchStats {
+	if e.batcher == nil {
+		return BatchStats{}
 	}
 
-	logBuf := make([]interface{}, e.batchSize)
-	count := 0
+	return e.batcher.Stats()
+}
 
-	for {
-		select {
-		case item := <-e.logQueue:
-			logBuf[count] = item
-			count++
-			if count >= e.batchSize {
-				e.grabAndConsumeLogs(count, logBuf)
-				count = 0
-			}
-		case <-e.flushQueue[id]:
-			if count > 0 {
-				e.grabAndConsumeLogs(count, logBuf)
-				count = 0
-			}
-		case <-ctx.Done():
-			e.logger.Trace("batchWorker[", id, "]: exiting per context Done")
-			return ctx.Err()
+// queuedLog is a log entry along with its JSON encoding, so the size of
+// a batch is known before it is sent.
+type queuedLog struct {
+	entry   interface{}
+	encoded []byte
+}
+
+func (e *Logs) sendBatch(ctx context.Context, items []interface{}) error {
+	entries := []interface{}{}
+	values := []interface{}{}
+
+	for _, item := range items {
+		if _, ok := item.(queuedLog).entry.(LogEntry); ok {
+			entries = append(entries, item)
+		} else {
+			values = append(values, item)
 		}
 	}
-}
 
-//
-// watchdog has a Timer that will send the results once the
-// it has expired.
-//
-func (e *Logs) watchdog(ctx context.Context) (err error) {
-	e.logger.Trace("watchdog")
-	if e.logTimer == nil {
-		return errors.New("invalid timer for watchdog()")
-	}
-
-	for {
-		select {
-		case <-e.logTimer.C:
-			e.logger.Debug("Timeout expired, flushing queued logs")
-			if err = e.Flush(); err != nil {
-				return
-			}
-			e.logTimer.Reset(e.batchTimeout)
-		case <-ctx.Done():
-			e.logger.Trace("watchdog exiting: context finished")
-			return ctx.Err()
+	e.logger.Trace(fmt.Sprintf("sendBatch: log entry count: %d, value count: %d", len(entries), len(values)))
+
+	var parts []batch.Part
+
+	if len(values) > 0 {
+		encoded := make([][]byte, len(values))
+		for i, item := range values {
+			encoded[i] = item.(queuedLog).encoded
 		}
+
+		parts = append(parts, batch.Part{Items: values, Err: batch.SendSized(ctx, values, encoded, e.payloadLimits, e.sendLogs)})
 	}
+
+	if len(entries) > 0 {
+		parts = append(parts, batch.Part{Items: entries, Err: e.sendLogBlock(ctx, entries)})
+	}
+
+	return batch.MergePartialErrors(parts...)
 }
 
-// grabAndConsumeLogs makes a copy of the log handles,
-// and asynchronously writes those logs in its own goroutine.
-func (e *Logs) grabAndConsumeLogs(count int, logBuf []interface{}) {
-	e.logger.Trace("grabAndConsumeLogs")
-	saved := make([]interface{}, count)
-	for i := 0; i < count; i++ {
-		saved[i] = logBuf[i]
-		logBuf[i] = nil
+// sendLogBlock sends log entries in the detailed format, as a single LogBlock
+// split into as many requests as needed.
+func (e *Logs) sendLogBlock(ctx context.Context, items []interface{}) error {
+	entries := make([]LogEntry, len(items))
+	for i, item := range items {
+		entries[i] = item.(queuedLog).entry.(LogEntry)
+	}
+
+	block := newLogBlock(e.common, entries)
+
+	prefix := []byte(`[{"logs":`)
+	if block.Common != nil {
+		common, err := json.Marshal(block.Common)
+		if err != nil {
+			return &batch.PartialError{Failed: items, Err: err}
+		}
+
+		prefix = []byte(fmt.Sprintf(`[{"common":%s,"logs":`, common))
 	}
+	suffix := []byte(`}]`)
 
-	go func(count int, saved []interface{}) {
-		if sendErr := e.sendLogs(saved[0:count]); sendErr != nil {
-			e.logger.Error("failed to send logs")
+	encoded := make([][]byte, len(block.Logs))
+	for i, entry := range block.Logs {
+		var err error
+		if encoded[i], err = json.Marshal(entry); err != nil {
+			return &batch.PartialError{Failed: items, Err: err}
 		}
-	}(count, saved)
+	}
+
+	// Leave room for the common attributes in every request
+	limits := e.payloadLimits
+	limits.MaxPayloadSize -= len(prefix) + len(suffix)
+
+	return batch.SendSized(ctx, items, encoded, limits, func(ctx context.Context, payload []byte) error {
+		body := make([]byte, 0, len(prefix)+len(payload)+len(suffix))
+		body = append(body, prefix...)
+		body = append(body, payload...)
+		body = append(body, suffix...)
+
+		return e.sendLogs(ctx, body)
+	})
 }
 
-func (e *Logs) sendLogs(logs []interface{}) error {
-	e.logger.Trace(fmt.Sprintf("sendLogs: entry count: %d", len(logs)))
-	_, err := e.client.Post(e.config.Region().LogsURL(), nil, logs, nil)
+func (e *Logs) sendLogs(ctx context.Context, payload []byte) error {
+	_, err := e.client.PostWithContext(ctx, e.config.Region().LogsURL(), nil, payload, nil)
 
 	if err != nil {
 		return err
@@ -218,3 +318,13 @@ func (e *Logs) sendLogs(logs []interface{}) error {
 
 	return nil
 }
+
+// toLogs converts the items of a batch back to the queued log entries.
+func toLogs(items []interface{}) []interface{} {
+	logs := make([]interface{}, len(items))
+	for i, item := range items {
+		logs[i] = item.(queuedLog).entry
+	}
+
+	return logs
+}
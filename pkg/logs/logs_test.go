@@ -0,0 +1,205 @@
+// +build unit
+
+package logs
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+	mock "github.com/newrelic/newrelic-client-go/pkg/testhelpers"
+)
+
+var (
+	testTimestamp = nrtime.EpochMilliseconds(time.Unix(1600000000, 123000000))
+)
+
+func newTestClient(t *testing.T, handler http.Handler) Logs {
+	ts := httptest.NewServer(handler)
+	tc := mock.NewTestConfig(t, ts)
+	tc.LicenseKey = "licenseKey"
+
+	return New(tc)
+}
+
+// readBody returns the request body, decompressing it when required.
+func readBody(t *testing.T, r *http.Request) []byte {
+	var reader io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		reader = gz
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestLogEntryMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	entry := LogEntry{
+		Timestamp:  &testTimestamp,
+		Message:    "hello",
+		Attributes: map[string]interface{}{"level": "info"},
+	}
+
+	data, err := json.Marshal(entry)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"timestamp":1600000000123,"message":"hello","attributes":{"level":"info"}}`, string(data))
+}
+
+func TestLogBlockValidate(t *testing.T) {
+	t.Parallel()
+
+	tooMany := map[string]interface{}{}
+	for i := 0; i < MaxAttributes; i++ {
+		tooMany[strings.Repeat("a", i+1)] = i
+	}
+
+	cases := map[string]struct {
+		block LogBlock
+		valid bool
+	}{
+		"valid": {
+			block: LogBlock{Logs: []LogEntry{{Message: "hello"}}},
+			valid: true,
+		},
+		"no entries": {
+			block: LogBlock{},
+		},
+		"no message": {
+			block: LogBlock{Logs: []LogEntry{{}}},
+		},
+		"attribute name too long": {
+			block: LogBlock{Logs: []LogEntry{{
+				Message:    "hello",
+				Attributes: map[string]interface{}{strings.Repeat("a", MaxAttributeNameLength+1): 1},
+			}}},
+		},
+		"attribute value too long": {
+			block: LogBlock{Logs: []LogEntry{{
+				Message:    "hello",
+				Attributes: map[string]interface{}{"a": strings.Repeat("a", MaxAttributeValueLength+1)},
+			}}},
+		},
+		"too many attributes with common": {
+			block: LogBlock{
+				Common: &Common{Attributes: map[string]interface{}{"common": true}},
+				Logs:   []LogEntry{{Message: "hello", Attributes: tooMany}},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		err := c.block.Validate()
+		if c.valid {
+			assert.NoError(t, err, name)
+		} else {
+			assert.Error(t, err, name)
+		}
+	}
+}
+
+func TestNewLogBlock(t *testing.T) {
+	t.Parallel()
+
+	entries := []LogEntry{
+		{Message: "one", Attributes: map[string]interface{}{"host": "a", "service": "api", "line": 1}},
+		{Message: "two", Attributes: map[string]interface{}{"host": "a", "service": "api", "line": 2}},
+	}
+
+	block := newLogBlock(&Common{Attributes: map[string]interface{}{"service": "web"}}, entries)
+
+	require.NotNil(t, block.Common)
+	assert.Equal(t, map[string]interface{}{"service": "web", "host": "a"}, block.Common.Attributes)
+	assert.Equal(t, map[string]interface{}{"service": "api", "line": 1}, block.Logs[0].Attributes)
+	assert.Equal(t, map[string]interface{}{"service": "api", "line": 2}, block.Logs[1].Attributes)
+
+	// The entries given are left untouched
+	assert.Len(t, entries[0].Attributes, 3)
+}
+
+func TestCreateLogs(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan []byte, 1)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- readBody(t, r)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	err := client.CreateLogEntry(LogEntry{Message: "hello"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"logs":[{"message":"hello"}]}]`, string(<-received))
+
+	assert.Error(t, client.CreateLogEntry(LogEntry{}))
+	assert.Error(t, client.CreateLogs([]LogBlock{}))
+}
+
+func TestBatchMode_detailedFormat(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan []byte, 1)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- readBody(t, r)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	ctx := context.Background()
+
+	err := client.BatchMode(ctx, 0,
+		BatchConfigQueueSize(2),
+		BatchConfigCommon(Common{Attributes: map[string]interface{}{"service": "test"}}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, client.EnqueueLogEntry(ctx, LogEntry{Message: "one", Attributes: map[string]interface{}{"host": "a"}}))
+	require.NoError(t, client.EnqueueLogEntry(ctx, &LogEntry{Message: "two", Attributes: map[string]interface{}{"host": "a"}}))
+	require.NoError(t, client.Shutdown(ctx))
+
+	assert.JSONEq(t, `[{
+		"common": {"attributes": {"service": "test", "host": "a"}},
+		"logs": [{"message": "one"}, {"message": "two"}]
+	}]`, string(<-received))
+
+	assert.Equal(t, BatchStats{Queued: 2, Sent: 2}, client.BatchStats())
+}
+
+func TestBatchMode_enqueueErrors(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, nil)
+	client.payloadLimits.MaxPayloadSize = 32
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, client.BatchMode(ctx, 0))
+
+	// Invalid log entry
+	assert.Error(t, client.EnqueueLogEntry(ctx, LogEntry{}))
+
+	// Larger than a payload on its own
+	err := client.EnqueueLogEntry(ctx, LogEntry{Message: strings.Repeat("a", 32)})
+
+	var tooLarge *nrErrors.PayloadTooLarge
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, 32, tooLarge.Limit())
+}
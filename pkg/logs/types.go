@@ -0,0 +1,172 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+)
+
+const (
+	// MaxAttributes is the maximum number of attributes of a log entry,
+	// including its common attributes.
+	MaxAttributes = 255
+
+	// MaxAttributeNameLength is the maximum length of an attribute name.
+	MaxAttributeNameLength = 255
+
+	// MaxAttributeValueLength is the maximum length of a string attribute value.
+	MaxAttributeValueLength = 4094
+)
+
+// LogEntry represents a single log entry sent to the Log API.
+type LogEntry struct {
+	Timestamp  *nrtime.EpochMilliseconds `json:"timestamp,omitempty"`
+	Message    string                    `json:"message"`
+	Attributes map[string]interface{}    `json:"attributes,omitempty"`
+}
+
+// Validate checks the log entry against the limits of the Log API.
+func (e LogEntry) Validate() error {
+	if e.Message == "" {
+		return errors.New("logs: log entry has no message")
+	}
+
+	return validateAttributes(e.Attributes)
+}
+
+// Common holds the values shared by every log entry within a LogBlock.
+type Common struct {
+	Timestamp  *nrtime.EpochMilliseconds `json:"timestamp,omitempty"`
+	Attributes map[string]interface{}    `json:"attributes,omitempty"`
+}
+
+// LogBlock represents a group of log entries, and their common values,
+// as sent to the Log API.
+type LogBlock struct {
+	Common *Common    `json:"common,omitempty"`
+	Logs   []LogEntry `json:"logs"`
+}
+
+// Validate checks every log entry of the block, along with the common
+// attributes it inherits, against the limits of the Log API.
+func (b LogBlock) Validate() error {
+	if len(b.Logs) == 0 {
+		return errors.New("logs: log block contains no log entries")
+	}
+
+	var common map[string]interface{}
+	if b.Common != nil {
+		common = b.Common.Attributes
+	}
+
+	if err := validateAttributes(common); err != nil {
+		return err
+	}
+
+	for _, e := range b.Logs {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+
+		count := len(common)
+		for k := range e.Attributes {
+			if _, ok := common[k]; !ok {
+				count++
+			}
+		}
+
+		if count > MaxAttributes {
+			return fmt.Errorf("logs: log entry has %d attributes including common attributes, the limit is %d", count, MaxAttributes)
+		}
+	}
+
+	return nil
+}
+
+func validateAttributes(attributes map[string]interface{}) error {
+	if len(attributes) > MaxAttributes {
+		return fmt.Errorf("logs: %d attributes exceeds the limit of %d", len(attributes), MaxAttributes)
+	}
+
+	for k, v := range attributes {
+		if len(k) > MaxAttributeNameLength {
+			return fmt.Errorf("logs: attribute name %.32q... exceeds the limit of %d characters", k, MaxAttributeNameLength)
+		}
+
+		if s, ok := v.(string); ok && len(s) > MaxAttributeValueLength {
+			return fmt.Errorf("logs: value of attribute %q exceeds the limit of %d characters", k, MaxAttributeValueLength)
+		}
+	}
+
+	return nil
+}
+
+// newLogBlock groups log entries into a LogBlock, moving the attributes
+// every entry has in common into the block's common attributes.  The
+// attributes of the entries given are left untouched.
+func newLogBlock(common *Common, entries []LogEntry) LogBlock {
+	block := LogBlock{
+		Logs: entries,
+	}
+
+	if common != nil {
+		c := *common
+		block.Common = &c
+	}
+
+	if len(entries) < 2 {
+		return block
+	}
+
+	shared := map[string]interface{}{}
+	for k, v := range entries[0].Attributes {
+		if block.Common != nil {
+			if _, ok := block.Common.Attributes[k]; ok {
+				continue
+			}
+		}
+
+		shared[k] = v
+	}
+
+	for _, e := range entries[1:] {
+		for k, v := range shared {
+			if ev, ok := e.Attributes[k]; !ok || !reflect.DeepEqual(ev, v) {
+				delete(shared, k)
+			}
+		}
+	}
+
+	if len(shared) == 0 {
+		return block
+	}
+
+	if block.Common == nil {
+		block.Common = &Common{}
+	}
+
+	attributes := make(map[string]interface{}, len(block.Common.Attributes)+len(shared))
+	for k, v := range block.Common.Attributes {
+		attributes[k] = v
+	}
+	for k, v := range shared {
+		attributes[k] = v
+	}
+	block.Common.Attributes = attributes
+
+	block.Logs = make([]LogEntry, len(entries))
+	for i, e := range entries {
+		block.Logs[i] = e
+		block.Logs[i].Attributes = make(map[string]interface{}, len(e.Attributes)-len(shared))
+
+		for k, v := range e.Attributes {
+			if _, ok := shared[k]; !ok {
+				block.Logs[i].Attributes[k] = v
+			}
+		}
+	}
+
+	return block
+}
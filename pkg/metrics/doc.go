@@ -0,0 +1,17 @@
+/*
+Package metrics provides a programmatic API for reporting dimensional metrics to New Relic.
+
+Metrics are sent to the Metric API as gauges, counts or summaries, grouped
+into blocks that can share a common set of attributes.  Payloads are
+compressed with gzip before being sent.
+
+Authentication
+
+You will need a valid Insights insert key or a license key to communicate with
+the backend New Relic API that provides this functionality.  See the API key
+documentation below for more information on how to locate these keys:
+
+https://docs.newrelic.com/docs/apis/get-started/intro-apis/types-new-relic-api-keys
+
+*/
+package metrics
@@ -0,0 +1,51 @@
+// +build integration
+
+package metrics
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+)
+
+func Example_basic() {
+	// Initialize the client configuration.  An Insights insert key is required
+	// to communicate with the backend API.
+	cfg := config.New()
+	cfg.InsightsInsertKey = os.Getenv("NEW_RELIC_INSIGHTS_INSERT_KEY")
+
+	// Initialize the client.
+	client := New(cfg)
+
+	now := nrtime.EpochMilliseconds(time.Now())
+
+	blocks := []MetricBlock{
+		{
+			Common: &Common{
+				Timestamp:  &now,
+				IntervalMs: 10000,
+				Attributes: map[string]interface{}{
+					"host.name": "dev.server.com",
+				},
+			},
+			Metrics: []Metric{
+				Gauge{
+					Name:  "memory.heap",
+					Value: 2.3,
+				},
+				Count{
+					Name:  "cache.misses",
+					Value: 17,
+				},
+			},
+		},
+	}
+
+	// Post the metrics.
+	if err := client.CreateMetrics(blocks); err != nil {
+		log.Fatal("error posting metrics:", err)
+	}
+}
@@ -0,0 +1,42 @@
+// +build integration
+
+package metrics
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+)
+
+func Example_batch() {
+	// Initialize the client configuration.  An Insights insert key is required
+	// to communicate with the backend API.
+	cfg := config.New()
+	cfg.InsightsInsertKey = os.Getenv("NEW_RELIC_INSIGHTS_INSERT_KEY")
+
+	// Initialize the client.
+	client := New(cfg)
+
+	// Start batch mode, sending the host name along with every batch.
+	common := Common{
+		Attributes: map[string]interface{}{
+			"host.name": "dev.server.com",
+		},
+	}
+
+	if err := client.BatchMode(context.Background(), BatchConfigCommon(common)); err != nil {
+		log.Fatal("error starting batch mode:", err)
+	}
+
+	// Queue a metric.
+	if err := client.EnqueueMetric(context.Background(), Gauge{Name: "memory.heap", Value: 2.3}); err != nil {
+		log.Fatal("error queueing metric:", err)
+	}
+
+	// Force flush metrics
+	if err := client.Flush(); err != nil {
+		log.Fatal("error flushing metric queue:", err)
+	}
+}
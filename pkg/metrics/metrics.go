@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/batch"
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/internal/logging"
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+)
+
+const (
+	DefaultBatchWorkers     = 1
+	DefaultBatchSize        = 900
+	DefaultBatchTimeout     = 60 * time.Second
+	DefaultBatchMaxInFlight = batch.DefaultMaxInFlight
+	DefaultBatchRetries     = batch.DefaultRetries
+)
+
+// Metrics is used to send dimensional metrics to the New Relic Metric API.
+type Metrics struct {
+	client http.Client
+	config config.Config
+	logger logging.Logger
+
+	// For queue based metric handling
+	common             *Common
+	batcher            *batch.Batcher
+	failedBatchHandler FailedBatchHandler
+
+	// These have defaults
+	batchWorkers     int
+	batchSize        int
+	batchTimeout     time.Duration
+	batchMaxInFlight int
+	batchRetries     int
+}
+
+// New is used to create a new Metrics client instance.
+func New(cfg config.Config) Metrics {
+	cfg.Compression = config.Compression.Gzip
+
+	client := http.NewClient(cfg)
+	if cfg.InsightsInsertKey != "" {
+		client.SetAuthStrategy(&http.LogsInsertKeyAuthorizer{})
+	} else {
+		client.SetAuthStrategy(&http.LicenseKeyAuthorizer{})
+	}
+
+	pkg := Metrics{
+		client:           client,
+		config:           cfg,
+		logger:           cfg.GetLogger(),
+		batchWorkers:     DefaultBatchWorkers,
+		batchSize:        DefaultBatchSize,
+		batchTimeout:     DefaultBatchTimeout,
+		batchMaxInFlight: DefaultBatchMaxInFlight,
+		batchRetries:     DefaultBatchRetries,
+	}
+
+	return pkg
+}
+
+// CreateMetrics reports one or more blocks of metrics to New Relic.
+func (m *Metrics) CreateMetrics(blocks []MetricBlock) error {
+	return m.CreateMetricsWithContext(context.Background(), blocks)
+}
+
+// CreateMetricsWithContext reports one or more blocks of metrics to New Relic.
+func (m *Metrics) CreateMetricsWithContext(ctx context.Context, blocks []MetricBlock) error {
+	if len(blocks) == 0 {
+		return errors.New("metrics: CreateMetrics: no metric blocks provided, nothing to do")
+	}
+
+	for _, b := range blocks {
+		if len(b.Metrics) == 0 {
+			return errors.New("metrics: CreateMetrics: metric block contains no metrics")
+		}
+	}
+
+	resp := &createMetricsResponse{}
+
+	_, err := m.client.PostWithContext(ctx, m.config.Region().MetricsURL(), nil, blocks, resp)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Trace("metrics accepted", "requestId", resp.RequestID)
+
+	return nil
+}
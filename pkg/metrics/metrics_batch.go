@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/batch"
+)
+
+// BatchStats holds the counters of a Metrics client in batch mode.
+type BatchStats = batch.Stats
+
+// FailedBatchHandler is called with the metrics of a batch that could not be
+// sent to New Relic, along with the error returned by the final attempt.
+type FailedBatchHandler func(metrics []Metric, err error)
+
+// BatchMode enables the Metrics client to accept, queue, and post
+// Metrics on behalf of the consuming application
+func (m *Metrics) BatchMode(ctx context.Context, opts ...BatchConfigOption) (err error) {
+	if m.batcher != nil {
+		return errors.New("the Metrics client is already in batch mode")
+	}
+
+	// Loop through config options
+	for _, fn := range opts {
+		if nil != fn {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+	}
+
+	cfg := batch.Config{
+		Workers:     m.batchWorkers,
+		Size:        m.batchSize,
+		Timeout:     m.batchTimeout,
+		MaxInFlight: m.batchMaxInFlight,
+		Retries:     m.batchRetries,
+	}
+
+	if m.failedBatchHandler != nil {
+		handler := m.failedBatchHandler
+		cfg.OnFailure = func(items []interface{}, err error) {
+			handler(toMetrics(items), err)
+		}
+	}
+
+	m.batcher = batch.New(m.sendBatch, cfg, m.logger)
+	m.batcher.Start(ctx)
+
+	return nil
+}
+
+type BatchConfigOption func(*Metrics) error
+
+// BatchConfigWorkers sets how many background workers will process
+// metrics as they are queued
+func BatchConfigWorkers(count int) BatchConfigOption {
+	return func(m *Metrics) error {
+		if count <= 0 {
+			return errors.New("metrics: invalid worker count specified")
+		}
+
+		m.batchWorkers = count
+
+		return nil
+	}
+}
+
+// BatchConfigQueueSize is how many metrics to queue before sending
+// to New Relic.  If this limit is hit before the Timeout, the queue
+// is flushed.
+func BatchConfigQueueSize(size int) BatchConfigOption {
+	return func(m *Metrics) error {
+		if size <= 0 {
+			return errors.New("metrics: invalid queue size specified")
+		}
+
+		m.batchSize = size
+		return nil
+	}
+}
+
+// BatchConfigTimeout is the maximum amount of time to queue metrics
+// before sending to New Relic.  If this is reached before the Size
+// limit, the queue is flushed.
+func BatchConfigTimeout(seconds int) BatchConfigOption {
+	return func(m *Metrics) error {
+		if seconds <= 0 {
+			return errors.New("metrics: invalid timeout specified")
+		}
+
+		m.batchTimeout = time.Duration(seconds) * time.Second
+		return nil
+	}
+}
+
+// BatchConfigCommon sets the common block sent along with every batch
+// of metrics, allowing attributes shared by all metrics to be sent once.
+func BatchConfigCommon(common Common) BatchConfigOption {
+	return func(m *Metrics) error {
+		m.common = &common
+		return nil
+	}
+}
+
+// BatchConfigMaxInFlight is the maximum number of batches being sent
+// to New Relic at once.  Once reached, queueing blocks until a send
+// completes.
+func BatchConfigMaxInFlight(count int) BatchConfigOption {
+	return func(m *Metrics) error {
+		if count <= 0 {
+			return errors.New("metrics: invalid max in flight count specified")
+		}
+
+		m.batchMaxInFlight = count
+		return nil
+	}
+}
+
+// BatchConfigRetries is the number of times a batch that failed to
+// send is retried before it is dropped.
+func BatchConfigRetries(count int) BatchConfigOption {
+	return func(m *Metrics) error {
+		if count < 0 {
+			return errors.New("metrics: invalid retry count specified")
+		}
+
+		m.batchRetries = count
+		return nil
+	}
+}
+
+// BatchConfigFailedBatchHandler sets a handler that is called with every
+// batch of metrics that is dropped after failing to send.
+func BatchConfigFailedBatchHandler(handler FailedBatchHandler) BatchConfigOption {
+	return func(m *Metrics) error {
+		if handler == nil {
+			return errors.New("metrics: invalid failed batch handler specified")
+		}
+
+		m.failedBatchHandler = handler
+		return nil
+	}
+}
+
+// EnqueueMetric handles the queueing. Only works in batch mode. If you wish to be able to avoid blocking
+// forever until the metric can be queued, provide a ctx with a deadline or timeout as this function will
+// bail when ctx.Done() is closed and return and error.
+func (m *Metrics) EnqueueMetric(ctx context.Context, metric Metric) (err error) {
+	if m.batcher == nil {
+		return errors.New("queueing not enabled for this client")
+	}
+
+	if metric == nil {
+		return errors.New("metrics: EnqueueMetric: metric is nil, nothing to do")
+	}
+
+	return m.batcher.Enqueue(ctx, metric)
+}
+
+// Flush gives the user a way to manually flush the queue in the foreground.
+// This is also used by watchdog when the timer expires.
+func (m *Metrics) Flush() error {
+	if m.batcher == nil {
+		return errors.New("queueing not enabled for this client")
+	}
+
+	m.logger.Debug("flushing metrics")
+	m.batcher.Flush()
+
+	return nil
+}
+
+// Shutdown stops accepting metrics, sends all queued metrics to New Relic and
+// waits for the sends to complete.  If ctx is done first, the sends still in
+// flight are canceled and the context's error is returned.
+func (m *Metrics) Shutdown(ctx context.Context) error {
+	if m.batcher == nil {
+		return errors.New("queueing not enabled for this client")
+	}
+
+	return m.batcher.Shutdown(ctx)
+}
+
+// BatchStats returns the counters of the metrics handled in batch mode.
+func (m *Metrics) BatchStats() BatchStats {
+	if m.batcher == nil {
+		return BatchStats{}
+	}
+
+	return m.batcher.Stats()
+}
+
+func (m *Metrics) sendBatch(ctx context.Context, items []interface{}) error {
+	return m.sendMetrics(ctx, toMetrics(items))
+}
+
+func (m *Metrics) sendMetrics(ctx context.Context, metrics []Metric) error {
+	blocks := []MetricBlock{
+		{
+			Common:  m.common,
+			Metrics: metrics,
+		},
+	}
+
+	return m.CreateMetricsWithContext(ctx, blocks)
+}
+
+// toMetrics converts the items of a batch back to metrics.
+func toMetrics(items []interface{}) []Metric {
+	metrics := make([]Metric, len(items))
+	for i, item := range items {
+		metrics[i] = item.(Metric)
+	}
+
+	return metrics
+}
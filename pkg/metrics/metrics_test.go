@@ -0,0 +1,209 @@
+// +build unit
+
+package metrics
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+	mock "github.com/newrelic/newrelic-client-go/pkg/testhelpers"
+)
+
+const (
+	testInsertKey = "insertKey"
+)
+
+var (
+	testTimestamp = nrtime.EpochMilliseconds(time.Unix(1600000000, 123000000))
+)
+
+func newTestClient(t *testing.T, handler http.Handler) Metrics {
+	ts := httptest.NewServer(handler)
+	tc := mock.NewTestConfig(t, ts)
+	tc.InsightsInsertKey = testInsertKey
+
+	return New(tc)
+}
+
+// readBody returns the request body, decompressing it when required.
+func readBody(t *testing.T, r *http.Request) []byte {
+	var reader io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		reader = gz
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestMetricMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]Metric{
+		`{"type":"gauge","name":"temperature","value":21.5,"timestamp":1600000000123,"attributes":{"room":"kitchen"}}`: Gauge{
+			Name:       "temperature",
+			Value:      21.5,
+			Timestamp:  &testTimestamp,
+			Attributes: map[string]interface{}{"room": "kitchen"},
+		},
+		`{"type":"count","name":"requests","value":3,"interval.ms":10000}`: Count{
+			Name:       "requests",
+			Value:      3,
+			IntervalMs: 10000,
+		},
+		`{"type":"summary","name":"duration","value":{"count":2,"sum":5,"min":1,"max":4},"interval.ms":10000}`: &Summary{
+			Name:       "duration",
+			Value:      SummaryValue{Count: 2, Sum: 5, Min: 1, Max: 4},
+			IntervalMs: 10000,
+		},
+	}
+
+	for expected, metric := range cases {
+		actual, err := json.Marshal(metric)
+		require.NoError(t, err)
+		assert.JSONEq(t, expected, string(actual))
+	}
+}
+
+func TestCreateMetrics(t *testing.T) {
+	t.Parallel()
+
+	blocks := []MetricBlock{
+		{
+			Common: &Common{
+				IntervalMs: 10000,
+				Attributes: map[string]interface{}{"host.name": "dev.server.com"},
+			},
+			Metrics: []Metric{
+				Gauge{Name: "memory.heap", Value: 2.3, Timestamp: &testTimestamp},
+				Count{Name: "cache.misses", Value: 17, Timestamp: &testTimestamp},
+				Summary{Name: "service.response", Value: SummaryValue{Count: 5, Sum: 0.004382655, Min: 0.0005093, Max: 0.001708826}, Timestamp: &testTimestamp},
+			},
+		},
+	}
+
+	expected, err := json.Marshal(blocks)
+	require.NoError(t, err)
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, testInsertKey, r.Header.Get("Api-Key"))
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		assert.JSONEq(t, string(expected), string(readBody(t, r)))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"requestId":"a4bb2a9b-0001-b000-0000-0175e4a0c1f1"}`))
+	}))
+
+	err = client.CreateMetrics(blocks)
+	assert.NoError(t, err)
+}
+
+func TestCreateMetrics_invalid(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, nil)
+
+	err := client.CreateMetrics(nil)
+	assert.Error(t, err)
+
+	err = client.CreateMetrics([]MetricBlock{{}})
+	assert.Error(t, err)
+}
+
+func TestBatchMode(t *testing.T) {
+	t.Parallel()
+
+	type receivedBlock struct {
+		Common  *Common           `json:"common"`
+		Metrics []json.RawMessage `json:"metrics"`
+	}
+
+	received := make(chan []receivedBlock, 1)
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blocks := []receivedBlock{}
+		assert.NoError(t, json.Unmarshal(readBody(t, r), &blocks))
+		received <- blocks
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"requestId":"a4bb2a9b-0001-b000-0000-0175e4a0c1f1"}`))
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := client.BatchMode(ctx,
+		BatchConfigQueueSize(2),
+		BatchConfigCommon(Common{Attributes: map[string]interface{}{"service": "test"}}),
+	)
+	require.NoError(t, err)
+
+	// Already in batch mode
+	assert.Error(t, client.BatchMode(ctx))
+
+	require.NoError(t, client.EnqueueMetric(ctx, Gauge{Name: "one", Value: 1}))
+	require.NoError(t, client.EnqueueMetric(ctx, Count{Name: "two", Value: 2, IntervalMs: 1000}))
+
+	select {
+	case blocks := <-received:
+		require.Len(t, blocks, 1)
+		require.NotNil(t, blocks[0].Common)
+		assert.Equal(t, "test", blocks[0].Common.Attributes["service"])
+		assert.Len(t, blocks[0].Metrics, 2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the batch to be sent")
+	}
+
+	require.NoError(t, client.EnqueueMetric(ctx, Gauge{Name: "three", Value: 3}))
+	require.NoError(t, client.Shutdown(ctx))
+
+	select {
+	case blocks := <-received:
+		require.Len(t, blocks, 1)
+		assert.Len(t, blocks[0].Metrics, 1)
+	default:
+		t.Fatal("expected queued metrics to be sent on shutdown")
+	}
+
+	stats := client.BatchStats()
+	assert.Equal(t, int64(3), stats.Sent)
+	assert.Equal(t, int64(0), stats.Dropped)
+
+	// No longer accepting metrics
+	assert.Error(t, client.EnqueueMetric(ctx, Gauge{Name: "four", Value: 4}))
+}
+
+func TestBatchConfigOptions(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, nil)
+
+	assert.Error(t, BatchConfigWorkers(0)(&client))
+	assert.Error(t, BatchConfigQueueSize(0)(&client))
+	assert.Error(t, BatchConfigTimeout(0)(&client))
+	assert.Error(t, BatchConfigMaxInFlight(0)(&client))
+	assert.Error(t, BatchConfigRetries(-1)(&client))
+	assert.Error(t, BatchConfigFailedBatchHandler(nil)(&client))
+
+	assert.Error(t, client.EnqueueMetric(context.Background(), Gauge{}))
+	assert.Error(t, client.Flush())
+	assert.Error(t, client.Shutdown(context.Background()))
+}
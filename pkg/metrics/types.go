@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"encoding/json"
+
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+)
+
+// MetricType specifies the type of a metric reported to the Metric API.
+type MetricType string
+
+var (
+	// MetricTypes specifies the possible types of a metric.
+	MetricTypes = struct {
+		Count   MetricType
+		Gauge   MetricType
+		Summary MetricType
+	}{
+		Count:   "count",
+		Gauge:   "gauge",
+		Summary: "summary",
+	}
+)
+
+// Metric is implemented by each of the metric types accepted by the Metric API.
+type Metric interface {
+	Type() MetricType
+}
+
+// Gauge represents a single value measured at a point in time.
+type Gauge struct {
+	Name       string                    `json:"name"`
+	Value      float64                   `json:"value"`
+	Timestamp  *nrtime.EpochMilliseconds `json:"timestamp,omitempty"`
+	Attributes map[string]interface{}    `json:"attributes,omitempty"`
+}
+
+// Type returns the metric type of a Gauge.
+func (g Gauge) Type() MetricType {
+	return MetricTypes.Gauge
+}
+
+// MarshalJSON is responsible for marshaling the Gauge type.
+func (g Gauge) MarshalJSON() ([]byte, error) {
+	type gauge Gauge
+
+	return json.Marshal(struct {
+		Type MetricType `json:"type"`
+		gauge
+	}{
+		Type:  g.Type(),
+		gauge: gauge(g),
+	})
+}
+
+// Count represents the number of occurrences of an event within an interval.
+type Count struct {
+	Name       string                    `json:"name"`
+	Value      float64                   `json:"value"`
+	Timestamp  *nrtime.EpochMilliseconds `json:"timestamp,omitempty"`
+	IntervalMs int64                     `json:"interval.ms,omitempty"`
+	Attributes map[string]interface{}    `json:"attributes,omitempty"`
+}
+
+// Type returns the metric type of a Count.
+func (c Count) Type() MetricType {
+	return MetricTypes.Count
+}
+
+// MarshalJSON is responsible for marshaling the Count type.
+func (c Count) MarshalJSON() ([]byte, error) {
+	type count Count
+
+	return json.Marshal(struct {
+		Type MetricType `json:"type"`
+		count
+	}{
+		Type:  c.Type(),
+		count: count(c),
+	})
+}
+
+// Summary represents pre-aggregated data for a group of values within an interval.
+type Summary struct {
+	Name       string                    `json:"name"`
+	Value      SummaryValue              `json:"value"`
+	Timestamp  *nrtime.EpochMilliseconds `json:"timestamp,omitempty"`
+	IntervalMs int64                     `json:"interval.ms,omitempty"`
+	Attributes map[string]interface{}    `json:"attributes,omitempty"`
+}
+
+// SummaryValue holds the aggregated values of a Summary.
+type SummaryValue struct {
+	Count float64 `json:"count"`
+	Sum   float64 `json:"sum"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// Type returns the metric type of a Summary.
+func (s Summary) Type() MetricType {
+	return MetricTypes.Summary
+}
+
+// MarshalJSON is responsible for marshaling the Summary type.
+func (s Summary) MarshalJSON() ([]byte, error) {
+	type summary Summary
+
+	return json.Marshal(struct {
+		Type MetricType `json:"type"`
+		summary
+	}{
+		Type:    s.Type(),
+		summary: summary(s),
+	})
+}
+
+// Common holds the values shared by every metric within a MetricBlock.
+// Values set on an individual metric take precedence over these.
+type Common struct {
+	Timestamp  *nrtime.EpochMilliseconds `json:"timestamp,omitempty"`
+	IntervalMs int64                     `json:"interval.ms,omitempty"`
+	Attributes map[string]interface{}    `json:"attributes,omitempty"`
+}
+
+// MetricBlock represents a group of metrics, and their common values,
+// as sent to the Metric API.
+type MetricBlock struct {
+	Common  *Common  `json:"common,omitempty"`
+	Metrics []Metric `json:"metrics"`
+}
+
+type createMetricsResponse struct {
+	RequestID string `json:"requestId"`
+}
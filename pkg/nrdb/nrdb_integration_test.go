@@ -58,3 +58,27 @@ func TestIntegrationNrdbQueryHistoryQuery(t *testing.T) {
 
 	require.GreaterOrEqual(t, len(*res), 1)
 }
+
+func TestIntegrationNrdbQueryMultiAccount(t *testing.T) {
+	t.Parallel()
+
+	client := newNrdbIntegrationTestClient(t)
+
+	accountID, err := strconv.Atoi(os.Getenv("NEW_RELIC_ACCOUNT_ID"))
+	if err != nil {
+		t.Skipf("integration testing requires NEW_RELIC_ACOUNT_ID")
+	}
+
+	res, err := client.QueryMultiAccount(QueryMultiAccountParams{
+		AccountIDs: []int{accountID},
+		Query:      "SELECT 1 FROM Transaction",
+		Timeout:    30,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	require.Equal(t, 1, len(res.Results))
+	require.Equal(t, 1, len(res.Accounts))
+	assert.Equal(t, accountID, res.Accounts[0].ID)
+	assert.NotEmpty(t, res.Accounts[0].Name)
+}
@@ -1,7 +1,13 @@
 // Package nrdb provides a programmatic API for interacting with NRDB, New Relic's Datastore
 package nrdb
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
 
 func (n *Nrdb) Query(accountID int, query NRQL) (*NRDBResultContainer, error) {
 	return n.QueryWithContext(context.Background(), accountID, query)
@@ -23,6 +29,111 @@ func (n *Nrdb) QueryWithContext(ctx context.Context, accountID int, query NRQL)
 	return &respBody.Actor.Account.NRQL, nil
 }
 
+// QueryMultiAccountParams represents the input parameters for the
+// QueryMultiAccount method.
+type QueryMultiAccountParams struct {
+	// The accounts to run the query across.
+	AccountIDs []int
+	// The NRQL query to run.
+	Query NRQL
+	// The number of seconds to wait for the results, before the query times
+	// out or, for an async query, returns its progress.  Zero leaves the
+	// default of NerdGraph.
+	Timeout int
+	// Async asks for the progress of the query to be returned when it does
+	// not complete within the timeout, instead of failing.
+	Async bool
+}
+
+// NRDBQueryProgress - Information about the progress of an async query.
+type NRDBQueryProgress struct {
+	// Whether the query has completed, its results being available.
+	Completed bool `json:"completed"`
+	// The ID of the query, used to retrieve its results.
+	QueryID string `json:"queryId,omitempty"`
+	// The number of seconds the results are kept once the query has completed.
+	ResultExpiration int `json:"resultExpiration,omitempty"`
+	// The number of seconds to wait before polling for the results.
+	RetryAfter int `json:"retryAfter,omitempty"`
+	// The number of seconds the results can be polled for.
+	RetryDeadline int `json:"retryDeadline,omitempty"`
+}
+
+// NRDBAccountMetadata - Information about an account a query was run across.
+type NRDBAccountMetadata struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// NRDBMultiAccountResultContainer - The results of a NRQL query run across
+// multiple accounts, along with the progress of the query when async and the
+// accounts it was run across.
+type NRDBMultiAccountResultContainer struct {
+	NRDBResultContainer
+	// The progress of the query, returned for async queries.
+	QueryProgress *NRDBQueryProgress `json:"queryProgress,omitempty"`
+	// The accounts the query was run across, in the order given.
+	Accounts []NRDBAccountMetadata `json:"-"`
+}
+
+// QueryMultiAccount runs a NRQL query across multiple accounts.
+func (n *Nrdb) QueryMultiAccount(params QueryMultiAccountParams) (*NRDBMultiAccountResultContainer, error) {
+	return n.QueryMultiAccountWithContext(context.Background(), params)
+}
+
+// QueryMultiAccountWithContext runs a NRQL query across multiple accounts.
+func (n *Nrdb) QueryMultiAccountWithContext(ctx context.Context, params QueryMultiAccountParams) (*NRDBMultiAccountResultContainer, error) {
+	if len(params.AccountIDs) == 0 {
+		return nil, errors.New("at least one account ID is required")
+	}
+
+	respBody := gqlNrqlMultiAccountQueryResponse{}
+
+	vars := map[string]interface{}{
+		"accountIds": params.AccountIDs,
+		"query":      params.Query,
+		"async":      params.Async,
+	}
+
+	if params.Timeout > 0 {
+		vars["timeout"] = params.Timeout
+	}
+
+	if err := n.client.NerdGraphQueryWithContext(ctx, multiAccountNrqlQuery(params.AccountIDs), vars, &respBody); err != nil {
+		return nil, err
+	}
+
+	result := NRDBMultiAccountResultContainer{}
+	if err := json.Unmarshal(respBody.Actor["nrql"], &result); err != nil {
+		return nil, err
+	}
+
+	for i, id := range params.AccountIDs {
+		account := NRDBAccountMetadata{ID: id}
+
+		if data, ok := respBody.Actor[fmt.Sprintf("account%d", i)]; ok && string(data) != "null" {
+			if err := json.Unmarshal(data, &account); err != nil {
+				return nil, err
+			}
+		}
+
+		result.Accounts = append(result.Accounts, account)
+	}
+
+	return &result, nil
+}
+
+// multiAccountNrqlQuery returns the query running a NRQL query across the
+// given accounts, along with an aliased lookup of each of them.
+func multiAccountNrqlQuery(accountIDs []int) string {
+	var accounts strings.Builder
+	for i, id := range accountIDs {
+		fmt.Fprintf(&accounts, " account%d: account(id: %d) { id name }", i, id)
+	}
+
+	return fmt.Sprintf(gqlNrqlMultiAccountQuery, accounts.String())
+}
+
 func (n *Nrdb) QueryHistory() (*[]NRQLHistoricalQuery, error) {
 	return n.QueryHistoryWithContext(context.Background())
 }
@@ -45,6 +156,13 @@ const (
     currentResults otherResult previousResults results totalResult
     metadata { eventTypes facets messages timeWindow { begin compareWith end since until } }
   } } } }`
+
+	gqlNrqlMultiAccountQuery = `query($query: Nrql!, $accountIds: [Int!]!, $timeout: Seconds, $async: Boolean) { actor {
+  nrql(accounts: $accountIds, query: $query, timeout: $timeout, async: $async) {
+    currentResults otherResult previousResults results totalResult
+    metadata { eventTypes facets messages timeWindow { begin compareWith end since until } }
+    queryProgress { completed queryId resultExpiration retryAfter retryDeadline }
+  }%s } }`
 )
 
 type gqlNrglQueryResponse struct {
@@ -55,6 +173,10 @@ type gqlNrglQueryResponse struct {
 	}
 }
 
+type gqlNrqlMultiAccountQueryResponse struct {
+	Actor map[string]json.RawMessage
+}
+
 type gqlNrglQueryHistoryResponse struct {
 	Actor struct {
 		NRQLQueryHistory []NRQLHistoricalQuery
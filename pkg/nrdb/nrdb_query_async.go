@@ -0,0 +1,203 @@
+package nrdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultAsyncQueryTimeout is the number of seconds an async query waits
+	// for its results before returning its progress, unless set otherwise.
+	DefaultAsyncQueryTimeout = 5
+	// DefaultPollInterval is the initial interval between two polls for the
+	// results of an async query, when not given by NerdGraph.
+	DefaultPollInterval = 1 * time.Second
+	// DefaultMaxPollInterval is the interval the backoff between two polls for
+	// the results of an async query is capped to.
+	DefaultMaxPollInterval = 30 * time.Second
+)
+
+// AsyncQuery is a NRQL query submitted asynchronously, whose results are
+// polled for until it completes.
+type AsyncQuery struct {
+	// PollInterval is the initial interval between two polls, doubled after
+	// each poll, used when NerdGraph does not tell when to poll again.
+	// DefaultPollInterval is used when not set.
+	PollInterval time.Duration
+	// MaxPollInterval caps the interval between two polls.
+	MaxPollInterval time.Duration
+
+	nrdb       *Nrdb
+	accountIDs []int
+	progress   NRDBQueryProgress
+	// updated is when the progress was last received, from which its retry
+	// deadline is counted.
+	updated time.Time
+	result     *NRDBResultContainer
+}
+
+// SubmitAsyncQuery submits a NRQL query across one or more accounts, without
+// waiting for it to complete.
+func (n *Nrdb) SubmitAsyncQuery(params QueryMultiAccountParams) (*AsyncQuery, error) {
+	return n.SubmitAsyncQueryWithContext(context.Background(), params)
+}
+
+// SubmitAsyncQueryWithContext submits a NRQL query across one or more
+// accounts, without waiting for it to complete.  The query waits for its
+// results for the timeout given, DefaultAsyncQueryTimeout if none, and the
+// results are polled for with Wait if it has not completed by then.
+func (n *Nrdb) SubmitAsyncQueryWithContext(ctx context.Context, params QueryMultiAccountParams) (*AsyncQuery, error) {
+	params.Async = true
+	if params.Timeout <= 0 {
+		params.Timeout = DefaultAsyncQueryTimeout
+	}
+
+	res, err := n.QueryMultiAccountWithContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &AsyncQuery{
+		PollInterval:    DefaultPollInterval,
+		MaxPollInterval: DefaultMaxPollInterval,
+		nrdb:            n,
+		accountIDs:      params.AccountIDs,
+	}
+	q.update(&res.NRDBResultContainer, res.QueryProgress)
+
+	return q, nil
+}
+
+// QueryID returns the ID of the query, empty when it completed on submission.
+func (q *AsyncQuery) QueryID() string {
+	return q.progress.QueryID
+}
+
+// Completed returns whether the query has completed, as last polled.
+func (q *AsyncQuery) Completed() bool {
+	return q.result != nil
+}
+
+// Poll retrieves the progress of the query once, returning whether it has
+// completed.
+func (q *AsyncQuery) Poll(ctx context.Context) (bool, error) {
+	if q.Completed() {
+		return true, nil
+	}
+
+	respBody := gqlNrqlQueryProgressResponse{}
+
+	vars := map[string]interface{}{
+		"accountIds": q.accountIDs,
+		"queryId":    q.progress.QueryID,
+	}
+
+	if err := q.nrdb.client.NerdGraphQueryWithContext(ctx, gqlNrqlQueryProgressQuery, vars, &respBody); err != nil {
+		return false, err
+	}
+
+	res := respBody.Actor.NRQLQueryProgress
+	q.update(&res.NRDBResultContainer, res.QueryProgress)
+
+	return q.Completed(), nil
+}
+
+// Wait polls for the progress of the query with backoff until it completes,
+// returning its results, or until the context is done or NerdGraph no longer
+// keeps the query.  The query is polled a last time at the retry deadline of
+// its progress, should the next poll come after it.
+func (q *AsyncQuery) Wait(ctx context.Context) (*NRDBResultContainer, error) {
+	interval := q.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	for !q.Completed() {
+		wait := interval
+		if q.progress.RetryAfter > 0 {
+			wait = time.Duration(q.progress.RetryAfter) * time.Second
+		}
+
+		if q.MaxPollInterval > 0 && wait > q.MaxPollInterval {
+			wait = q.MaxPollInterval
+		}
+
+		if q.progress.RetryDeadline > 0 {
+			left := time.Until(q.updated.Add(time.Duration(q.progress.RetryDeadline) * time.Second))
+			if left <= 0 {
+				return nil, fmt.Errorf("async query %s did not complete within %d seconds", q.progress.QueryID, q.progress.RetryDeadline)
+			}
+
+			if wait > left {
+				wait = left
+			}
+		}
+
+		q.nrdb.logger.Trace(fmt.Sprintf("waiting %s for async query %s", wait, q.progress.QueryID))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if _, err := q.Poll(ctx); err != nil {
+			return nil, err
+		}
+
+		interval *= 2
+	}
+
+	return q.result, nil
+}
+
+func (q *AsyncQuery) update(res *NRDBResultContainer, progress *NRDBQueryProgress) {
+	if progress == nil || progress.Completed {
+		q.result = res
+	}
+
+	if progress != nil {
+		q.progress = *progress
+	}
+
+	q.updated = time.Now()
+}
+
+// QueryAsync runs a NRQL query asynchronously, waiting for it to complete.
+// It suits long-running queries which time out with Query.
+func (n *Nrdb) QueryAsync(accountID int, query NRQL) (*NRDBResultContainer, error) {
+	return n.QueryAsyncWithContext(context.Background(), accountID, query)
+}
+
+// QueryAsyncWithContext runs a NRQL query asynchronously, polling for its
+// results until it completes or the context is done.  It suits long-running
+// queries which time out with QueryWithContext.
+func (n *Nrdb) QueryAsyncWithContext(ctx context.Context, accountID int, query NRQL) (*NRDBResultContainer, error) {
+	q, err := n.SubmitAsyncQueryWithContext(ctx, QueryMultiAccountParams{
+		AccountIDs: []int{accountID},
+		Query:      query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Wait(ctx)
+}
+
+const (
+	gqlNrqlQueryProgressQuery = `query($accountIds: [Int!]!, $queryId: ID!) { actor {
+  nrqlQueryProgress(accounts: $accountIds, queryId: $queryId) {
+    currentResults otherResult previousResults results totalResult
+    metadata { eventTypes facets messages timeWindow { begin compareWith end since until } }
+    queryProgress { completed queryId resultExpiration retryAfter retryDeadline }
+  } } }`
+)
+
+type gqlNrqlQueryProgressResponse struct {
+	Actor struct {
+		NRQLQueryProgress NRDBMultiAccountResultContainer
+	}
+}
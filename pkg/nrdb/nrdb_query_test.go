@@ -0,0 +1,265 @@
+// +build unit
+
+package nrdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mock "github.com/newrelic/newrelic-client-go/pkg/testhelpers"
+)
+
+var testQueryMultiAccountResponseJSON = `{
+	"data": {
+		"actor": {
+			"nrql": {
+				"results": [{"count": 42}],
+				"metadata": {"eventTypes": ["Transaction"], "messages": []},
+				"queryProgress": null
+			},
+			"account0": {"id": 1, "name": "one"},
+			"account1": null
+		}
+	}
+}`
+
+func newTestClient(t *testing.T, handler http.Handler) Nrdb {
+	ts := httptest.NewServer(handler)
+	tc := mock.NewTestConfig(t, ts)
+
+	return New(tc)
+}
+
+func TestQueryMultiAccount(t *testing.T) {
+	t.Parallel()
+
+	var req struct {
+		Query     string
+		Variables map[string]interface{}
+	}
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(testQueryMultiAccountResponseJSON))
+		require.NoError(t, err)
+	}))
+
+	res, err := client.QueryMultiAccount(QueryMultiAccountParams{
+		AccountIDs: []int{1, 2},
+		Query:      "SELECT count(*) FROM Transaction",
+		Timeout:    10,
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, req.Query, "account0: account(id: 1) { id name }")
+	assert.Contains(t, req.Query, "account1: account(id: 2) { id name }")
+	assert.Equal(t, []interface{}{float64(1), float64(2)}, req.Variables["accountIds"])
+	assert.Equal(t, float64(10), req.Variables["timeout"])
+	assert.Equal(t, false, req.Variables["async"])
+
+	assert.Equal(t, []NRDBResult{{"count": float64(42)}}, res.Results)
+	assert.Equal(t, []string{"Transaction"}, res.Metadata.EventTypes)
+	assert.Nil(t, res.QueryProgress)
+	assert.Equal(t, []NRDBAccountMetadata{{ID: 1, Name: "one"}, {ID: 2}}, res.Accounts)
+}
+
+func TestQueryMultiAccount_async(t *testing.T) {
+	t.Parallel()
+
+	var variables map[string]interface{}
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Variables map[string]interface{} }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		variables = req.Variables
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data": {"actor": {
+			"nrql": {"results": null, "queryProgress": {"completed": false, "queryId": "abc", "retryAfter": 5, "retryDeadline": 600}},
+			"account0": {"id": 1, "name": "one"}
+		}}}`))
+		require.NoError(t, err)
+	}))
+
+	res, err := client.QueryMultiAccount(QueryMultiAccountParams{
+		AccountIDs: []int{1},
+		Query:      "SELECT count(*) FROM Transaction",
+		Async:      true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, true, variables["async"])
+	assert.NotContains(t, variables, "timeout")
+	assert.Empty(t, res.Results)
+	require.NotNil(t, res.QueryProgress)
+	assert.Equal(t, NRDBQueryProgress{QueryID: "abc", RetryAfter: 5, RetryDeadline: 600}, *res.QueryProgress)
+}
+
+func TestQueryMultiAccount_noAccounts(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request should be made")
+	}))
+
+	_, err := client.QueryMultiAccount(QueryMultiAccountParams{Query: "SELECT 1 FROM Transaction"})
+	assert.Error(t, err)
+}
+
+func newAsyncQueryTestClient(t *testing.T, polls int32) (Nrdb, *int32) {
+	var requests int32
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		n := atomic.AddInt32(&requests, 1)
+		if n == 1 {
+			assert.Equal(t, true, req.Variables["async"])
+			assert.Equal(t, float64(DefaultAsyncQueryTimeout), req.Variables["timeout"])
+		} else {
+			assert.Contains(t, req.Query, "nrqlQueryProgress")
+			assert.Equal(t, "abc", req.Variables["queryId"])
+			assert.Equal(t, []interface{}{float64(1)}, req.Variables["accountIds"])
+		}
+
+		nrql := `{"results": null, "queryProgress": {"completed": false, "queryId": "abc", "retryDeadline": 600}}`
+		if n > polls {
+			nrql = `{"results": [{"count": 42}], "queryProgress": {"completed": true, "queryId": "abc"}}`
+		}
+
+		field := "nrqlQueryProgress"
+		if n == 1 {
+			field = "nrql"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data": {"actor": {"` + field + `": ` + nrql + `}}}`))
+		require.NoError(t, err)
+	}))
+
+	return client, &requests
+}
+
+func TestSubmitAsyncQuery_wait(t *testing.T) {
+	t.Parallel()
+	client, requests := newAsyncQueryTestClient(t, 3)
+
+	q, err := client.SubmitAsyncQuery(QueryMultiAccountParams{
+		AccountIDs: []int{1},
+		Query:      "SELECT count(*) FROM Transaction",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", q.QueryID())
+	assert.False(t, q.Completed())
+
+	q.PollInterval = time.Millisecond
+	q.MaxPollInterval = 2 * time.Millisecond
+
+	res, err := q.Wait(context.Background())
+	require.NoError(t, err)
+	assert.True(t, q.Completed())
+	assert.Equal(t, []NRDBResult{{"count": float64(42)}}, res.Results)
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+}
+
+func TestAsyncQuery_waitDeadline(t *testing.T) {
+	t.Parallel()
+
+	// Each progress gives one more second to poll, counted from its response.
+	var requests int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nrql := `{"results": null, "queryProgress": {"completed": false, "queryId": "abc", "retryDeadline": 1}}`
+		field := "nrqlQueryProgress"
+
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			field = "nrql"
+		case 3:
+			nrql = `{"results": [{"count": 42}], "queryProgress": {"completed": true, "queryId": "abc"}}`
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data": {"actor": {"` + field + `": ` + nrql + `}}}`))
+		require.NoError(t, err)
+	}))
+
+	q, err := client.SubmitAsyncQuery(QueryMultiAccountParams{
+		AccountIDs: []int{1},
+		Query:      "SELECT count(*) FROM Transaction",
+	})
+	require.NoError(t, err)
+
+	// The polls are brought forward to the deadline of each progress.
+	q.PollInterval = time.Minute
+	q.MaxPollInterval = time.Minute
+
+	start := time.Now()
+	res, err := q.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []NRDBResult{{"count": float64(42)}}, res.Results)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.Less(t, int64(time.Since(start)), int64(3*time.Second))
+}
+
+func TestAsyncQuery_waitZeroPollInterval(t *testing.T) {
+	t.Parallel()
+	client, requests := newAsyncQueryTestClient(t, 3)
+
+	q, err := client.SubmitAsyncQuery(QueryMultiAccountParams{
+		AccountIDs: []int{1},
+		Query:      "SELECT count(*) FROM Transaction",
+	})
+	require.NoError(t, err)
+
+	// The interval defaults to DefaultPollInterval, capped here.
+	q.PollInterval = 0
+	q.MaxPollInterval = 10 * time.Millisecond
+
+	start := time.Now()
+	_, err = q.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(3*q.MaxPollInterval))
+}
+
+func TestQueryAsync_completedOnSubmit(t *testing.T) {
+	t.Parallel()
+	client, requests := newAsyncQueryTestClient(t, 0)
+
+	res, err := client.QueryAsync(1, "SELECT count(*) FROM Transaction")
+	require.NoError(t, err)
+	assert.Equal(t, []NRDBResult{{"count": float64(42)}}, res.Results)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestAsyncQuery_waitCanceled(t *testing.T) {
+	t.Parallel()
+	client, _ := newAsyncQueryTestClient(t, 100)
+
+	q, err := client.SubmitAsyncQuery(QueryMultiAccountParams{
+		AccountIDs: []int{1},
+		Query:      "SELECT count(*) FROM Transaction",
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = q.Wait(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.False(t, q.Completed())
+}
@@ -1,11 +1,24 @@
 // Code generated by tutone: DO NOT EDIT
 package nrqldroprules
 
+import (
+	"context"
+)
+
 // Create new drop rule(s).
 func (a *Nrqldroprules) NRQLDropRulesCreate(
 	accountID int,
 	rules []NRQLDropRulesCreateDropRuleInput,
 ) (*NRQLDropRulesCreateDropRuleResult, error) {
+	return a.NRQLDropRulesCreateWithContext(context.Background(), accountID, rules)
+}
+
+// Create new drop rule(s).
+func (a *Nrqldroprules) NRQLDropRulesCreateWithContext(
+	ctx context.Context,
+	accountID int,
+	rules []NRQLDropRulesCreateDropRuleInput,
+) (*NRQLDropRulesCreateDropRuleResult, error) {
 
 	resp := NRQLDropRulesCreateQueryResponse{}
 	vars := map[string]interface{}{
@@ -13,7 +26,7 @@ func (a *Nrqldroprules) NRQLDropRulesCreate(
 		"rules":     rules,
 	}
 
-	if err := a.client.NerdGraphQuery(NRQLDropRulesCreateMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, NRQLDropRulesCreateMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -47,6 +60,15 @@ func (a *Nrqldroprules) NRQLDropRulesDelete(
 	accountID int,
 	ruleIds []string,
 ) (*NRQLDropRulesDeleteDropRuleResult, error) {
+	return a.NRQLDropRulesDeleteWithContext(context.Background(), accountID, ruleIds)
+}
+
+// Delete drop rule(s) by id.
+func (a *Nrqldroprules) NRQLDropRulesDeleteWithContext(
+	ctx context.Context,
+	accountID int,
+	ruleIds []string,
+) (*NRQLDropRulesDeleteDropRuleResult, error) {
 
 	resp := NRQLDropRulesDeleteQueryResponse{}
 	vars := map[string]interface{}{
@@ -54,7 +76,7 @@ func (a *Nrqldroprules) NRQLDropRulesDelete(
 		"ruleIds":   ruleIds,
 	}
 
-	if err := a.client.NerdGraphQuery(NRQLDropRulesDeleteMutation, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, NRQLDropRulesDeleteMutation, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -87,13 +109,21 @@ const NRQLDropRulesDeleteMutation = `mutation(
 func (a *Nrqldroprules) GetList(
 	accountID int,
 ) (*NRQLDropRulesListDropRulesResult, error) {
+	return a.GetListWithContext(context.Background(), accountID)
+}
+
+// List the drop rules for the given account
+func (a *Nrqldroprules) GetListWithContext(
+	ctx context.Context,
+	accountID int,
+) (*NRQLDropRulesListDropRulesResult, error) {
 
 	resp := listResponse{}
 	vars := map[string]interface{}{
 		"accountID": accountID,
 	}
 
-	if err := a.client.NerdGraphQuery(getListQuery, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, getListQuery, vars, &resp); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,32 @@
+// Package paging provides iterators over the results of the paginated
+// New Relic APIs.
+//
+// Iterators fetch pages as their items are read, so that large result sets
+// do not need to be held in memory:
+//
+//	it := client.Alerts.ListPoliciesIterator(nil)
+//	for it.Next() {
+//		policy := it.Value().(alerts.Policy)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+package paging
+
+import (
+	"github.com/newrelic/newrelic-client-go/internal/http"
+)
+
+// Iterator streams the items of a paginated API.  Next advances to the next
+// item, read with Value, while NextPage advances a whole page at a time, read
+// with Page.  Once either returns false, Err reports whether the iteration
+// completed or failed.
+type Iterator = http.Iterator
+
+// Option configures an Iterator.
+type Option = http.IteratorOption
+
+// PageSize requests pages of the given size, for the APIs that allow it.
+func PageSize(size int) Option {
+	return http.IteratorPageSize(size)
+}
@@ -0,0 +1,109 @@
+package region
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Endpoints holds the base URLs of the New Relic APIs within a region.
+type Endpoints struct {
+	Infrastructure string
+	Insights       string
+	Logs           string
+	Metrics        string
+	NerdGraph      string
+	Rest           string
+	Synthetics     string
+	Traces         string
+}
+
+// New returns a custom region of the given name, such as a proxy or a
+// dedicated deployment of New Relic, with the base URLs given.  The base URLs
+// must be absolute HTTP or HTTPS URLs, the ones of the APIs not used being
+// left empty.
+//
+// Regions are not registered in Regions, so that clients configured with
+// different custom regions do not interfere.
+func New(name string, endpoints Endpoints) (*Region, error) {
+	if name == "" {
+		return nil, InvalidError{Message: "name can not be empty"}
+	}
+
+	if err := endpoints.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Region{
+		name:                  name,
+		infrastructureBaseURL: endpoints.Infrastructure,
+		insightsBaseURL:       endpoints.Insights,
+		logsBaseURL:           endpoints.Logs,
+		metricsBaseURL:        endpoints.Metrics,
+		nerdGraphBaseURL:      endpoints.NerdGraph,
+		restBaseURL:           endpoints.Rest,
+		syntheticsBaseURL:     endpoints.Synthetics,
+		tracesBaseURL:         endpoints.Traces,
+	}, nil
+}
+
+// Endpoints returns the base URLs of the region.
+func (r *Region) Endpoints() Endpoints {
+	return Endpoints{
+		Infrastructure: r.infrastructureBaseURL,
+		Insights:       r.insightsBaseURL,
+		Logs:           r.logsBaseURL,
+		Metrics:        r.metricsBaseURL,
+		NerdGraph:      r.nerdGraphBaseURL,
+		Rest:           r.restBaseURL,
+		Synthetics:     r.syntheticsBaseURL,
+		Traces:         r.tracesBaseURL,
+	}
+}
+
+// WithEndpoints returns a copy of the region with the base URLs overridden
+// by the ones given which are not empty, leaving the region untouched.
+func (r *Region) WithEndpoints(overrides Endpoints) (*Region, error) {
+	if r == nil {
+		return nil, ErrorNil()
+	}
+
+	if err := overrides.validate(); err != nil {
+		return nil, err
+	}
+
+	reg := *r
+	reg.SetInfrastructureBaseURL(overrides.Infrastructure)
+	reg.SetInsightsBaseURL(overrides.Insights)
+	reg.SetLogsBaseURL(overrides.Logs)
+	reg.SetMetricsBaseURL(overrides.Metrics)
+	reg.SetNerdGraphBaseURL(overrides.NerdGraph)
+	reg.SetRestBaseURL(overrides.Rest)
+	reg.SetSyntheticsBaseURL(overrides.Synthetics)
+	reg.SetTracesBaseURL(overrides.Traces)
+
+	return &reg, nil
+}
+
+func (e Endpoints) validate() error {
+	for api, u := range map[string]string{
+		"infrastructure": e.Infrastructure,
+		"insights":       e.Insights,
+		"logs":           e.Logs,
+		"metrics":        e.Metrics,
+		"nerdgraph":      e.NerdGraph,
+		"rest":           e.Rest,
+		"synthetics":     e.Synthetics,
+		"traces":         e.Traces,
+	} {
+		if u == "" {
+			continue
+		}
+
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return InvalidError{Message: fmt.Sprintf("%s base URL %q must be an absolute HTTP or HTTPS URL", api, u)}
+		}
+	}
+
+	return nil
+}
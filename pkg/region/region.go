@@ -22,9 +22,11 @@ type Region struct {
 	infrastructureBaseURL string
 	insightsBaseURL       string
 	logsBaseURL           string
+	metricsBaseURL        string
 	nerdGraphBaseURL      string
 	restBaseURL           string
 	syntheticsBaseURL     string
+	tracesBaseURL         string
 }
 
 // String returns a human readable value for the specified Region Name
@@ -209,3 +211,45 @@ func (r *Region) LogsURL() string {
 
 	return r.logsBaseURL
 }
+
+//
+// Metrics
+//
+
+// SetMetricsBaseURL Allows overriding the Metric API Base URL
+func (r *Region) SetMetricsBaseURL(url string) {
+	if r != nil && url != "" {
+		r.metricsBaseURL = url
+	}
+}
+
+// MetricsURL returns the Full URL for the Metric API
+func (r *Region) MetricsURL() string {
+	if r == nil {
+		log.Errorf("call to nil region.MetricsURL")
+		return ""
+	}
+
+	return r.metricsBaseURL
+}
+
+//
+// Traces
+//
+
+// SetTracesBaseURL Allows overriding the Trace API Base URL
+func (r *Region) SetTracesBaseURL(url string) {
+	if r != nil && url != "" {
+		r.tracesBaseURL = url
+	}
+}
+
+// TracesURL returns the Full URL for the Trace API
+func (r *Region) TracesURL() string {
+	if r == nil {
+		log.Errorf("call to nil region.TracesURL")
+		return ""
+	}
+
+	return r.tracesBaseURL
+}
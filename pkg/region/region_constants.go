@@ -17,6 +17,10 @@ const (
 
 	// Local represents a local development environment.
 	Local Name = "Local"
+
+	// FedRAMP represents New Relic's FedRAMP endpoints for US government
+	// customers.
+	FedRAMP Name = "FedRAMP"
 )
 
 // Regions defines the service URLs that make up the various environments.
@@ -26,36 +30,55 @@ var Regions = map[Name]*Region{
 		infrastructureBaseURL: "https://infra-api.newrelic.com/v2",
 		insightsBaseURL:       "https://insights-collector.newrelic.com/v1",
 		logsBaseURL:           "https://log-api.newrelic.com/log/v1",
+		metricsBaseURL:        "https://metric-api.newrelic.com/metric/v1",
 		nerdGraphBaseURL:      "https://api.newrelic.com/graphql",
 		restBaseURL:           "https://api.newrelic.com/v2",
 		syntheticsBaseURL:     "https://synthetics.newrelic.com/synthetics/api",
+		tracesBaseURL:         "https://trace-api.newrelic.com/trace/v1",
 	},
 	EU: {
 		name:                  "EU",
 		infrastructureBaseURL: "https://infra-api.eu.newrelic.com/v2",
 		insightsBaseURL:       "https://insights-collector.eu01.nr-data.net/v1",
 		logsBaseURL:           "https://log-api.eu.newrelic.com/log/v1",
+		metricsBaseURL:        "https://metric-api.eu.newrelic.com/metric/v1",
 		nerdGraphBaseURL:      "https://api.eu.newrelic.com/graphql",
 		restBaseURL:           "https://api.eu.newrelic.com/v2",
 		syntheticsBaseURL:     "https://synthetics.eu.newrelic.com/synthetics/api",
+		tracesBaseURL:         "https://trace-api.eu.newrelic.com/trace/v1",
 	},
 	Staging: {
 		name:                  "Staging",
 		infrastructureBaseURL: "https://staging-infra-api.newrelic.com/v2",
 		insightsBaseURL:       "https://staging-insights-collector.newrelic.com/v1",
 		logsBaseURL:           "https://staging-log-api.newrelic.com/log/v1",
+		metricsBaseURL:        "https://staging-metric-api.newrelic.com/metric/v1",
 		nerdGraphBaseURL:      "https://staging-api.newrelic.com/graphql",
 		restBaseURL:           "https://staging-api.newrelic.com/v2",
 		syntheticsBaseURL:     "https://staging-synthetics.newrelic.com/synthetics/api",
+		tracesBaseURL:         "https://staging-trace-api.newrelic.com/trace/v1",
+	},
+	FedRAMP: {
+		name:                  "FedRAMP",
+		infrastructureBaseURL: "https://gov-infra-api.newrelic.com/v2",
+		insightsBaseURL:       "https://gov-insights-collector.newrelic.com/v1",
+		logsBaseURL:           "https://gov-log-api.newrelic.com/log/v1",
+		metricsBaseURL:        "https://gov-metric-api.newrelic.com/metric/v1",
+		nerdGraphBaseURL:      "https://gov-api.newrelic.com/graphql",
+		restBaseURL:           "https://gov-api.newrelic.com/v2",
+		syntheticsBaseURL:     "https://gov-synthetics.newrelic.com/synthetics/api",
+		tracesBaseURL:         "https://gov-trace-api.newrelic.com/trace/v1",
 	},
 	Local: {
 		name:                  "Local",
 		infrastructureBaseURL: "http://localhost:3000/v2",
 		insightsBaseURL:       "http://localhost:3000/v1",
 		logsBaseURL:           "http://localhost:3000/log/v1",
+		metricsBaseURL:        "http://localhost:3000/metric/v1",
 		nerdGraphBaseURL:      "http://localhost:3000/graphql",
 		restBaseURL:           "http://localhost:3000/v2",
 		syntheticsBaseURL:     "http://localhost:3000/synthetics/api",
+		tracesBaseURL:         "http://localhost:3000/trace/v1",
 	},
 }
 
@@ -73,6 +96,8 @@ func Parse(r string) (Name, error) {
 		return Staging, nil
 	case "local":
 		return Local, nil
+	case "fedramp", "gov":
+		return FedRAMP, nil
 	default:
 		return "", UnknownError{Message: r}
 	}
@@ -84,5 +109,6 @@ func Get(r Name) (*Region, error) {
 		return &ret, nil
 	}
 
-	return Regions[Default], UnknownUsingDefaultError{Message: r.String()}
+	ret := *Regions[Default]
+	return &ret, UnknownUsingDefaultError{Message: r.String()}
 }
@@ -26,6 +26,9 @@ func TestParse(t *testing.T) {
 		"local":   Local,
 		"Local":   Local,
 		"LOCAL":   Local,
+		"fedramp": FedRAMP,
+		"FedRAMP": FedRAMP,
+		"gov":     FedRAMP,
 	}
 
 	for k, v := range pairs {
@@ -48,6 +51,7 @@ func TestRegionGet(t *testing.T) {
 		US:      Regions[US],
 		EU:      Regions[EU],
 		Staging: Regions[Staging],
+		FedRAMP: Regions[FedRAMP],
 	}
 
 	for k, v := range pairs {
@@ -62,6 +66,50 @@ func TestRegionGet(t *testing.T) {
 	assert.Error(t, err)
 	assert.IsType(t, UnknownUsingDefaultError{}, err)
 	assert.Equal(t, Regions[Default], result)
+	assert.NotSame(t, Regions[Default], result)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	reg, err := New("custom", Endpoints{
+		NerdGraph: "https://proxy.example.com/graphql",
+		Rest:      "https://proxy.example.com/v2",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", reg.String())
+	assert.Equal(t, "https://proxy.example.com/graphql", reg.NerdGraphURL())
+	assert.Equal(t, "https://proxy.example.com/v2/path", reg.RestURL("path"))
+	assert.Equal(t, Endpoints{
+		NerdGraph: "https://proxy.example.com/graphql",
+		Rest:      "https://proxy.example.com/v2",
+	}, reg.Endpoints())
+
+	_, err = New("", Endpoints{})
+	assert.IsType(t, InvalidError{}, err)
+
+	for _, u := range []string{"proxy.example.com/v2", "ftp://proxy.example.com", "https://", "://"} {
+		_, err = New("custom", Endpoints{Rest: u})
+		assert.IsType(t, InvalidError{}, err, u)
+	}
+}
+
+func TestWithEndpoints(t *testing.T) {
+	t.Parallel()
+
+	us, _ := Get(US)
+	reg, err := us.WithEndpoints(Endpoints{Rest: "http://localhost/v2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost/v2", reg.RestURL())
+	assert.Equal(t, us.NerdGraphURL(), reg.NerdGraphURL())
+
+	// The original region is left untouched
+	assert.Equal(t, Regions[US], us)
+
+	_, err = us.WithEndpoints(Endpoints{NerdGraph: "localhost"})
+	assert.Error(t, err)
 }
 
 func TestRegionString(t *testing.T) {
@@ -130,6 +178,21 @@ func TestLogsURLs(t *testing.T) {
 	}
 }
 
+func TestMetricsURLs(t *testing.T) {
+	t.Parallel()
+
+	pairs := map[Name]string{
+		US:      "https://metric-api.newrelic.com/metric/v1",
+		EU:      "https://metric-api.eu.newrelic.com/metric/v1",
+		Staging: "https://staging-metric-api.newrelic.com/metric/v1",
+		Local:   "http://localhost:3000/metric/v1",
+	}
+
+	for k, v := range pairs {
+		assert.Equal(t, v, Regions[k].MetricsURL())
+	}
+}
+
 func TestNerdgraphURLs(t *testing.T) {
 	t.Parallel()
 
@@ -160,6 +223,21 @@ func TestRESTURLs(t *testing.T) {
 	}
 }
 
+func TestTracesURLs(t *testing.T) {
+	t.Parallel()
+
+	pairs := map[Name]string{
+		US:      "https://trace-api.newrelic.com/trace/v1",
+		EU:      "https://trace-api.eu.newrelic.com/trace/v1",
+		Staging: "https://staging-trace-api.newrelic.com/trace/v1",
+		Local:   "http://localhost:3000/trace/v1",
+	}
+
+	for k, v := range pairs {
+		assert.Equal(t, v, Regions[k].TracesURL())
+	}
+}
+
 func TestConcatURLPaths(t *testing.T) {
 	t.Parallel()
 
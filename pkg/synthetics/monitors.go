@@ -1,7 +1,12 @@
 package synthetics
 
 import (
+	"context"
 	"path"
+	"strconv"
+
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 const (
@@ -79,18 +84,63 @@ var (
 
 // ListMonitors is used to retrieve New Relic Synthetics monitors.
 func (s *Synthetics) ListMonitors() ([]*Monitor, error) {
-	resp := listMonitorsResponse{}
-	queryParams := listMonitorsParams{
-		Limit: listMonitorsLimit,
-	}
+	monitors := []*Monitor{}
 
-	_, err := s.client.Get(s.config.Region().SyntheticsURL("/v4/monitors"), &queryParams, &resp)
+	it := s.ListMonitorsIterator()
+	for it.Next() {
+		monitors = append(monitors, it.Value().(*Monitor))
+	}
 
-	if err != nil {
+	if err := it.Err(); err != nil {
 		return nil, err
 	}
 
-	return resp.Monitors, nil
+	return monitors, nil
+}
+
+// ListMonitorsIterator returns an iterator over the New Relic Synthetics monitors.
+func (s *Synthetics) ListMonitorsIterator(opts ...paging.Option) *paging.Iterator {
+	return s.ListMonitorsIteratorWithContext(context.Background(), opts...)
+}
+
+// ListMonitorsIteratorWithContext returns an iterator over the New Relic Synthetics monitors.
+func (s *Synthetics) ListMonitorsIteratorWithContext(ctx context.Context, opts ...paging.Option) *paging.Iterator {
+	fetch := func(ctx context.Context, cursor string, pageSize int) ([]interface{}, string, error) {
+		queryParams := listMonitorsParams{
+			Limit: listMonitorsLimit,
+		}
+
+		if pageSize > 0 {
+			queryParams.Limit = pageSize
+		}
+
+		if cursor != "" {
+			offset, err := strconv.Atoi(cursor)
+			if err != nil {
+				return nil, "", err
+			}
+
+			queryParams.Offset = offset
+		}
+
+		resp := listMonitorsResponse{}
+
+		_, err := s.client.GetWithContext(ctx, s.config.Region().SyntheticsURL("/v4/monitors"), &queryParams, &resp)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// The monitors are paginated by offset, the last page being the
+		// first one not full or reaching the total count.
+		next := queryParams.Offset + len(resp.Monitors)
+		if len(resp.Monitors) < queryParams.Limit || (resp.Count > 0 && next >= resp.Count) {
+			return http.Items(resp.Monitors), "", nil
+		}
+
+		return http.Items(resp.Monitors), strconv.Itoa(next), nil
+	}
+
+	return http.NewIterator(ctx, fetch, opts...)
 }
 
 // GetMonitor is used to retrieve a specific New Relic Synthetics monitor.
@@ -146,8 +196,10 @@ func (s *Synthetics) DeleteMonitor(monitorID string) error {
 
 type listMonitorsResponse struct {
 	Monitors []*Monitor `json:"monitors,omitempty"`
+	Count    int        `json:"count,omitempty"`
 }
 
 type listMonitorsParams struct {
-	Limit int `url:"limit,omitempty"`
+	Offset int `url:"offset,omitempty"`
+	Limit  int `url:"limit,omitempty"`
 }
@@ -3,12 +3,17 @@
 package synthetics
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
 )
 
 var (
@@ -78,6 +83,55 @@ func TestListMonitors(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestListMonitorsIterator(t *testing.T) {
+	t.Parallel()
+
+	offsets := []string{}
+	synthetics := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("limit"))
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(fmt.Sprintf(`{ "monitors": [%s], "count": 2 }`, testMonitorJson)))
+		require.NoError(t, err)
+	}))
+
+	it := synthetics.ListMonitorsIterator(paging.PageSize(1))
+
+	count := 0
+	for it.Next() {
+		assert.Equal(t, &testMonitor, it.Value())
+		count++
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"", "1"}, offsets)
+}
+
+func TestListMonitorsIteratorWithContext_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	requests := 0
+	synthetics := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cancel()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(fmt.Sprintf(`{ "monitors": [%s], "count": 2 }`, testMonitorJson)))
+		require.NoError(t, err)
+	}))
+
+	it := synthetics.ListMonitorsIteratorWithContext(ctx, paging.PageSize(1))
+	for it.Next() {
+	}
+
+	assert.True(t, errors.Is(it.Err(), context.Canceled))
+	assert.Equal(t, 1, requests)
+}
+
 func TestGetMonitor(t *testing.T) {
 	t.Parallel()
 	synthetics := newMockResponse(t, testMonitorJson, http.StatusOK)
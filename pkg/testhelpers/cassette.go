@@ -0,0 +1,251 @@
+package testhelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/internal/logging"
+)
+
+// CassetteModeEnv is the environment variable selecting the cassette mode of
+// the integration tests, either "record" or "replay".  Integration tests run
+// against the live APIs when unset.
+const CassetteModeEnv = "NEW_RELIC_CASSETTE_MODE"
+
+// CassetteMode specifies whether a cassette records or replays HTTP exchanges.
+type CassetteMode string
+
+// CassetteModes specifies the possible modes of a cassette.
+var CassetteModes = struct {
+	Record CassetteMode
+	Replay CassetteMode
+}{
+	Record: "record",
+	Replay: "replay",
+}
+
+// Cassette is an http.RoundTripper recording the HTTP exchanges with the
+// New Relic APIs to a file, or replaying them from it.  API keys are
+// obfuscated from the requests and responses recorded with the same rules as
+// for logging.
+//
+// Requests are replayed in the order recorded, each request being answered
+// with the first response not yet replayed for the same method and URL.
+type Cassette struct {
+	mode      CassetteMode
+	path      string
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*interaction
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+
+	replayed bool
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// NewCassette returns a cassette recording to or replaying from the file
+// at the given path.  Recorded exchanges are written to the file by Save.
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{
+		mode:      mode,
+		path:      path,
+		transport: http.DefaultTransport,
+	}
+
+	switch mode {
+	case CassetteModes.Record:
+		return c, nil
+	case CassetteModes.Replay:
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+
+		return c, nil
+	default:
+		return nil, fmt.Errorf("invalid cassette mode %q", mode)
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.mode == CassetteModes.Replay {
+		return c.replay(req)
+	}
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, &interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: logging.CleanHeader(req.Header),
+			Body:   string(body),
+		},
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     logging.CleanHeader(resp.Header),
+			Body:       string(respBody),
+		},
+	})
+
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, i := range c.interactions {
+		if i.replayed || i.Request.Method != req.Method || i.Request.URL != req.URL.String() {
+			continue
+		}
+
+		i.replayed = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no interaction recorded in cassette %s for %s %s", c.path, req.Method, req.URL)
+}
+
+// Save writes the exchanges recorded to the file of the cassette, creating
+// its directory as needed.  Save does nothing when replaying.
+func (c *Cassette) Save() error {
+	if c.mode != CassetteModes.Record {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path, data, 0644)
+}
+
+// readRequestBody reads the body of a request, leaving it readable again.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+var (
+	testCassettesMu sync.Mutex
+	testCassettes   = map[string]*Cassette{}
+)
+
+// NewTestCassette returns the cassette of the given test, stored in the
+// testdata/cassettes directory of the package under test, and saved once
+// the test completes.  Clients configured several times within a test share
+// the same cassette.  When replaying, tests with no cassette recorded are
+// skipped.
+func NewTestCassette(t *testing.T, mode CassetteMode) *Cassette {
+	testCassettesMu.Lock()
+	defer testCassettesMu.Unlock()
+
+	if c, ok := testCassettes[t.Name()]; ok {
+		return c
+	}
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	path := filepath.Join("testdata", "cassettes", name+".json")
+
+	c, err := NewCassette(path, mode)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skipf("no cassette recorded at %s", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCassettes[t.Name()] = c
+
+	t.Cleanup(func() {
+		testCassettesMu.Lock()
+		delete(testCassettes, t.Name())
+		testCassettesMu.Unlock()
+
+		if err := c.Save(); err != nil {
+			t.Errorf("failed to save cassette: %s", err)
+		}
+	})
+
+	return c
+}
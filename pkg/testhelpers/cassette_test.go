@@ -0,0 +1,99 @@
+// +build unit
+
+package testhelpers
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-License-Key", "0123456789abcdef")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"call":` + strings.Repeat("1", calls) + `,"body":"` + string(body) + `"}`))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+
+	do := func(c *Cassette) string {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/v2/test.json", strings.NewReader("request"))
+		require.NoError(t, err)
+		req.Header.Set("Api-Key", "NRAK-0123456789")
+		req.Header.Set("X-License-Key", "0123456789abcdef")
+
+		resp, err := (&http.Client{Transport: c}).Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	recorder, err := NewCassette(path, CassetteModes.Record)
+	require.NoError(t, err)
+	assert.Equal(t, `{"call":1,"body":"request"}`, do(recorder))
+	assert.Equal(t, `{"call":11,"body":"request"}`, do(recorder))
+	require.NoError(t, recorder.Save())
+
+	// API keys are obfuscated from the cassette
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "NRAK-0123456789")
+	assert.Contains(t, string(data), "NRAK-012*******")
+	assert.NotContains(t, string(data), "0123456789abcdef")
+
+	// Exchanges are replayed in order, without reaching the server
+	player, err := NewCassette(path, CassetteModes.Replay)
+	require.NoError(t, err)
+	assert.Equal(t, `{"call":1,"body":"request"}`, do(player))
+	assert.Equal(t, `{"call":11,"body":"request"}`, do(player))
+	assert.Equal(t, 2, calls)
+
+	_, err = (&http.Client{Transport: player}).Get(ts.URL + "/v2/test.json")
+	assert.Error(t, err)
+}
+
+func TestNewCassette_errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCassette(filepath.Join(t.TempDir(), "missing.json"), CassetteModes.Replay)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	_, err = NewCassette("test.json", CassetteMode("invalid"))
+	assert.Error(t, err)
+}
+
+func TestNewTestCassette_skipsMissing(t *testing.T) {
+	t.Parallel()
+
+	replayed := false
+	t.Run("not recorded", func(t *testing.T) {
+		NewTestCassette(t, CassetteModes.Replay)
+		replayed = true
+	})
+
+	assert.False(t, replayed)
+}
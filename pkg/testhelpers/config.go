@@ -37,6 +37,8 @@ func NewTestConfig(t *testing.T, testServer *httptest.Server) config.Config {
 		cfg.Region().SetRestBaseURL(testServer.URL)
 		cfg.Region().SetSyntheticsBaseURL(testServer.URL)
 		cfg.Region().SetLogsBaseURL(testServer.URL)
+		cfg.Region().SetMetricsBaseURL(testServer.URL)
+		cfg.Region().SetTracesBaseURL(testServer.URL)
 	}
 
 	return cfg
@@ -44,12 +46,23 @@ func NewTestConfig(t *testing.T, testServer *httptest.Server) config.Config {
 
 // NewIntegrationTestConfig grabs environment vars for required fields or skips the test.
 // returns a fully saturated configuration
+//
+// The HTTP exchanges of the test are recorded to or replayed from a cassette
+// when the NEW_RELIC_CASSETTE_MODE environment variable is set, see
+// NewTestCassette.  Replayed tests require no API key.
 func NewIntegrationTestConfig(t *testing.T) config.Config {
 	envPersonalAPIKey := os.Getenv("NEW_RELIC_API_KEY")
 	envInsightsInsertKey := os.Getenv("NEW_RELIC_INSIGHTS_INSERT_KEY")
 	envLicenseKey := os.Getenv("NEW_RELIC_LICENSE_KEY")
 	envRegion := os.Getenv("NEW_RELIC_REGION")
 	envLogLevel := os.Getenv("NEW_RELIC_LOG_LEVEL")
+	envCassetteMode := CassetteMode(os.Getenv(CassetteModeEnv))
+
+	if envPersonalAPIKey == "" && envCassetteMode == CassetteModes.Replay {
+		envPersonalAPIKey = PersonalAPIKey
+		envInsightsInsertKey = "insightsInsertKey"
+		envLicenseKey = LicenseKey
+	}
 
 	if envPersonalAPIKey == "" {
 		t.Skipf("acceptance testing requires NEW_RELIC_API_KEY")
@@ -70,6 +83,10 @@ func NewIntegrationTestConfig(t *testing.T) config.Config {
 	cfg.Timeout = &timeout
 	cfg.UserAgent = UserAgent
 
+	if envCassetteMode != "" {
+		cfg.HTTPTransport = NewTestCassette(t, envCassetteMode)
+	}
+
 	// Auth
 	cfg.PersonalAPIKey = envPersonalAPIKey
 	cfg.InsightsInsertKey = envInsightsInsertKey
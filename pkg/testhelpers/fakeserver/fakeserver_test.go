@@ -0,0 +1,265 @@
+// +build unit
+
+package fakeserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/paging"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/newrelic/newrelic-client-go/pkg/users"
+)
+
+func TestPolicies(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	client := alerts.New(s.Config())
+
+	created, err := client.CreatePolicy(alerts.Policy{
+		Name:               "test policy",
+		IncidentPreference: alerts.IncidentPreferenceTypes.PerPolicy,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	created.Name = "updated policy"
+	updated, err := client.UpdatePolicy(*created)
+	require.NoError(t, err)
+	assert.Equal(t, "updated policy", updated.Name)
+
+	policy, err := client.GetPolicy(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *updated, *policy)
+
+	_, err = client.DeletePolicy(created.ID)
+	require.NoError(t, err)
+
+	_, err = client.GetPolicy(created.ID)
+	assert.IsType(t, &errors.NotFound{}, err)
+
+	_, err = client.DeletePolicy(created.ID)
+	assert.IsType(t, &errors.NotFound{}, err)
+}
+
+func TestPolicies_pagination(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	s.SetPageSize(2)
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		s.Seed(Resources.AlertPolicies, map[string]interface{}{"name": name})
+	}
+
+	client := alerts.New(s.Config())
+
+	policies, err := client.ListPolicies(nil)
+	require.NoError(t, err)
+	assert.Len(t, policies, 5)
+
+	names := []string{}
+	it := client.ListPoliciesIterator(nil, paging.PageSize(2))
+	for it.Next() {
+		names = append(names, it.Value().(alerts.Policy).Name)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+
+	filtered, err := client.ListPolicies(&alerts.ListPoliciesParams{Name: "c"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "c", filtered[0].Name)
+}
+
+func TestNrqlConditions(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	client := alerts.New(s.Config())
+
+	policy, err := client.CreatePolicy(alerts.Policy{Name: "test policy"})
+	require.NoError(t, err)
+
+	_, err = client.CreateNrqlCondition(policy.ID+1, alerts.NrqlCondition{Name: "missing policy"})
+	assert.IsType(t, &errors.NotFound{}, err)
+
+	condition, err := client.CreateNrqlCondition(policy.ID, alerts.NrqlCondition{
+		Name: "test condition",
+		Nrql: alerts.NrqlQuery{Query: "SELECT count(*) FROM Transaction"},
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, condition.ID)
+
+	conditions, err := client.ListNrqlConditions(policy.ID)
+	require.NoError(t, err)
+	require.Len(t, conditions, 1)
+	assert.Equal(t, condition, conditions[0])
+
+	// Conditions are deleted along with their policy
+	_, err = client.DeletePolicy(policy.ID)
+	require.NoError(t, err)
+	assert.Empty(t, s.Objects(Resources.AlertNrqlConditions))
+}
+
+func TestInfrastructureConditions(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	client := alerts.New(s.Config())
+
+	threshold := 0.0
+	created, err := client.CreateInfrastructureCondition(alerts.InfrastructureCondition{
+		Name:       "test condition",
+		PolicyID:   1,
+		Type:       "infra_process_running",
+		Comparison: "equal",
+		Critical:   &alerts.InfrastructureConditionThreshold{Duration: 5, Value: &threshold},
+	})
+	require.NoError(t, err)
+
+	conditions, err := client.ListInfrastructureConditions(1)
+	require.NoError(t, err)
+	assert.Len(t, conditions, 1)
+
+	conditions, err = client.ListInfrastructureConditions(2)
+	require.NoError(t, err)
+	assert.Empty(t, conditions)
+
+	require.NoError(t, client.DeleteInfrastructureCondition(created.ID))
+
+	_, err = client.GetInfrastructureCondition(created.ID)
+	assert.IsType(t, &errors.NotFound{}, err)
+}
+
+func TestSyntheticsMonitors(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	client := synthetics.New(s.Config())
+
+	created, err := client.CreateMonitor(synthetics.Monitor{
+		Name:      "test monitor",
+		Type:      synthetics.MonitorTypes.Ping,
+		Frequency: 15,
+		URI:       "https://example.com",
+		Status:    synthetics.MonitorStatus.Enabled,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+
+	monitor, err := client.GetMonitor(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "test monitor", monitor.Name)
+
+	monitors, err := client.ListMonitors()
+	require.NoError(t, err)
+	assert.Len(t, monitors, 1)
+
+	require.NoError(t, client.DeleteMonitor(created.ID))
+
+	_, err = client.GetMonitor(created.ID)
+	assert.IsType(t, &errors.NotFound{}, err)
+}
+
+func TestNerdGraph(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+
+	nr, err := newrelic.New(newrelic.ConfigPersonalAPIKey("test"), s.Configure)
+	require.NoError(t, err)
+
+	created, err := nr.Alerts.CreatePolicyMutation(1, alerts.AlertsPolicyInput{
+		Name:               "test policy",
+		IncidentPreference: alerts.AlertsIncidentPreferenceTypes.PER_POLICY,
+	})
+	require.NoError(t, err)
+
+	// Policies created through NerdGraph are available through REST
+	policy, err := nr.Alerts.QueryPolicy(1, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, policy)
+	assert.Len(t, s.Objects(Resources.AlertPolicies), 1)
+
+	_, err = nr.Alerts.DeletePolicyMutation(1, created.ID)
+	require.NoError(t, err)
+
+	_, err = nr.Alerts.QueryPolicy(1, created.ID)
+	assert.IsType(t, &errors.NotFound{}, err)
+
+	client := users.New(s.Config())
+	user, err := client.GetUser()
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Server", user.Name)
+}
+
+func TestPoliciesSearch(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	s.SetPageSize(2)
+
+	ids := []string{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		ids = append(ids, s.Seed(Resources.AlertPolicies, map[string]interface{}{"name": name}))
+	}
+
+	client := alerts.New(s.Config())
+
+	policies, err := client.QueryPolicySearch(1, alerts.AlertsPoliciesSearchCriteriaInput{})
+	require.NoError(t, err)
+	assert.Len(t, policies, 5)
+
+	policies, err = client.QueryPolicySearch(1, alerts.AlertsPoliciesSearchCriteriaInput{IDs: []string{ids[1], ids[4]}})
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, "b", policies[0].Name)
+	assert.Equal(t, "e", policies[1].Name)
+}
+
+func TestHandleNerdGraph(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	s.HandleNerdGraph("user", func(variables map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"actor": map[string]interface{}{
+				"user": map[string]interface{}{"name": "Custom"},
+			},
+		}, nil
+	})
+
+	client := users.New(s.Config())
+	user, err := client.GetUser()
+	require.NoError(t, err)
+	assert.Equal(t, "Custom", user.Name)
+}
+
+func TestHandleNerdGraph_callsServer(t *testing.T) {
+	t.Parallel()
+
+	s := New(t)
+	s.HandleNerdGraph("user", func(variables map[string]interface{}) (interface{}, error) {
+		// The resolver can use the store of the server.
+		s.Seed(Resources.AlertPolicies, map[string]interface{}{"name": "Seeded"})
+
+		return map[string]interface{}{
+			"actor": map[string]interface{}{
+				"user": map[string]interface{}{"name": s.Objects(Resources.AlertPolicies)[0]["name"]},
+			},
+		}, nil
+	})
+
+	client := users.New(s.Config())
+	user, err := client.GetUser()
+	require.NoError(t, err)
+	assert.Equal(t, "Seeded", user.Name)
+}
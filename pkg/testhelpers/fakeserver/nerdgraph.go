@@ -0,0 +1,270 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+// NerdGraphResolver resolves a NerdGraph operation given its variables,
+// returning the data of the response.  Errors of type *errors.GraphQLError
+// are returned as is to the client.
+type NerdGraphResolver func(variables map[string]interface{}) (interface{}, error)
+
+type resolver struct {
+	pattern *regexp.Regexp
+	resolve NerdGraphResolver
+	// custom tells the resolvers added with HandleNerdGraph, called without
+	// holding the lock of the server.
+	custom bool
+}
+
+func newResolver(field string, fn NerdGraphResolver) resolver {
+	return resolver{
+		pattern: regexp.MustCompile(`\b` + regexp.QuoteMeta(field) + `\s*[({]`),
+		resolve: fn,
+	}
+}
+
+// HandleNerdGraph resolves the NerdGraph requests selecting the given field,
+// such as "alertsPolicyCreate" or "entitySearch", with the resolver given.
+// Resolvers added take precedence over the ones built in.  They are called
+// without holding the lock of the server, so may call Seed, Objects and
+// SetPageSize, and must guard any state of their own as requests may be
+// served concurrently.
+func (s *Server) HandleNerdGraph(field string, fn NerdGraphResolver) {
+	res := newResolver(field, fn)
+	res.custom = true
+
+	s.resolversMu.Lock()
+	defer s.resolversMu.Unlock()
+
+	s.resolvers = append([]resolver{res}, s.resolvers...)
+}
+
+type nerdGraphRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func (s *Server) serveNerdGraph(w http.ResponseWriter, r *http.Request) {
+	req := nerdGraphRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeNerdGraphError(w, fmt.Errorf("invalid request body: %s", err))
+		return
+	}
+
+	s.resolversMu.Lock()
+	resolvers := s.resolvers
+	s.resolversMu.Unlock()
+
+	for _, res := range resolvers {
+		if !res.pattern.MatchString(req.Query) {
+			continue
+		}
+
+		data, err := s.resolve(res, req.Variables)
+		if err != nil {
+			writeNerdGraphError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+		return
+	}
+
+	writeNerdGraphError(w, fmt.Errorf("no fake resolver for query %q", req.Query))
+}
+
+// resolve calls a resolver, holding the lock of the server for the built in
+// ones.
+func (s *Server) resolve(res resolver, variables map[string]interface{}) (interface{}, error) {
+	if !res.custom {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	return res.resolve(variables)
+}
+
+func writeNerdGraphError(w http.ResponseWriter, err error) {
+	gqlErr, ok := err.(*nrErrors.GraphQLError)
+	if !ok {
+		gqlErr = &nrErrors.GraphQLError{Message: err.Error()}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"errors": []interface{}{gqlErr},
+	})
+}
+
+func (s *Server) nerdGraphResolvers() []resolver {
+	return []resolver{
+		newResolver("alertsPolicyCreate", s.alertsPolicyCreate),
+		newResolver("alertsPolicyUpdate", s.alertsPolicyUpdate),
+		newResolver("alertsPolicyDelete", s.alertsPolicyDelete),
+		newResolver("policiesSearch", s.policiesSearch),
+		newResolver("policy", s.policy),
+		newResolver("user", s.user),
+	}
+}
+
+// nerdGraphPolicy returns the NerdGraph form of a policy of the REST API.
+func nerdGraphPolicy(rec *record, accountID interface{}) map[string]interface{} {
+	if id, ok := rec.data["account_id"]; ok {
+		accountID = id
+	}
+
+	return map[string]interface{}{
+		"id":                 rec.id,
+		"name":               rec.data["name"],
+		"incidentPreference": rec.data["incident_preference"],
+		"accountId":          accountID,
+	}
+}
+
+func policyNotFound(id string) error {
+	return &nrErrors.GraphQLError{
+		Message: fmt.Sprintf("policy %s not found", id),
+		DownstreamResponse: []nrErrors.GraphQLDownstreamResponse{
+			{
+				Message:    "Not Found",
+				Extensions: nrErrors.GraphQLDownstreamExtensions{Code: "BAD_USER_INPUT"},
+			},
+		},
+	}
+}
+
+func (s *Server) alertsPolicyCreate(variables map[string]interface{}) (interface{}, error) {
+	input, _ := variables["policy"].(map[string]interface{})
+
+	rec := s.insert(Resources.AlertPolicies, "", map[string]interface{}{
+		"name":                input["name"],
+		"incident_preference": input["incidentPreference"],
+		"account_id":          variables["accountID"],
+	})
+
+	return map[string]interface{}{
+		"alertsPolicyCreate": nerdGraphPolicy(rec, variables["accountID"]),
+	}, nil
+}
+
+func (s *Server) alertsPolicyUpdate(variables map[string]interface{}) (interface{}, error) {
+	id := valueString(variables["policyID"])
+
+	rec := s.find(Resources.AlertPolicies, id)
+	if rec == nil {
+		return nil, policyNotFound(id)
+	}
+
+	input, _ := variables["policy"].(map[string]interface{})
+	if name, ok := input["name"]; ok {
+		rec.data["name"] = name
+	}
+	if pref, ok := input["incidentPreference"]; ok {
+		rec.data["incident_preference"] = pref
+	}
+
+	return map[string]interface{}{
+		"alertsPolicyUpdate": nerdGraphPolicy(rec, variables["accountID"]),
+	}, nil
+}
+
+func (s *Server) alertsPolicyDelete(variables map[string]interface{}) (interface{}, error) {
+	id := valueString(variables["policyID"])
+
+	if s.remove(Resources.AlertPolicies, id) == nil {
+		return nil, policyNotFound(id)
+	}
+
+	return map[string]interface{}{
+		"alertsPolicyDelete": map[string]interface{}{"id": id},
+	}, nil
+}
+
+// policiesSearch resolves the policies matching the IDs of the search
+// criteria, if any, paginated by a cursor holding the offset of the next page.
+func (s *Server) policiesSearch(variables map[string]interface{}) (interface{}, error) {
+	criteria, _ := variables["searchCriteria"].(map[string]interface{})
+	ids := map[string]bool{}
+	if criteria != nil {
+		list, _ := criteria["ids"].([]interface{})
+		for _, id := range list {
+			ids[valueString(id)] = true
+		}
+	}
+
+	matching := []*record{}
+	for _, rec := range s.collection(Resources.AlertPolicies).records {
+		if len(ids) == 0 || ids[rec.id] {
+			matching = append(matching, rec)
+		}
+	}
+
+	offset := 0
+	if cursor := valueString(variables["cursor"]); cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+
+	policies := []interface{}{}
+	for i := offset; i < len(matching) && i < offset+s.pageSize; i++ {
+		policies = append(policies, nerdGraphPolicy(matching[i], variables["accountID"]))
+	}
+
+	var nextCursor interface{}
+	if offset+s.pageSize < len(matching) {
+		nextCursor = strconv.Itoa(offset + s.pageSize)
+	}
+
+	return accountAlerts(map[string]interface{}{
+		"policiesSearch": map[string]interface{}{
+			"nextCursor": nextCursor,
+			"totalCount": len(matching),
+			"policies":   policies,
+		},
+	}), nil
+}
+
+func (s *Server) policy(variables map[string]interface{}) (interface{}, error) {
+	id := valueString(variables["policyID"])
+
+	rec := s.find(Resources.AlertPolicies, id)
+	if rec == nil {
+		return nil, policyNotFound(id)
+	}
+
+	return accountAlerts(map[string]interface{}{
+		"policy": nerdGraphPolicy(rec, variables["accountID"]),
+	}), nil
+}
+
+func accountAlerts(alerts map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"actor": map[string]interface{}{
+			"account": map[string]interface{}{
+				"alerts": alerts,
+			},
+		},
+	}
+}
+
+// user resolves the user associated with the API key, always the same fake
+// user.
+func (s *Server) user(variables map[string]interface{}) (interface{}, error) {
+	return map[string]interface{}{
+		"actor": map[string]interface{}{
+			"user": map[string]interface{}{
+				"email": "fakeserver@example.com",
+				"id":    1,
+				"name":  "Fake Server",
+			},
+		},
+	}, nil
+}
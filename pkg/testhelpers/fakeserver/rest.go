@@ -0,0 +1,573 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// restCondition describes a kind of alert condition of the REST v2 API,
+// created within a policy.
+type restCondition struct {
+	resource Resource
+	single   string
+	plural   string
+}
+
+var restConditions = []restCondition{
+	{Resources.AlertConditions, "condition", "conditions"},
+	{Resources.AlertNrqlConditions, "nrql_condition", "nrql_conditions"},
+	{Resources.AlertPluginsConditions, "plugins_condition", "plugins_conditions"},
+	{Resources.AlertSyntheticsConditions, "synthetics_condition", "synthetics_conditions"},
+}
+
+func (s *Server) restRoutes() []route {
+	routes := []route{
+		// Alert policies
+		newRoute("GET", restPath+"/alerts_policies.json", s.list(Resources.AlertPolicies, "policies")),
+		newRoute("POST", restPath+"/alerts_policies.json", s.create(Resources.AlertPolicies, "policy")),
+		newRoute("PUT", restPath+`/alerts_policies/(\d+)\.json`, s.update(Resources.AlertPolicies, "policy")),
+		newRoute("DELETE", restPath+`/alerts_policies/(\d+)\.json`, s.deletePolicy),
+
+		// Alert channels
+		newRoute("GET", restPath+"/alerts_channels.json", s.list(Resources.AlertChannels, "channels")),
+		newRoute("POST", restPath+"/alerts_channels.json", s.createChannel),
+		newRoute("DELETE", restPath+`/alerts_channels/(\d+)\.json`, s.delete(Resources.AlertChannels, "channel")),
+		newRoute("PUT", restPath+"/alerts_policy_channels.json", s.updatePolicyChannels),
+		newRoute("DELETE", restPath+"/alerts_policy_channels.json", s.deletePolicyChannel),
+
+		// Alert conditions of location failure, deleted as other conditions
+		newRoute("GET", restPath+`/alerts_location_failure_conditions/policies/(\d+)\.json`, s.listChildren(Resources.AlertLocationFailureConditions, "location_failure_conditions")),
+		newRoute("POST", restPath+`/alerts_location_failure_conditions/policies/(\d+)\.json`, s.createChild(Resources.AlertLocationFailureConditions, "location_failure_condition")),
+		newRoute("PUT", restPath+`/alerts_location_failure_conditions/(\d+)\.json`, s.update(Resources.AlertLocationFailureConditions, "location_failure_condition")),
+		newRoute("DELETE", restPath+`/alerts_conditions/(\d+)\.json`, s.deleteCondition),
+
+		// Alert incidents and events, only ever seeded
+		newRoute("GET", restPath+"/alerts_incidents.json", s.list("alerts_incidents", "incidents")),
+		newRoute("GET", restPath+"/alerts_events.json", s.list("alerts_events", "alert_events")),
+
+		// APM
+		newRoute("GET", restPath+"/applications.json", s.list(Resources.Applications, "applications")),
+		newRoute("GET", restPath+`/applications/(\d+)\.json`, s.get(Resources.Applications, "application")),
+		newRoute("PUT", restPath+`/applications/(\d+)\.json`, s.update(Resources.Applications, "application")),
+		newRoute("DELETE", restPath+`/applications/(\d+)\.json`, s.delete(Resources.Applications, "application")),
+		newRoute("GET", restPath+`/applications/(\d+)/deployments\.json`, s.listChildren(Resources.Deployments, "deployments")),
+		newRoute("POST", restPath+`/applications/(\d+)/deployments\.json`, s.createChild(Resources.Deployments, "deployment")),
+		newRoute("DELETE", restPath+`/applications/\d+/deployments/(\d+)\.json`, s.delete(Resources.Deployments, "deployment")),
+		newRoute("GET", restPath+"/key_transactions.json", s.list(Resources.KeyTransactions, "key_transactions")),
+		newRoute("GET", restPath+`/key_transactions/(\d+)\.json`, s.get(Resources.KeyTransactions, "key_transaction")),
+		newRoute("GET", restPath+"/labels.json", s.list(Resources.Labels, "labels")),
+		newRoute("PUT", restPath+"/labels.json", s.updateLabel),
+		newRoute("DELETE", restPath+`/labels/(.+)\.json`, s.delete(Resources.Labels, "label")),
+
+		// Dashboards
+		newRoute("GET", restPath+"/dashboards.json", s.list(Resources.Dashboards, "dashboards")),
+		newRoute("POST", restPath+"/dashboards.json", s.create(Resources.Dashboards, "dashboard")),
+		newRoute("GET", restPath+`/dashboards/(\d+)\.json`, s.get(Resources.Dashboards, "dashboard")),
+		newRoute("PUT", restPath+`/dashboards/(\d+)\.json`, s.update(Resources.Dashboards, "dashboard")),
+		newRoute("DELETE", restPath+`/dashboards/(\d+)\.json`, s.delete(Resources.Dashboards, "dashboard")),
+
+		// Infrastructure alert conditions
+		newRoute("GET", infrastructurePath+"/alerts/conditions", s.listInfrastructureConditions),
+		newRoute("POST", infrastructurePath+"/alerts/conditions", s.create(Resources.InfrastructureConditions, "data")),
+		newRoute("GET", infrastructurePath+`/alerts/conditions/(\d+)`, s.get(Resources.InfrastructureConditions, "data")),
+		newRoute("PUT", infrastructurePath+`/alerts/conditions/(\d+)`, s.update(Resources.InfrastructureConditions, "data")),
+		newRoute("DELETE", infrastructurePath+`/alerts/conditions/(\d+)`, s.deleteNoContent(Resources.InfrastructureConditions)),
+
+		// Synthetics monitors
+		newRoute("GET", syntheticsPath+"/v4/monitors", s.listMonitors),
+		newRoute("POST", syntheticsPath+"/v4/monitors", s.createMonitor),
+		newRoute("GET", syntheticsPath+"/v4/monitors/([^/]+)", s.getMonitor),
+		newRoute("PUT", syntheticsPath+"/v4/monitors/([^/]+)", s.updateMonitor),
+		newRoute("PATCH", syntheticsPath+"/v4/monitors/([^/]+)", s.updateMonitor),
+		newRoute("DELETE", syntheticsPath+"/v4/monitors/([^/]+)", s.deleteNoContent(Resources.SyntheticsMonitors)),
+	}
+
+	for _, c := range restConditions {
+		path := restPath + "/" + string(c.resource)
+
+		routes = append(routes,
+			newRoute("GET", path+".json", s.listChildren(c.resource, c.plural)),
+			newRoute("POST", path+`/policies/(\d+)\.json`, s.createChild(c.resource, c.single)),
+			newRoute("PUT", path+`/(\d+)\.json`, s.update(c.resource, c.single)),
+		)
+
+		if c.resource != Resources.AlertConditions {
+			routes = append(routes, newRoute("DELETE", path+`/(\d+)\.json`, s.delete(c.resource, c.single)))
+		}
+	}
+
+	return routes
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params []string)
+
+// list serves the objects of a resource matching the filters of the request,
+// paginated with Link headers.  Objects belonging to a parent are filtered by
+// the parent given as the first path parameter or the policy_id parameter.
+func (s *Server) list(resource Resource, plural string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		records := s.filter(resource, r.URL.Query(), "")
+
+		s.writePage(w, r, plural, records)
+	}
+}
+
+func (s *Server) listChildren(resource Resource, plural string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		parent := r.URL.Query().Get("policy_id")
+		if len(params) > 0 {
+			parent = params[0]
+		}
+
+		s.writePage(w, r, plural, s.filter(resource, r.URL.Query(), parent))
+	}
+}
+
+func (s *Server) get(resource Resource, single string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		rec := s.find(resource, params[0])
+		if rec == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", single, params[0]))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{single: rec.data})
+	}
+}
+
+func (s *Server) create(resource Resource, single string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		object, ok := readObject(w, r, single)
+		if !ok {
+			return
+		}
+
+		delete(object, "id")
+		rec := s.insert(resource, "", object)
+
+		writeJSON(w, http.StatusCreated, map[string]interface{}{single: rec.data})
+	}
+}
+
+func (s *Server) createChild(resource Resource, single string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		if resource == Resources.Deployments {
+			if s.find(Resources.Applications, params[0]) == nil {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("application %s not found", params[0]))
+				return
+			}
+		} else if s.find(Resources.AlertPolicies, params[0]) == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("policy %s not found", params[0]))
+			return
+		}
+
+		object, ok := readObject(w, r, single)
+		if !ok {
+			return
+		}
+
+		delete(object, "id")
+		rec := s.insert(resource, params[0], object)
+
+		writeJSON(w, http.StatusCreated, map[string]interface{}{single: rec.data})
+	}
+}
+
+func (s *Server) update(resource Resource, single string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		rec := s.find(resource, params[0])
+		if rec == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", single, params[0]))
+			return
+		}
+
+		object, ok := readObject(w, r, single)
+		if !ok {
+			return
+		}
+
+		rec.update(object)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{single: rec.data})
+	}
+}
+
+func (s *Server) delete(resource Resource, single string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		rec := s.remove(resource, params[0])
+		if rec == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", single, params[0]))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{single: rec.data})
+	}
+}
+
+func (s *Server) deleteNoContent(resource Resource) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		if s.remove(resource, params[0]) == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", params[0]))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deletePolicy deletes a policy along with its conditions.
+func (s *Server) deletePolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	rec := s.remove(Resources.AlertPolicies, params[0])
+	if rec == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("policy %s not found", params[0]))
+		return
+	}
+
+	resources := []Resource{Resources.AlertLocationFailureConditions}
+	for _, c := range restConditions {
+		resources = append(resources, c.resource)
+	}
+
+	for _, resource := range resources {
+		c := s.collection(resource)
+
+		kept := c.records[:0]
+		for _, child := range c.records {
+			if child.parent != rec.id {
+				kept = append(kept, child)
+			}
+		}
+		c.records = kept
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"policy": rec.data})
+}
+
+// deleteCondition deletes an APM or location failure condition, which share
+// the same endpoint.
+func (s *Server) deleteCondition(w http.ResponseWriter, r *http.Request, params []string) {
+	for resource, single := range map[Resource]string{
+		Resources.AlertConditions:                "condition",
+		Resources.AlertLocationFailureConditions: "location_failure_condition",
+	} {
+		if rec := s.remove(resource, params[0]); rec != nil {
+			writeJSON(w, http.StatusOK, map[string]interface{}{single: rec.data})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("condition %s not found", params[0]))
+}
+
+func (s *Server) createChannel(w http.ResponseWriter, r *http.Request, params []string) {
+	object, ok := readObject(w, r, "channel")
+	if !ok {
+		return
+	}
+
+	delete(object, "id")
+	object["links"] = map[string]interface{}{"policy_ids": []interface{}{}}
+	rec := s.insert(Resources.AlertChannels, "", object)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"channels": []interface{}{rec.data}})
+}
+
+func (s *Server) updatePolicyChannels(w http.ResponseWriter, r *http.Request, params []string) {
+	policyID := r.URL.Query().Get("policy_id")
+	if s.find(Resources.AlertPolicies, policyID) == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("policy %s not found", policyID))
+		return
+	}
+
+	channelIDs := []interface{}{}
+	for _, id := range strings.Split(r.URL.Query().Get("channel_ids"), ",") {
+		channel := s.find(Resources.AlertChannels, id)
+		if channel == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("channel %s not found", id))
+			return
+		}
+
+		policyIDs := channelPolicyIDs(channel)
+		if !containsID(policyIDs, policyID) {
+			policyIDs = append(policyIDs, json.Number(policyID))
+		}
+		channel.data["links"] = map[string]interface{}{"policy_ids": policyIDs}
+
+		channelIDs = append(channelIDs, json.Number(id))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"policy": map[string]interface{}{
+			"id":          json.Number(policyID),
+			"channel_ids": channelIDs,
+		},
+	})
+}
+
+func (s *Server) deletePolicyChannel(w http.ResponseWriter, r *http.Request, params []string) {
+	policyID := r.URL.Query().Get("policy_id")
+	channelID := r.URL.Query().Get("channel_id")
+
+	channel := s.find(Resources.AlertChannels, channelID)
+	if channel == nil || !containsID(channelPolicyIDs(channel), policyID) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("channel %s not found in policy %s", channelID, policyID))
+		return
+	}
+
+	policyIDs := []interface{}{}
+	for _, id := range channelPolicyIDs(channel) {
+		if valueString(id) != policyID {
+			policyIDs = append(policyIDs, id)
+		}
+	}
+	channel.data["links"] = map[string]interface{}{"policy_ids": policyIDs}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"channel": channel.data})
+}
+
+func channelPolicyIDs(channel *record) []interface{} {
+	links, _ := channel.data["links"].(map[string]interface{})
+	ids, _ := links["policy_ids"].([]interface{})
+
+	return append([]interface{}{}, ids...)
+}
+
+func containsID(ids []interface{}, id string) bool {
+	for _, v := range ids {
+		if valueString(v) == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateLabel creates or updates a label, identified by its category and
+// name, adding the links given to the existing ones.
+func (s *Server) updateLabel(w http.ResponseWriter, r *http.Request, params []string) {
+	object, ok := readObject(w, r, "label")
+	if !ok {
+		return
+	}
+
+	key := fmt.Sprintf("%s:%s", valueString(object["category"]), valueString(object["name"]))
+	object["key"] = key
+
+	rec := s.find(Resources.Labels, key)
+	if rec == nil {
+		rec = s.insertRecord(Resources.Labels, key, "", map[string]interface{}{})
+	}
+
+	links, _ := rec.data["links"].(map[string]interface{})
+	newLinks, _ := object["links"].(map[string]interface{})
+	merged := map[string]interface{}{}
+
+	for _, kind := range []string{"applications", "servers"} {
+		ids, _ := links[kind].([]interface{})
+		added, _ := newLinks[kind].([]interface{})
+
+		for _, id := range added {
+			if !containsID(ids, valueString(id)) {
+				ids = append(ids, id)
+			}
+		}
+
+		if ids == nil {
+			ids = []interface{}{}
+		}
+		merged[kind] = ids
+	}
+
+	object["links"] = merged
+	for k, v := range object {
+		rec.data[k] = v
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"label": rec.data})
+}
+
+func (s *Server) listInfrastructureConditions(w http.ResponseWriter, r *http.Request, params []string) {
+	policyID := r.URL.Query().Get("policy_id")
+
+	data := []interface{}{}
+	for _, rec := range s.collection(Resources.InfrastructureConditions).records {
+		if policyID == "" || valueString(rec.data["policy_id"]) == policyID {
+			data = append(data, rec.data)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// listMonitors serves the Synthetics monitors, paginated by offset.
+func (s *Server) listMonitors(w http.ResponseWriter, r *http.Request, params []string) {
+	records := s.collection(Resources.SyntheticsMonitors).records
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = s.pageSize
+	}
+
+	monitors := []interface{}{}
+	for i := offset; i < len(records) && i < offset+limit; i++ {
+		monitors = append(monitors, records[i].data)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"monitors": monitors,
+		"count":    len(records),
+	})
+}
+
+func (s *Server) createMonitor(w http.ResponseWriter, r *http.Request, params []string) {
+	object, ok := readObject(w, r, "")
+	if !ok {
+		return
+	}
+
+	id := fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+	s.nextID++
+
+	object["id"] = id
+	s.insertRecord(Resources.SyntheticsMonitors, id, "", object)
+
+	w.Header().Set("Location", s.URL+syntheticsPath+"/v4/monitors/"+id)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) getMonitor(w http.ResponseWriter, r *http.Request, params []string) {
+	rec := s.find(Resources.SyntheticsMonitors, params[0])
+	if rec == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("monitor %s not found", params[0]))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rec.data)
+}
+
+func (s *Server) updateMonitor(w http.ResponseWriter, r *http.Request, params []string) {
+	rec := s.find(Resources.SyntheticsMonitors, params[0])
+	if rec == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("monitor %s not found", params[0]))
+		return
+	}
+
+	object, ok := readObject(w, r, "")
+	if !ok {
+		return
+	}
+
+	rec.update(object)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// filter returns the records of a resource belonging to the given parent, if
+// any, and matching the filter[...] parameters of the query.  The filter[ids]
+// parameter holds a comma separated list of IDs, the other filters match the
+// objects with the field containing the value given.
+func (s *Server) filter(resource Resource, query url.Values, parent string) []*record {
+	records := []*record{}
+
+	for _, rec := range s.collection(resource).records {
+		if parent != "" && rec.parent != parent {
+			continue
+		}
+
+		if matchesFilters(rec, query) {
+			records = append(records, rec)
+		}
+	}
+
+	return records
+}
+
+func matchesFilters(rec *record, query url.Values) bool {
+	for param, values := range query {
+		if !strings.HasPrefix(param, "filter[") || !strings.HasSuffix(param, "]") {
+			continue
+		}
+
+		field := strings.TrimSuffix(strings.TrimPrefix(param, "filter["), "]")
+		value := values[0]
+
+		if field == "ids" {
+			if !containsString(strings.Split(value, ","), rec.id) {
+				return false
+			}
+
+			continue
+		}
+
+		if !strings.Contains(strings.ToLower(valueString(rec.data[field])), strings.ToLower(value)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writePage writes the page of records requested with the page parameter,
+// along with a Link header to the next page, if any.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, plural string, records []*record) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * s.pageSize
+	end := start + s.pageSize
+
+	objects := []interface{}{}
+	for i := start; i < len(records) && i < end; i++ {
+		objects = append(objects, records[i].data)
+	}
+
+	if end < len(records) {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page+1))
+
+		next := fmt.Sprintf("%s%s?%s", s.URL, r.URL.Path, query.Encode())
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{plural: objects})
+}
+
+// readObject decodes the body of the request, returning the object within
+// the given envelope key, or the whole body when empty.
+func readObject(w http.ResponseWriter, r *http.Request, key string) (map[string]interface{}, bool) {
+	body := map[string]interface{}{}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return nil, false
+	}
+
+	if key == "" {
+		return body, true
+	}
+
+	object, ok := body[key].(map[string]interface{})
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("request body has no %q object", key))
+		return nil, false
+	}
+
+	return object, true
+}
@@ -0,0 +1,339 @@
+// Package fakeserver provides a stateful, in-memory fake of the New Relic
+// APIs for testing code built on this client without network access.
+//
+// The fake implements the REST v2 endpoints of Alerts, APM and Dashboards,
+// the Synthetics v4 monitors, the Infrastructure alert conditions and a
+// subset of NerdGraph, storing the objects created in memory:
+//
+//	server := fakeserver.New(t)
+//
+//	client, err := newrelic.New(
+//		newrelic.ConfigPersonalAPIKey("test"),
+//		server.Configure,
+//	)
+//
+// Objects can be seeded and inspected with Seed and Objects, and NerdGraph
+// operations beyond the ones built in added with HandleNerdGraph.
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+const (
+	// DefaultPageSize is the number of objects per page of the paginated
+	// endpoints, unless configured otherwise with SetPageSize.
+	DefaultPageSize = 200
+
+	restPath           = "/v2"
+	infrastructurePath = "/infrastructure/v2"
+	syntheticsPath     = "/synthetics/api"
+	nerdGraphPath      = "/graphql"
+)
+
+// Server is an in-memory fake of the New Relic APIs, served over HTTP.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	nextID      int
+	pageSize    int
+	collections map[string]*collection
+	routes      []route
+
+	// resolversMu guards the resolvers, apart from mu so that the ones added
+	// with HandleNerdGraph are called without holding mu.
+	resolversMu sync.Mutex
+	resolvers   []resolver
+}
+
+// New starts a fake server, closed once the test or benchmark completes.
+func New(t testing.TB) *Server {
+	s := &Server{
+		nextID:      1,
+		pageSize:    DefaultPageSize,
+		collections: map[string]*collection{},
+	}
+
+	s.routes = s.restRoutes()
+	s.resolvers = s.nerdGraphResolvers()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Configure points every API of the configuration to the fake server.  Its
+// signature allows it to be given as a newrelic.ConfigOption.
+func (s *Server) Configure(cfg *config.Config) error {
+	reg, err := region.New("fakeserver", region.Endpoints{
+		Infrastructure: s.URL + infrastructurePath,
+		NerdGraph:      s.URL + nerdGraphPath,
+		Rest:           s.URL + restPath,
+		Synthetics:     s.URL + syntheticsPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cfg.SetRegion(reg)
+}
+
+// Config returns a configuration for clients of the fake server.
+func (s *Server) Config() config.Config {
+	cfg := config.New()
+	cfg.PersonalAPIKey = "fakeserver"
+	cfg.AdminAPIKey = "fakeserver"
+
+	_ = s.Configure(&cfg)
+
+	return cfg
+}
+
+// SetPageSize sets the number of objects per page of the paginated endpoints.
+func (s *Server) SetPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pageSize = size
+}
+
+// Seed stores an object of the given resource, such as APM applications which
+// cannot be created through the API, and returns its ID.  Objects belonging
+// to a parent, such as alert conditions to a policy, are seeded with the ID of
+// the parent.
+func (s *Server) Seed(resource Resource, object map[string]interface{}, parent ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := ""
+	if len(parent) > 0 {
+		p = parent[0]
+	}
+
+	return s.insert(resource, p, object).id
+}
+
+// Objects returns a copy of the objects stored for the given resource.
+func (s *Server) Objects(resource Resource) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects := []map[string]interface{}{}
+	for _, r := range s.collection(resource).records {
+		objects = append(objects, copyObject(r.data))
+	}
+
+	return objects
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == nerdGraphPath {
+		s.serveNerdGraph(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rt := range s.routes {
+		if rt.method != r.Method {
+			continue
+		}
+
+		if match := rt.pattern.FindStringSubmatch(r.URL.Path); match != nil {
+			rt.handler(w, r, match[1:])
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no fake endpoint for %s %s", r.Method, r.URL.Path))
+}
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+func newRoute(method string, pattern string, handler func(w http.ResponseWriter, r *http.Request, params []string)) route {
+	return route{
+		method:  method,
+		pattern: regexp.MustCompile("^" + pattern + "$"),
+		handler: handler,
+	}
+}
+
+// Resource names a kind of object stored by the fake server.
+type Resource string
+
+// Resources are the kinds of objects stored by the fake server.
+var Resources = struct {
+	AlertChannels                  Resource
+	AlertConditions                Resource
+	AlertLocationFailureConditions Resource
+	AlertNrqlConditions            Resource
+	AlertPluginsConditions         Resource
+	AlertPolicies                  Resource
+	AlertSyntheticsConditions      Resource
+	Applications                   Resource
+	Dashboards                     Resource
+	Deployments                    Resource
+	InfrastructureConditions       Resource
+	KeyTransactions                Resource
+	Labels                         Resource
+	SyntheticsMonitors             Resource
+}{
+	AlertChannels:                  "alerts_channels",
+	AlertConditions:                "alerts_conditions",
+	AlertLocationFailureConditions: "alerts_location_failure_conditions",
+	AlertNrqlConditions:            "alerts_nrql_conditions",
+	AlertPluginsConditions:         "alerts_plugins_conditions",
+	AlertPolicies:                  "alerts_policies",
+	AlertSyntheticsConditions:      "alerts_synthetics_conditions",
+	Applications:                   "applications",
+	Dashboards:                     "dashboards",
+	Deployments:                    "deployments",
+	InfrastructureConditions:       "infrastructure_conditions",
+	KeyTransactions:                "key_transactions",
+	Labels:                         "labels",
+	SyntheticsMonitors:             "synthetics_monitors",
+}
+
+type record struct {
+	id     string
+	parent string
+	data   map[string]interface{}
+}
+
+type collection struct {
+	records []*record
+}
+
+func (s *Server) collection(resource Resource) *collection {
+	c, ok := s.collections[string(resource)]
+	if !ok {
+		c = &collection{}
+		s.collections[string(resource)] = c
+	}
+
+	return c
+}
+
+// insert stores an object, assigning it a numeric ID unless it already has
+// an ID, in which case the IDs assigned afterwards are greater.
+func (s *Server) insert(resource Resource, parent string, object map[string]interface{}) *record {
+	data := copyObject(object)
+
+	id := valueString(data["id"])
+	if id == "" || id == "0" {
+		id = strconv.Itoa(s.nextID)
+		data["id"] = s.nextID
+		s.nextID++
+	} else if n, err := strconv.Atoi(id); err == nil && n >= s.nextID {
+		s.nextID = n + 1
+	}
+
+	return s.insertRecord(resource, id, parent, data)
+}
+
+func (s *Server) insertRecord(resource Resource, id string, parent string, data map[string]interface{}) *record {
+	r := &record{
+		id:     id,
+		parent: parent,
+		data:   data,
+	}
+
+	c := s.collection(resource)
+	c.records = append(c.records, r)
+
+	return r
+}
+
+func (s *Server) find(resource Resource, id string) *record {
+	for _, r := range s.collection(resource).records {
+		if r.id == id {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) remove(resource Resource, id string) *record {
+	c := s.collection(resource)
+
+	for i, r := range c.records {
+		if r.id == id {
+			c.records = append(c.records[:i], c.records[i+1:]...)
+			return r
+		}
+	}
+
+	return nil
+}
+
+// update replaces the fields of a stored object with the ones given, keeping
+// its ID.
+func (r *record) update(object map[string]interface{}) {
+	id := r.data["id"]
+
+	for k, v := range object {
+		r.data[k] = v
+	}
+
+	r.data["id"] = id
+}
+
+func copyObject(object map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(object))
+	for k, v := range object {
+		c[k] = v
+	}
+
+	return c
+}
+
+// valueString returns the string form of a JSON value, integral numbers
+// being formatted as integers.
+func valueString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		if value == float64(int64(value)) {
+			return strconv.FormatInt(int64(value), 10)
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, title string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{
+			"title": title,
+		},
+	})
+}
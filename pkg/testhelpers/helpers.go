@@ -2,9 +2,13 @@ package testhelpers
 
 import (
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,15 +17,55 @@ var (
 )
 
 // RandSeq is used to get a string made up of n random lowercase letters.
+// When recording or replaying cassettes, the strings are derived from the
+// calling test so that the requests replayed match the ones recorded.
 func RandSeq(n int) string {
-	rand.Seed(time.Now().UnixNano())
+	r := rand.New(rand.NewSource(randSeed()))
 	b := make([]rune, n)
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[r.Intn(len(letters))]
 	}
 	return string(b)
 }
 
+var (
+	randSeqMu    sync.Mutex
+	randSeqCalls = map[string]int{}
+)
+
+// randSeed returns a seed for RandSeq, unique to the calling test and the
+// number of strings it got so far when using cassettes.
+func randSeed() int64 {
+	if os.Getenv(CassetteModeEnv) == "" {
+		return time.Now().UnixNano()
+	}
+
+	test := ""
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if strings.Contains(frame.Function, ".Test") {
+			test = frame.Function
+			break
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	randSeqMu.Lock()
+	randSeqCalls[test]++
+	calls := randSeqCalls[test]
+	randSeqMu.Unlock()
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s#%d", test, calls)
+
+	return int64(h.Sum64())
+}
+
 // GetTestUserID returns the integer value for a New Relic user ID from the environment
 func GetTestUserID() (int, error) {
 	return getEnvInt("NEW_RELIC_TEST_USER_ID")
@@ -0,0 +1,17 @@
+/*
+Package traces provides a programmatic API for sending distributed tracing data to New Relic.
+
+Spans can be sent to the Trace API in either the New Relic JSON format or
+the Zipkin v2 JSON format, which makes it possible to report traces from
+processes that do not run a New Relic agent.
+
+Authentication
+
+You will need a valid Insights insert key or a license key to communicate with
+the backend New Relic API that provides this functionality.  See the API key
+documentation below for more information on how to locate these keys:
+
+https://docs.newrelic.com/docs/apis/get-started/intro-apis/types-new-relic-api-keys
+
+*/
+package traces
@@ -0,0 +1,48 @@
+// +build integration
+
+package traces
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+)
+
+func Example_basic() {
+	// Initialize the client configuration.  A New Relic License Key is required
+	// to communicate with the backend API.
+	cfg := config.New()
+	cfg.LicenseKey = os.Getenv("NEW_RELIC_LICENSE_KEY")
+
+	// Initialize the client.
+	client := New(cfg)
+
+	now := nrtime.EpochMilliseconds(time.Now())
+
+	blocks := []SpanBlock{
+		{
+			Common: &Common{
+				Attributes: map[string]interface{}{
+					"service.name": "Batch Job",
+				},
+			},
+			Spans: []Span{
+				{
+					ID:         "ee97a5bcb8b4c1ce",
+					TraceID:    "4f9c1ab5a9d8e6b0",
+					Timestamp:  &now,
+					Name:       "process-records",
+					DurationMs: 250,
+				},
+			},
+		},
+	}
+
+	// Post the spans.
+	if err := client.CreateSpans(blocks); err != nil {
+		log.Fatal("error posting spans:", err)
+	}
+}
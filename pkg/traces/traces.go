@@ -0,0 +1,115 @@
+package traces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/batch"
+	"github.com/newrelic/newrelic-client-go/internal/http"
+	"github.com/newrelic/newrelic-client-go/internal/logging"
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+)
+
+const (
+	DefaultBatchWorkers     = 1
+	DefaultBatchSize        = 900
+	DefaultBatchTimeout     = 60 * time.Second
+	DefaultBatchMaxInFlight = batch.DefaultMaxInFlight
+	DefaultBatchRetries     = batch.DefaultRetries
+)
+
+const (
+	dataFormatHeader        = "Data-Format"
+	dataFormatVersionHeader = "Data-Format-Version"
+)
+
+// Traces is used to send spans to the New Relic Trace API.
+type Traces struct {
+	client http.Client
+	config config.Config
+	logger logging.Logger
+
+	// For queue based span handling
+	common             *Common
+	batcher            *batch.Batcher
+	failedBatchHandler FailedBatchHandler
+
+	// These have defaults
+	batchWorkers     int
+	batchSize        int
+	batchTimeout     time.Duration
+	batchMaxInFlight int
+	batchRetries     int
+}
+
+// New is used to create a new Traces client instance.
+func New(cfg config.Config) Traces {
+	cfg.Compression = config.Compression.Gzip
+
+	client := http.NewClient(cfg)
+	if cfg.InsightsInsertKey != "" {
+		client.SetAuthStrategy(&http.LogsInsertKeyAuthorizer{})
+	} else {
+		client.SetAuthStrategy(&http.LicenseKeyAuthorizer{})
+	}
+
+	pkg := Traces{
+		client:           client,
+		config:           cfg,
+		logger:           cfg.GetLogger(),
+		batchWorkers:     DefaultBatchWorkers,
+		batchSize:        DefaultBatchSize,
+		batchTimeout:     DefaultBatchTimeout,
+		batchMaxInFlight: DefaultBatchMaxInFlight,
+		batchRetries:     DefaultBatchRetries,
+	}
+
+	return pkg
+}
+
+// CreateSpans reports one or more blocks of spans in the New Relic format.
+func (t *Traces) CreateSpans(blocks []SpanBlock) error {
+	return t.CreateSpansWithContext(context.Background(), blocks)
+}
+
+// CreateSpansWithContext reports one or more blocks of spans in the New Relic format.
+func (t *Traces) CreateSpansWithContext(ctx context.Context, blocks []SpanBlock) error {
+	if len(blocks) == 0 {
+		return errors.New("traces: CreateSpans: no span blocks provided, nothing to do")
+	}
+
+	return t.send(ctx, DataFormats.NewRelic, blocks)
+}
+
+// CreateZipkinSpans reports one or more spans in the Zipkin v2 format.
+func (t *Traces) CreateZipkinSpans(spans []ZipkinSpan) error {
+	return t.CreateZipkinSpansWithContext(context.Background(), spans)
+}
+
+// CreateZipkinSpansWithContext reports one or more spans in the Zipkin v2 format.
+func (t *Traces) CreateZipkinSpansWithContext(ctx context.Context, spans []ZipkinSpan) error {
+	if len(spans) == 0 {
+		return errors.New("traces: CreateZipkinSpans: no spans provided, nothing to do")
+	}
+
+	return t.send(ctx, DataFormats.Zipkin, spans)
+}
+
+func (t *Traces) send(ctx context.Context, format DataFormat, payload interface{}) error {
+	req, err := t.client.NewRequest("POST", t.config.Region().TracesURL(), nil, payload, nil)
+	if err != nil {
+		return err
+	}
+
+	req.WithContext(ctx)
+	req.SetHeader(dataFormatHeader, string(format))
+	req.SetHeader(dataFormatVersionHeader, format.version())
+
+	_, err = t.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package traces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/internal/batch"
+)
+
+// BatchStats holds the counters of a Traces client in batch mode.
+type BatchStats = batch.Stats
+
+// FailedBatchHandler is called with the spans of a batch that could not be
+// sent to New Relic, along with the error returned by the final attempt.
+// Each span is either a Span or a ZipkinSpan.
+type FailedBatchHandler func(spans []interface{}, err error)
+
+// BatchMode enables the Traces client to accept, queue, and post
+// spans on behalf of the consuming application
+func (t *Traces) BatchMode(ctx context.Context, opts ...BatchConfigOption) (err error) {
+	if t.batcher != nil {
+		return errors.New("the Traces client is already in batch mode")
+	}
+
+	// Loop through config options
+	for _, fn := range opts {
+		if nil != fn {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+	}
+
+	cfg := batch.Config{
+		Workers:     t.batchWorkers,
+		Size:        t.batchSize,
+		Timeout:     t.batchTimeout,
+		MaxInFlight: t.batchMaxInFlight,
+		Retries:     t.batchRetries,
+		OnFailure:   batch.FailureHandler(t.failedBatchHandler),
+	}
+
+	t.batcher = batch.New(t.sendSpans, cfg, t.logger)
+	t.batcher.Start(ctx)
+
+	return nil
+}
+
+type BatchConfigOption func(*Traces) error
+
+// BatchConfigWorkers sets how many background workers will process
+// spans as they are queued
+func BatchConfigWorkers(count int) BatchConfigOption {
+	return func(t *Traces) error {
+		if count <= 0 {
+			return errors.New("traces: invalid worker count specified")
+		}
+
+		t.batchWorkers = count
+
+		return nil
+	}
+}
+
+// BatchConfigQueueSize is how many spans to queue before sending
+// to New Relic.  If this limit is hit before the Timeout, the queue
+// is flushed.
+func BatchConfigQueueSize(size int) BatchConfigOption {
+	return func(t *Traces) error {
+		if size <= 0 {
+			return errors.New("traces: invalid queue size specified")
+		}
+
+		t.batchSize = size
+		return nil
+	}
+}
+
+// BatchConfigTimeout is the maximum amount of time to queue spans
+// before sending to New Relic.  If this is reached before the Size
+// limit, the queue is flushed.
+func BatchConfigTimeout(seconds int) BatchConfigOption {
+	return func(t *Traces) error {
+		if seconds <= 0 {
+			return errors.New("traces: invalid timeout specified")
+		}
+
+		t.batchTimeout = time.Duration(seconds) * time.Second
+		return nil
+	}
+}
+
+// BatchConfigCommon sets the common attributes sent along with every
+// batch of spans in the New Relic format.
+func BatchConfigCommon(common Common) BatchConfigOption {
+	return func(t *Traces) error {
+		t.common = &common
+		return nil
+	}
+}
+
+// BatchConfigMaxInFlight is the maximum number of batches being sent
+// to New Relic at once.  Once reached, queueing blocks until a send
+// completes.
+func BatchConfigMaxInFlight(count int) BatchConfigOption {
+	return func(t *Traces) error {
+		if count <= 0 {
+			return errors.New("traces: invalid max in flight count specified")
+		}
+
+		t.batchMaxInFlight = count
+		return nil
+	}
+}
+
+// BatchConfigRetries is the number of times a batch that failed to
+// send is retried before it is dropped.
+func BatchConfigRetries(count int) BatchConfigOption {
+	return func(t *Traces) error {
+		if count < 0 {
+			return errors.New("traces: invalid retry count specified")
+		}
+
+		t.batchRetries = count
+		return nil
+	}
+}
+
+// BatchConfigFailedBatchHandler sets a handler that is called with every
+// batch of spans that is dropped after failing to send.
+func BatchConfigFailedBatchHandler(handler FailedBatchHandler) BatchConfigOption {
+	return func(t *Traces) error {
+		if handler == nil {
+			return errors.New("traces: invalid failed batch handler specified")
+		}
+
+		t.failedBatchHandler = handler
+		return nil
+	}
+}
+
+// EnqueueSpan queues a span in the New Relic format. Only works in batch mode. If you wish to be able to
+// avoid blocking forever until the span can be queued, provide a ctx with a deadline or timeout as this
+// function will bail when ctx.Done() is closed and return and error.
+func (t *Traces) EnqueueSpan(ctx context.Context, span Span) (err error) {
+	return t.enqueue(ctx, span)
+}
+
+// EnqueueZipkinSpan queues a span in the Zipkin v2 format. Only works in batch mode. If you wish to be able
+// to avoid blocking forever until the span can be queued, provide a ctx with a deadline or timeout as this
+// function will bail when ctx.Done() is closed and return and error.
+func (t *Traces) EnqueueZipkinSpan(ctx context.Context, span ZipkinSpan) (err error) {
+	return t.enqueue(ctx, span)
+}
+
+func (t *Traces) enqueue(ctx context.Context, span interface{}) error {
+	if t.batcher == nil {
+		return errors.New("queueing not enabled for this client")
+	}
+
+	return t.batcher.Enqueue(ctx, span)
+}
+
+// Flush gives the user a way to manually flush the queue in the foreground.
+// This is also used by watchdog when the timer expires.
+func (t *Traces) Flush() error {
+	if t.batcher == nil {
+		return errors.New("queueing not enabled for this client")
+	}
+
+	t.logger.Debug("flushing spans")
+	t.batcher.Flush()
+
+	return nil
+}
+
+// Shutdown stops accepting spans, sends all queued spans to New Relic and
+// waits for the sends to complete.  If ctx is done first, the sends still in
+// flight are canceled and the context's error is returned.
+func (t *Traces) Shutdown(ctx context.Context) error {
+	if t.batcher == nil {
+		return errors.New("queueing not enabled for this client")
+	}
+
+	return t.batcher.Shutdown(ctx)
+}
+
+// BatchStats returns the counters of the spans handled in batch mode.
+func (t *Traces) BatchStats() BatchStats {
+	if t.batcher == nil {
+		return BatchStats{}
+	}
+
+	return t.batcher.Stats()
+}
+
+// sendSpans splits a batch by data format, since each request to
+// the Trace API may only contain spans of a single format.  Both formats are
+// sent even when one of them fails, the *batch.PartialError returned then
+// holding only the spans of the failed format.
+func (t *Traces) sendSpans(ctx context.Context, queued []interface{}) error {
+	spans := []Span{}
+	spanItems := []interface{}{}
+	zipkinSpans := []ZipkinSpan{}
+	zipkinItems := []interface{}{}
+
+	for _, s := range queued {
+		switch span := s.(type) {
+		case Span:
+			spans = append(spans, span)
+			spanItems = append(spanItems, s)
+		case ZipkinSpan:
+			zipkinSpans = append(zipkinSpans, span)
+			zipkinItems = append(zipkinItems, s)
+		}
+	}
+
+	t.logger.Trace(fmt.Sprintf("sendSpans: span count: %d, zipkin span count: %d", len(spans), len(zipkinSpans)))
+
+	var parts []batch.Part
+
+	if len(spans) > 0 {
+		blocks := []SpanBlock{
+			{
+				Common: t.common,
+				Spans:  spans,
+			},
+		}
+
+		parts = append(parts, batch.Part{Items: spanItems, Err: t.CreateSpansWithContext(ctx, blocks)})
+	}
+
+	if len(zipkinSpans) > 0 {
+		parts = append(parts, batch.Part{Items: zipkinItems, Err: t.CreateZipkinSpansWithContext(ctx, zipkinSpans)})
+	}
+
+	return batch.MergePartialErrors(parts...)
+}
@@ -0,0 +1,248 @@
+// +build unit
+
+package traces
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/internal/batch"
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+	mock "github.com/newrelic/newrelic-client-go/pkg/testhelpers"
+)
+
+var (
+	testTimestamp = nrtime.EpochMilliseconds(time.Unix(1600000000, 123000000))
+
+	testSpan = Span{
+		ID:          "span-1",
+		TraceID:     "trace-1",
+		Timestamp:   &testTimestamp,
+		Name:        "/home",
+		ParentID:    "span-0",
+		ServiceName: "Test Service",
+		DurationMs:  52.5,
+		Attributes: map[string]interface{}{
+			"host": "host123.example.com",
+		},
+	}
+
+	testZipkinSpan = ZipkinSpan{
+		TraceID:   "trace-1",
+		ID:        "span-2",
+		ParentID:  "span-1",
+		Name:      "get /users",
+		Kind:      ZipkinSpanKinds.Server,
+		Timestamp: 1600000000123000,
+		Duration:  52500,
+		LocalEndpoint: &ZipkinEndpoint{
+			ServiceName: "Test Service",
+			IPv4:        "127.0.0.1",
+		},
+		Tags: map[string]string{
+			"http.method": "GET",
+		},
+	}
+)
+
+func newTestClient(t *testing.T, handler http.Handler) Traces {
+	ts := httptest.NewServer(handler)
+	tc := mock.NewTestConfig(t, ts)
+
+	return New(tc)
+}
+
+// readBody returns the request body, decompressing it when required.
+func readBody(t *testing.T, r *http.Request) []byte {
+	var reader io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		reader = gz
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestSpanMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	expected := `{
+		"id": "span-1",
+		"trace.id": "trace-1",
+		"timestamp": 1600000000123,
+		"attributes": {
+			"name": "/home",
+			"parent.id": "span-0",
+			"service.name": "Test Service",
+			"duration.ms": 52.5,
+			"host": "host123.example.com"
+		}
+	}`
+
+	actual, err := json.Marshal(testSpan)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(actual))
+
+	// The caller's attributes are left untouched
+	assert.Len(t, testSpan.Attributes, 1)
+}
+
+func TestCreateSpans(t *testing.T) {
+	t.Parallel()
+
+	blocks := []SpanBlock{
+		{
+			Common: &Common{Attributes: map[string]interface{}{"environment": "test"}},
+			Spans:  []Span{testSpan},
+		},
+	}
+
+	expected, err := json.Marshal(blocks)
+	require.NoError(t, err)
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "newrelic", r.Header.Get("Data-Format"))
+		assert.Equal(t, "1", r.Header.Get("Data-Format-Version"))
+		assert.Equal(t, mock.LicenseKey, r.Header.Get("X-License-Key"))
+		assert.JSONEq(t, string(expected), string(readBody(t, r)))
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	err = client.CreateSpans(blocks)
+	assert.NoError(t, err)
+
+	assert.Error(t, client.CreateSpans(nil))
+}
+
+func TestCreateZipkinSpans(t *testing.T) {
+	t.Parallel()
+
+	spans := []ZipkinSpan{testZipkinSpan}
+
+	expected, err := json.Marshal(spans)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "zipkin", r.Header.Get("Data-Format"))
+		assert.Equal(t, "2", r.Header.Get("Data-Format-Version"))
+		assert.Equal(t, "insertKey", r.Header.Get("Api-Key"))
+		assert.JSONEq(t, string(expected), string(readBody(t, r)))
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	tc := mock.NewTestConfig(t, ts)
+	tc.InsightsInsertKey = "insertKey"
+	client := New(tc)
+
+	err = client.CreateZipkinSpans(spans)
+	assert.NoError(t, err)
+
+	assert.Error(t, client.CreateZipkinSpans(nil))
+}
+
+func TestBatchMode(t *testing.T) {
+	t.Parallel()
+
+	formats := make(chan string, 2)
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(t, r)
+
+		switch r.Header.Get("Data-Format") {
+		case "newrelic":
+			blocks := []struct {
+				Common *Common           `json:"common"`
+				Spans  []json.RawMessage `json:"spans"`
+			}{}
+			assert.NoError(t, json.Unmarshal(body, &blocks))
+			assert.Len(t, blocks, 1)
+			assert.Len(t, blocks[0].Spans, 1)
+			assert.Equal(t, "test", blocks[0].Common.Attributes["environment"])
+		case "zipkin":
+			spans := []ZipkinSpan{}
+			assert.NoError(t, json.Unmarshal(body, &spans))
+			assert.Equal(t, []ZipkinSpan{testZipkinSpan}, spans)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		formats <- r.Header.Get("Data-Format")
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := client.BatchMode(ctx,
+		BatchConfigQueueSize(2),
+		BatchConfigCommon(Common{Attributes: map[string]interface{}{"environment": "test"}}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, client.EnqueueSpan(ctx, testSpan))
+	require.NoError(t, client.EnqueueZipkinSpan(ctx, testZipkinSpan))
+
+	received := []string{}
+	for len(received) < 2 {
+		select {
+		case f := <-formats:
+			received = append(received, f)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the batch to be sent")
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"newrelic", "zipkin"}, received)
+}
+
+func TestSendSpans_partialFailure(t *testing.T) {
+	t.Parallel()
+
+	formats := []string{}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		formats = append(formats, r.Header.Get("Data-Format"))
+
+		if r.Header.Get("Data-Format") == "newrelic" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	err := client.sendSpans(context.Background(), []interface{}{testSpan, testZipkinSpan})
+	assert.Equal(t, []string{"newrelic", "zipkin"}, formats)
+
+	var partial *batch.PartialError
+	require.True(t, errors.As(err, &partial))
+	assert.Equal(t, []interface{}{testSpan}, partial.Failed)
+	assert.Error(t, partial.Err)
+}
+
+func TestBatchMode_notEnabled(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, nil)
+
+	assert.Error(t, client.EnqueueSpan(context.Background(), testSpan))
+	assert.Error(t, client.EnqueueZipkinSpan(context.Background(), testZipkinSpan))
+	assert.Error(t, client.Flush())
+}
@@ -0,0 +1,146 @@
+package traces
+
+import (
+	"encoding/json"
+
+	"github.com/newrelic/newrelic-client-go/pkg/nrtime"
+)
+
+// DataFormat specifies the format of the spans sent to the Trace API.
+type DataFormat string
+
+var (
+	// DataFormats specifies the possible span formats accepted by the Trace API.
+	DataFormats = struct {
+		NewRelic DataFormat
+		Zipkin   DataFormat
+	}{
+		NewRelic: "newrelic",
+		Zipkin:   "zipkin",
+	}
+)
+
+// version returns the Data-Format-Version header value for the format.
+func (f DataFormat) version() string {
+	if f == DataFormats.Zipkin {
+		return "2"
+	}
+
+	return "1"
+}
+
+// Span represents a single span in the New Relic format.
+//
+// The Name, ParentID, ServiceName and DurationMs fields are sent to
+// the Trace API as attributes of the span.
+type Span struct {
+	ID          string
+	TraceID     string
+	Timestamp   *nrtime.EpochMilliseconds
+	Name        string
+	ParentID    string
+	ServiceName string
+	DurationMs  float64
+	Attributes  map[string]interface{}
+}
+
+// MarshalJSON is responsible for marshaling the Span type.
+func (s Span) MarshalJSON() ([]byte, error) {
+	attributes := make(map[string]interface{}, len(s.Attributes)+4)
+	for k, v := range s.Attributes {
+		attributes[k] = v
+	}
+
+	if s.Name != "" {
+		attributes["name"] = s.Name
+	}
+
+	if s.ParentID != "" {
+		attributes["parent.id"] = s.ParentID
+	}
+
+	if s.ServiceName != "" {
+		attributes["service.name"] = s.ServiceName
+	}
+
+	if s.DurationMs > 0 {
+		attributes["duration.ms"] = s.DurationMs
+	}
+
+	span := struct {
+		ID         string                    `json:"id"`
+		TraceID    string                    `json:"trace.id"`
+		Timestamp  *nrtime.EpochMilliseconds `json:"timestamp,omitempty"`
+		Attributes map[string]interface{}    `json:"attributes,omitempty"`
+	}{
+		ID:         s.ID,
+		TraceID:    s.TraceID,
+		Timestamp:  s.Timestamp,
+		Attributes: attributes,
+	}
+
+	return json.Marshal(span)
+}
+
+// Common holds the attributes shared by every span within a SpanBlock.
+type Common struct {
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// SpanBlock represents a group of spans, and their common attributes,
+// as sent to the Trace API in the New Relic format.
+type SpanBlock struct {
+	Common *Common `json:"common,omitempty"`
+	Spans  []Span  `json:"spans"`
+}
+
+// ZipkinSpanKind specifies the kind of a Zipkin span.
+type ZipkinSpanKind string
+
+var (
+	// ZipkinSpanKinds specifies the possible kinds of a Zipkin span.
+	ZipkinSpanKinds = struct {
+		Client   ZipkinSpanKind
+		Server   ZipkinSpanKind
+		Producer ZipkinSpanKind
+		Consumer ZipkinSpanKind
+	}{
+		Client:   "CLIENT",
+		Server:   "SERVER",
+		Producer: "PRODUCER",
+		Consumer: "CONSUMER",
+	}
+)
+
+// ZipkinSpan represents a single span in the Zipkin v2 format.
+// Timestamp and Duration are expressed in microseconds.
+type ZipkinSpan struct {
+	TraceID        string             `json:"traceId"`
+	ID             string             `json:"id"`
+	ParentID       string             `json:"parentId,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	Kind           ZipkinSpanKind     `json:"kind,omitempty"`
+	Timestamp      int64              `json:"timestamp,omitempty"`
+	Duration       int64              `json:"duration,omitempty"`
+	Debug          bool               `json:"debug,omitempty"`
+	Shared         bool               `json:"shared,omitempty"`
+	LocalEndpoint  *ZipkinEndpoint    `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *ZipkinEndpoint    `json:"remoteEndpoint,omitempty"`
+	Annotations    []ZipkinAnnotation `json:"annotations,omitempty"`
+	Tags           map[string]string  `json:"tags,omitempty"`
+}
+
+// ZipkinEndpoint represents the network context of a node in a Zipkin trace.
+type ZipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+	IPv4        string `json:"ipv4,omitempty"`
+	IPv6        string `json:"ipv6,omitempty"`
+	Port        int    `json:"port,omitempty"`
+}
+
+// ZipkinAnnotation represents an event that explains latency in a Zipkin span.
+// Timestamp is expressed in microseconds.
+type ZipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
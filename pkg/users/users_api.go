@@ -1,13 +1,24 @@
 // Code generated by tutone: DO NOT EDIT
 package users
 
+import (
+	"context"
+)
+
 // The `User` that is associated with the API key used in this request.
 func (a *Users) GetUser() (*User, error) {
+	return a.GetUserWithContext(context.Background())
+}
+
+// The `User` that is associated with the API key used in this request.
+func (a *Users) GetUserWithContext(
+	ctx context.Context,
+) (*User, error) {
 
 	resp := userResponse{}
 	vars := map[string]interface{}{}
 
-	if err := a.client.NerdGraphQuery(getUserQuery, vars, &resp); err != nil {
+	if err := a.client.NerdGraphQueryWithContext(ctx, getUserQuery, vars, &resp); err != nil {
 		return nil, err
 	}
 